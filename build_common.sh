@@ -25,6 +25,8 @@ NO_COLOUR="\033[0m"
 
 PROJECT_ROOT=$(pwd)
 export GOPATH=$PWD
+# Packages are built from GOPATH, go modules are not used
+export GO111MODULE=off
 DEP_ROOT=$(pwd)/dependencies
 EZMQX_TARGET_ARCH="$(uname -m)"
 EZMQX_INSTALL_PREREQUISITES=false
@@ -74,14 +76,29 @@ install_dependencies() {
     echo -e "${GREEN}Install aml-go done${NO_COLOUR}"
 }
 
+# Clones go package at pinned tag into GOPATH, as go get does not work in GOPATH mode.
+# Usage: get_go_package <import path> <repository URL> <tag>
+get_go_package() {
+    PACKAGE_DIR=$PROJECT_ROOT/src/$1
+    if [ -d "$PACKAGE_DIR" ] ; then
+        echo "$1 folder exist"
+    else
+        git clone --depth 1 --branch $3 $2 $PACKAGE_DIR
+    fi
+}
+
 build_x86_and_64() {
     cd $PROJECT_ROOT/src/go/
     #build ezmqx SDK
     cd ./ezmqx  
     go build -tags="${EZMQX_BUILD_MODE} ${IS_SECURED}" 
     go install
+    #build ezmqx_tns server package
+    cd ../ezmqx_tns
+    go install -tags="${EZMQX_BUILD_MODE} ${IS_SECURED}"
     #build ezmqx_samples
     cd ../ezmqx_samples
+    go build -a -tags="${EZMQX_BUILD_MODE} ${IS_SECURED}" tnsserver.go
     if [ ${EZMQX_WITH_SECURITY} = true ]; then
         go build -a -tags="${EZMQX_BUILD_MODE} ${IS_SECURED}" topicdiscovery.go
         go build -a -tags="${EZMQX_BUILD_MODE} ${IS_SECURED}" publisher_secured.go 
@@ -101,8 +118,12 @@ build_armhf_native() {
     cd ./ezmqx
     CGO_ENABLED=1 GOOS=linux GOARCH=arm go build -tags="${EZMQX_BUILD_MODE} ${IS_SECURED}" 
     CGO_ENABLED=1 GOOS=linux GOARCH=arm go install
+    #build ezmqx_tns server package
+    cd ../ezmqx_tns
+    CGO_ENABLED=1 GOOS=linux GOARCH=arm go install -tags="${EZMQX_BUILD_MODE} ${IS_SECURED}"
     #build ezmqx_samples
     cd ../ezmqx_samples
+    CGO_ENABLED=1 GOOS=linux GOARCH=arm go build -a -tags="${EZMQX_BUILD_MODE} ${IS_SECURED}" tnsserver.go
     if [ ${EZMQX_WITH_SECURITY} = true ]; then
         CGO_ENABLED=1 GOOS=linux GOARCH=arm go build -a -tags="${EZMQX_BUILD_MODE} ${IS_SECURED}" topicdiscovery.go
         CGO_ENABLED=1 GOOS=linux GOARCH=arm go build -a -tags="${EZMQX_BUILD_MODE} ${IS_SECURED}" publisher_secured.go 
@@ -156,6 +177,10 @@ build_ezmqx() {
         cp -r $DEP_ROOT/protocol-ezmq-go/src/ .
         # Copy "aml-go" package to GOPATH
         cp -r $DEP_ROOT/datamodel-aml-go/src/go/aml/ ./src/go/
+        # Get protobuf runtime package for proto data model
+        get_go_package google.golang.org/protobuf https://github.com/protocolbuffers/protobuf-go.git v1.28.1
+        # Get zstd package for payload compression
+        get_go_package github.com/klauspost/compress https://github.com/klauspost/compress.git v1.15.9
         # copy aml libs 
         cd $PROJECT_ROOT/src/go
         mkdir ezmqx_extlibs && cd ezmqx_extlibs
@@ -166,6 +191,8 @@ build_ezmqx() {
     cd $PROJECT_ROOT
     #copy ezmq-plus SDK files
     cp -r ezmqx ./src/go
+    #copy ezmq-plus TNS server
+    cp -r ezmqx_tns ./src/go
     #copy ezmq-plus ezmqx_samples
     cp -r ezmqx_samples ./src/go
     # Copy ezmq-plus unit test cases
@@ -0,0 +1,212 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmqx
+
+import (
+	"container/list"
+	"context"
+	"go.uber.org/zap"
+	"go/aml"
+	"strconv"
+	"sync"
+)
+
+// Structure represents EZMQX AML publisher for multiple topics.
+// All the topics are published on a single socket and port.
+type EZMQXAMLMultiPublisher struct {
+	publisher       *EZMQXPublisher
+	topics          map[string]*EZMQXTopic
+	representations map[string]*aml.Representation
+	mutex           *sync.Mutex
+	isSecured       bool
+}
+
+// Get EZMQX AML multi topic publisher instance.
+// Topics can be added using AddTopic API.
+func GetAMLMultiPublisher(optionalPort int) (*EZMQXAMLMultiPublisher, EZMQXErrorCode) {
+	return GetConfigInstance().GetAMLMultiPublisher(optionalPort)
+}
+
+// Get EZMQX AML multi topic publisher instance.
+// Topics can be added using AddTopic API.
+func (configInstance *EZMQXConfig) GetAMLMultiPublisher(optionalPort int) (*EZMQXAMLMultiPublisher, EZMQXErrorCode) {
+	instance := createAmlMultiPublisher(configInstance.context)
+	result := instance.publisher.initialize(optionalPort)
+	if result != EZMQX_OK {
+		return nil, result
+	}
+	instance.isSecured = false
+	return instance, EZMQX_OK
+}
+
+// Add topic to publisher and register it to TNS.
+func (instance *EZMQXAMLMultiPublisher) AddTopic(topic string, modelInfo EZMQXAmlModelInfo, modelId string) EZMQXErrorCode {
+	return GetErrorCode(instance.AddTopicWithContext(context.Background(), topic, modelInfo, modelId))
+}
+
+// Add topic to publisher and register it to TNS with context.
+// TNS register request is canceled when ctx is done, deadline of ctx is applied to it.
+// On failure, returned error is *EZMQXError which holds error code along with failed operation and cause.
+func (instance *EZMQXAMLMultiPublisher) AddTopicWithContext(ctx context.Context, topic string, modelInfo EZMQXAmlModelInfo, modelId string) error {
+	publisher := instance.publisher
+	if publisher.isTerminated() {
+		Logger.Error("Publisher terminated")
+		return toError(EZMQX_TERMINATED, "AddTopic")
+	}
+	err := validateTopicName("AddTopic", topic)
+	if err != nil {
+		Logger.Error("Topic validation failed", zap.Error(err))
+		return err
+	}
+	instance.mutex.Lock()
+	defer instance.mutex.Unlock()
+	if nil != instance.topics[topic] {
+		Logger.Error("Topic already added", zap.String("Topic: ", topic))
+		return newError(EZMQX_DUPLICATED_TOPIC, "AddTopic", "topic already added "+topic)
+	}
+	context := publisher.context
+	representation, errorCode := getAmlRepresentation(context, modelInfo, modelId)
+	if errorCode != EZMQX_OK {
+		return toError(errorCode, "getAmlRepresentation")
+	}
+	repId, amlCode := representation.GetRepresentationId()
+	if amlCode != aml.AML_OK {
+		Logger.Error("Get representation ID failed")
+		return newError(EZMQX_UNKNOWN_STATE, "AddTopic", "get representation ID failed with error code "+strconv.Itoa(int(amlCode)))
+	}
+	hostEP, errorCode := context.getHostEp(publisher.localPort)
+	if errorCode != EZMQX_OK {
+		Logger.Error("Get hostEP failed")
+		return toError(EZMQX_UNKNOWN_STATE, "getHostEp")
+	}
+	ezmqxTopic := GetEZMQXTopic(topic, repId, instance.isSecured, hostEP)
+	err = publisher.registerTnsTopic(ctx, ezmqxTopic)
+	if err != nil {
+		Logger.Error("Register topic failed", zap.String("Topic: ", topic))
+		return err
+	}
+	instance.topics[topic] = ezmqxTopic
+	instance.representations[topic] = representation
+	return nil
+}
+
+// Remove topic from publisher and unregister it from TNS.
+func (instance *EZMQXAMLMultiPublisher) RemoveTopic(topic string) EZMQXErrorCode {
+	instance.mutex.Lock()
+	defer instance.mutex.Unlock()
+	ezmqxTopic := instance.topics[topic]
+	if nil == ezmqxTopic {
+		Logger.Error("Topic not added", zap.String("Topic: ", topic))
+		return EZMQX_UNKNOWN_TOPIC
+	}
+	delete(instance.topics, topic)
+	delete(instance.representations, topic)
+	instance.publisher.removeCachedTopic(topic)
+	return instance.publisher.unRegisterTopic(ezmqxTopic)
+}
+
+// Publish AMLObject on the given topic for subscribers.
+func (instance *EZMQXAMLMultiPublisher) Publish(topic string, object *aml.AMLObject) EZMQXErrorCode {
+	publisher := instance.publisher
+	if publisher.context.isCtxTerminated() {
+		Logger.Error("Context terminated")
+		instance.Terminate()
+		return EZMQX_TERMINATED
+	}
+	instance.mutex.Lock()
+	representation := instance.representations[topic]
+	instance.mutex.Unlock()
+	if nil == representation {
+		Logger.Error("Topic not added", zap.String("Topic: ", topic))
+		return EZMQX_UNKNOWN_TOPIC
+	}
+	byteData, errorCode := representation.DataToByte(object)
+	if errorCode != aml.AML_OK {
+		Logger.Error("AML DataToByte failed")
+		return EZMQX_UNKNOWN_STATE
+	}
+	return publisher.publishOnTopic(topic, byteData)
+}
+
+// Enable envelope on published messages.
+// Envelope carries per topic sequence number, publisher ID and publish time stamp,
+// which subscribers use to detect lost and duplicated messages.
+// If publisher ID is empty, a unique ID is generated. It should be called before publishing.
+func (instance *EZMQXAMLMultiPublisher) EnableEnvelope(publisherId string) EZMQXErrorCode {
+	return instance.publisher.enableEnvelope(publisherId)
+}
+
+// Get number of messages dropped by send queue.
+// Messages are dropped when queue is full with OVERFLOW_DROP policy or linger is expired on terminate.
+func (instance *EZMQXAMLMultiPublisher) GetDroppedCount() (uint64, EZMQXErrorCode) {
+	return instance.publisher.getDroppedCount(), EZMQX_OK
+}
+
+// Terminate EZMQX AML multi topic publisher.
+// All the added topics will be unregistered from TNS.
+func (instance *EZMQXAMLMultiPublisher) Terminate() EZMQXErrorCode {
+	publisher := instance.publisher
+	if publisher.isTerminated() {
+		Logger.Error("terminate failed : Not initialized")
+		return EZMQX_UNKNOWN_STATE
+	}
+	instance.mutex.Lock()
+	for topic, ezmqxTopic := range instance.topics {
+		if publisher.context.isCtxTnsEnabled() {
+			result := publisher.unRegisterTopic(ezmqxTopic)
+			if result != EZMQX_OK {
+				Logger.Error("Unregister topic: failed", zap.String("Topic: ", topic))
+			}
+		}
+		delete(instance.topics, topic)
+		delete(instance.representations, topic)
+	}
+	instance.mutex.Unlock()
+	return publisher.terminate()
+}
+
+// Check whether publisher is terminated or not.
+func (instance *EZMQXAMLMultiPublisher) IsTerminated() (bool, EZMQXErrorCode) {
+	return instance.publisher.isTerminated(), EZMQX_OK
+}
+
+// Get list of topics that added on this publisher.
+func (instance *EZMQXAMLMultiPublisher) GetTopics() (*list.List, EZMQXErrorCode) {
+	topics := list.New()
+	instance.mutex.Lock()
+	defer instance.mutex.Unlock()
+	for _, ezmqxTopic := range instance.topics {
+		topics.PushBack(*ezmqxTopic)
+	}
+	return topics, EZMQX_OK
+}
+
+// Check whether publisher is secured or not.
+func (instance *EZMQXAMLMultiPublisher) IsSecured() (bool, EZMQXErrorCode) {
+	return instance.isSecured, EZMQX_OK
+}
+
+func createAmlMultiPublisher(context *EZMQXContext) *EZMQXAMLMultiPublisher {
+	var instance *EZMQXAMLMultiPublisher
+	instance = &EZMQXAMLMultiPublisher{}
+	instance.publisher = getPublisher(context, nil)
+	instance.topics = make(map[string]*EZMQXTopic)
+	instance.representations = make(map[string]*aml.Representation)
+	instance.mutex = &sync.Mutex{}
+	return instance
+}
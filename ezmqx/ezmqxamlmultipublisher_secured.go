@@ -0,0 +1,25 @@
+// +build !unsecure
+
+package ezmqx
+
+// Get Secured EZMQX AML multi topic publisher instance.
+//
+// Note:
+// (1) Key should be 40-character string encoded in the Z85 encoding format
+func GetSecuredAMLMultiPublisher(serverPrivateKey string, optionalPort int) (*EZMQXAMLMultiPublisher, EZMQXErrorCode) {
+	return GetConfigInstance().GetSecuredAMLMultiPublisher(serverPrivateKey, optionalPort)
+}
+
+// Get Secured EZMQX AML multi topic publisher instance.
+//
+// Note:
+// (1) Key should be 40-character string encoded in the Z85 encoding format
+func (configInstance *EZMQXConfig) GetSecuredAMLMultiPublisher(serverPrivateKey string, optionalPort int) (*EZMQXAMLMultiPublisher, EZMQXErrorCode) {
+	instance := createAmlMultiPublisher(configInstance.context)
+	result := instance.publisher.initializeSecured(optionalPort, serverPrivateKey)
+	if result != EZMQX_OK {
+		return nil, result
+	}
+	instance.isSecured = true
+	return instance, EZMQX_OK
+}
@@ -19,10 +19,14 @@ package ezmqx
 
 import (
 	"container/list"
+	"context"
 	"go/aml"
-	"go/ezmq"
+	"strconv"
 )
 
+// Callback to get result of asynchronous publish.
+type EZMQXPublishCB func(object *aml.AMLObject, errorCode EZMQXErrorCode)
+
 // Structure represents EZMQX publisher.
 type EZMQXAMLPublisher struct {
 	publisher      *EZMQXPublisher
@@ -32,21 +36,38 @@ type EZMQXAMLPublisher struct {
 
 // Get EZMQX publisher instance.
 func GetAMLPublisher(topic string, modelInfo EZMQXAmlModelInfo, modelId string, optionalPort int) (*EZMQXAMLPublisher, EZMQXErrorCode) {
+	instance, err := GetAMLPublisherWithContext(context.Background(), topic, modelInfo, modelId, optionalPort, nil)
+	return instance, GetErrorCode(err)
+}
+
+// Get EZMQX publisher instance with context.
+// TNS register request is canceled when ctx is done, deadline of ctx is applied to it.
+// On failure, returned error is *EZMQXError which holds error code along with failed operation and cause.
+// Flow control of config [SetFlowControl] is used if optionalFlowControl is nil.
+func GetAMLPublisherWithContext(ctx context.Context, topic string, modelInfo EZMQXAmlModelInfo, modelId string, optionalPort int, optionalFlowControl *EZMQXFlowControl) (*EZMQXAMLPublisher, error) {
+	return GetConfigInstance().GetAMLPublisherWithContext(ctx, topic, modelInfo, modelId, optionalPort, optionalFlowControl)
+}
+
+// Get EZMQX publisher instance with context.
+// TNS register request is canceled when ctx is done, deadline of ctx is applied to it.
+// On failure, returned error is *EZMQXError which holds error code along with failed operation and cause.
+// Flow control of config [SetFlowControl] is used if optionalFlowControl is nil.
+func (configInstance *EZMQXConfig) GetAMLPublisherWithContext(ctx context.Context, topic string, modelInfo EZMQXAmlModelInfo, modelId string, optionalPort int, optionalFlowControl *EZMQXFlowControl) (*EZMQXAMLPublisher, error) {
 	var instance *EZMQXAMLPublisher
 	instance = &EZMQXAMLPublisher{}
-	instance.publisher = getPublisher()
+	instance.publisher = getPublisher(configInstance.context, optionalFlowControl)
 	result := instance.publisher.initialize(optionalPort)
 	if result != EZMQX_OK {
-		return nil, result
+		return nil, toError(result, "initialize")
 	}
-	result = instance.registerTopic(topic, modelInfo, modelId, false)
-	if result != EZMQX_OK {
+	err := instance.registerTopic(ctx, topic, modelInfo, modelId, false)
+	if err != nil {
 		Logger.Error("Register topic failed, stopping ezmq publisher")
-		instance.publisher.ezmqPublisher.Stop()
-		return nil, result
+		instance.publisher.release()
+		return nil, err
 	}
 	instance.isSecured = false
-	return instance, EZMQX_OK
+	return instance, nil
 }
 
 // Publish AMLObject on the socket for subscribers.
@@ -61,26 +82,100 @@ func (instance *EZMQXAMLPublisher) Publish(object *aml.AMLObject) EZMQXErrorCode
 		instance.Terminate()
 		return EZMQX_TERMINATED
 	}
+	return instance.publish(object)
+}
+
+// Publish AMLObject asynchronously.
+// Object is serialized and published on a worker routine of this publisher, in the order of calls.
+// Result is notified through callback [callback can be nil], EZMQX_TERMINATED if publisher is
+// terminated before object is published.
+//
+// Queue size, overflow policy and linger on terminate follow flow control of config.
+// If send queue size is not set, ASYNC_QUEUE_SIZE, OVERFLOW_DROP and ASYNC_LINGER are used.
+// Returns EZMQX_MESSAGE_DROPPED if queue is full with OVERFLOW_DROP policy, callback is not called in that case.
+// Callbacks are called in order of publish on a separate goroutine, callback can call Terminate.
+// Note: Object should not be modified till callback is called.
+func (instance *EZMQXAMLPublisher) PublishAsync(object *aml.AMLObject, callback EZMQXPublishCB) EZMQXErrorCode {
+	publisher := instance.publisher
+	if nil == publisher {
+		Logger.Error("Publisher is null")
+		return EZMQX_UNKNOWN_STATE
+	}
+	if publisher.isTerminated() {
+		Logger.Error("Publisher terminated")
+		return EZMQX_TERMINATED
+	}
+	message := queuedMessage{object: object}
+	if nil != callback {
+		message.callback = func(errorCode EZMQXErrorCode) {
+			callback(object, errorCode)
+		}
+	}
+	return publisher.getAsyncQueue(func(message queuedMessage) EZMQXErrorCode {
+		return instance.publish(message.object)
+	}).push(message)
+}
+
+// Context termination is not handled here, as it is also called on async worker.
+func (instance *EZMQXAMLPublisher) publish(object *aml.AMLObject) EZMQXErrorCode {
+	publisher := instance.publisher
+	if publisher.context.isCtxTerminated() {
+		return EZMQX_TERMINATED
+	}
 	byteData, errorCode := instance.representation.DataToByte(object)
 	if errorCode != aml.AML_OK {
 		Logger.Error("AML DataToByte failed")
 		return EZMQX_UNKNOWN_STATE
 	}
-	ezmqByteData := ezmq.EZMQByteData{byteData}
-	ezmqPublisher := publisher.ezmqPublisher
-	if nil == ezmqPublisher {
-		Logger.Error("Ezmq Publisher failed")
+	return publisher.publish(byteData)
+}
+
+// Publish AML XML string on the socket for subscribers.
+// String is converted to AMLObject using the representation of this publisher.
+func (instance *EZMQXAMLPublisher) PublishXML(amlString string) EZMQXErrorCode {
+	publisher := instance.publisher
+	if nil == publisher {
+		Logger.Error("Publisher is null")
 		return EZMQX_UNKNOWN_STATE
 	}
-	result := ezmqPublisher.PublishOnTopic(publisher.topic.GetName(), ezmqByteData)
-	if result != ezmq.EZMQ_OK {
-		Logger.Error("Publish failed")
+	if publisher.context.isCtxTerminated() {
+		Logger.Error("Context terminated")
+		instance.Terminate()
+		return EZMQX_TERMINATED
+	}
+	amlObject, errorCode := instance.representation.AmlToData(amlString)
+	if errorCode != aml.AML_OK {
+		Logger.Error("AML AmlToData failed")
+		return EZMQX_INVALID_PARAM
+	}
+	return instance.Publish(amlObject)
+}
+
+// Enable envelope on published messages.
+// Envelope carries per topic sequence number, publisher ID and publish time stamp,
+// which subscribers use to detect lost and duplicated messages.
+// If publisher ID is empty, a unique ID is generated. It should be called before publishing.
+func (instance *EZMQXAMLPublisher) EnableEnvelope(publisherId string) EZMQXErrorCode {
+	publisher := instance.publisher
+	if nil == publisher {
 		return EZMQX_UNKNOWN_STATE
 	}
-	return EZMQX_OK
+	return publisher.enableEnvelope(publisherId)
+}
+
+// Get number of messages dropped by send queue and asynchronous publish queue.
+// Messages are dropped when queue is full with OVERFLOW_DROP policy or linger is expired on terminate.
+func (instance *EZMQXAMLPublisher) GetDroppedCount() (uint64, EZMQXErrorCode) {
+	publisher := instance.publisher
+	if nil == publisher {
+		return 0, EZMQX_UNKNOWN_STATE
+	}
+	return publisher.getDroppedCount(), EZMQX_OK
 }
 
 // Terminate EZMQX publisher.
+// Pending asynchronous publish requests are published till linger is expired.
+// Their callbacks may be called after Terminate returns.
 func (instance *EZMQXAMLPublisher) Terminate() EZMQXErrorCode {
 	publisher := instance.publisher
 	if nil == publisher {
@@ -112,45 +207,52 @@ func (instance *EZMQXAMLPublisher) IsSecured() (bool, EZMQXErrorCode) {
 	return instance.isSecured, EZMQX_OK
 }
 
-func (instance *EZMQXAMLPublisher) registerTopic(topic string, modelInfo EZMQXAmlModelInfo, modelId string, isSecured bool) EZMQXErrorCode {
+func (instance *EZMQXAMLPublisher) registerTopic(ctx context.Context, topic string, modelInfo EZMQXAmlModelInfo, modelId string, isSecured bool) error {
 	var errorCode EZMQXErrorCode
 	publisher := instance.publisher
 	context := publisher.context
+	instance.representation, errorCode = getAmlRepresentation(context, modelInfo, modelId)
+	if errorCode != EZMQX_OK {
+		return toError(errorCode, "getAmlRepresentation")
+	}
+	repId, amlCode := instance.representation.GetRepresentationId()
+	if amlCode != aml.AML_OK {
+		Logger.Error("Get representation ID failed")
+		return newError(EZMQX_UNKNOWN_STATE, "registerTopic", "get representation ID failed with error code "+strconv.Itoa(int(amlCode)))
+	}
+	hostEP, errorCode := context.getHostEp(publisher.localPort)
+	if errorCode != EZMQX_OK {
+		Logger.Error("Get hostEP failed")
+		return toError(EZMQX_UNKNOWN_STATE, "getHostEp")
+	}
+	ezmqxTopic := GetEZMQXTopic(topic, repId, isSecured, hostEP)
+	return publisher.registerTopic(ctx, ezmqxTopic)
+}
+
+func getAmlRepresentation(context *EZMQXContext, modelInfo EZMQXAmlModelInfo, modelId string) (*aml.Representation, EZMQXErrorCode) {
 	if AML_MODEL_ID == modelInfo {
-		instance.representation, errorCode = context.getAmlRep(modelId)
+		representation, errorCode := context.getAmlRep(modelId)
 		if errorCode != EZMQX_OK {
 			Logger.Error("Get aml representation failed [AML_MODEL_ID]")
-			return errorCode
+			return nil, errorCode
 		}
+		return representation, EZMQX_OK
 	} else if AML_FILE_PATH == modelInfo {
 		amlFilePath := list.New()
 		amlFilePath.PushBack(modelId)
 		idList, error := context.addAmlRep(*amlFilePath)
 		if error != EZMQX_OK {
 			Logger.Error("Add aml representation failed")
-			return error
+			return nil, error
 		}
 		id := idList.Front().Value.(string)
-		instance.representation, error = context.getAmlRep(id)
+		representation, error := context.getAmlRep(id)
 		if error != EZMQX_OK {
 			Logger.Error("Get aml representation failed [AML_FILE_PATH]")
-			return error
+			return nil, error
 		}
-	} else {
-		Logger.Error("Unknown aml model info")
-		return EZMQX_UNKNOWN_STATE
+		return representation, EZMQX_OK
 	}
-
-	repId, amlCode := instance.representation.GetRepresentationId()
-	if amlCode != aml.AML_OK {
-		Logger.Error("Get representation ID failed")
-		return EZMQX_UNKNOWN_STATE
-	}
-	hostEP, errorCode := context.getHostEp(publisher.localPort)
-	if errorCode != EZMQX_OK {
-		Logger.Error("Get hostEP failed")
-		return EZMQX_UNKNOWN_STATE
-	}
-	ezmqxTopic := GetEZMQXTopic(topic, repId, isSecured, hostEP)
-	return publisher.registerTopic(ezmqxTopic)
+	Logger.Error("Unknown aml model info")
+	return nil, EZMQX_UNKNOWN_STATE
 }
@@ -2,22 +2,32 @@
 
 package ezmqx
 
+import "context"
+
 // Get Secured EZMQX publisher instance.
 //
 // Note:
 // (1) Key should be 40-character string encoded in the Z85 encoding format
 func GetSecuredAMLPublisher(topic string, serverPrivateKey string, modelInfo EZMQXAmlModelInfo, modelId string, optionalPort int) (*EZMQXAMLPublisher, EZMQXErrorCode) {
+	return GetConfigInstance().GetSecuredAMLPublisher(topic, serverPrivateKey, modelInfo, modelId, optionalPort)
+}
+
+// Get Secured EZMQX publisher instance.
+//
+// Note:
+// (1) Key should be 40-character string encoded in the Z85 encoding format
+func (configInstance *EZMQXConfig) GetSecuredAMLPublisher(topic string, serverPrivateKey string, modelInfo EZMQXAmlModelInfo, modelId string, optionalPort int) (*EZMQXAMLPublisher, EZMQXErrorCode) {
 	var instance *EZMQXAMLPublisher
 	instance = &EZMQXAMLPublisher{}
-	instance.publisher = getPublisher()
+	instance.publisher = getPublisher(configInstance.context, nil)
 	result := instance.publisher.initializeSecured(optionalPort, serverPrivateKey)
 	if result != EZMQX_OK {
 		return nil, result
 	}
-	result = instance.registerTopic(topic, modelInfo, modelId, true)
+	result = GetErrorCode(instance.registerTopic(context.Background(), topic, modelInfo, modelId, true))
 	if result != EZMQX_OK {
 		Logger.Error("Register topic failed, stopping ezmq publisher")
-		instance.publisher.ezmqPublisher.Stop()
+		instance.publisher.release()
 		return nil, result
 	}
 	instance.isSecured = true
@@ -19,9 +19,9 @@ package ezmqx
 
 import (
 	"container/list"
+	"context"
 	"go.uber.org/zap"
 	"go/aml"
-	"go/ezmq"
 )
 
 // Callback to get all the subscribed events for a specific topic.
@@ -32,29 +32,50 @@ type EZMQXAmlErrorCB func(topic string, errorCode EZMQXErrorCode)
 
 // Structure represents EZMQX AML subscriber.
 type EZMQXAMLSubscriber struct {
-	subscriber    *EZMQXSubscriber
-	subCallback   EZMQXAmlSubCB
-	errorCallback EZMQXAmlErrorCB
-	isSecured     bool
+	EZMQXTypedSubscriber[*aml.AMLObject]
 }
 
 // Get AML subscriber instance for given topic.
 // It will work, if EZMQX is configured in docker mode.
+// Topic can be a pattern with '*' and '#' wildcards, see HierarchicalQuery of topic discovery.
 func GetAMLSubscriber(topic string, isHierarchical bool, subCallback EZMQXAmlSubCB, errorCallback EZMQXAmlErrorCB) (*EZMQXAMLSubscriber, EZMQXErrorCode) {
-	instance := createAmlSubscriber(subCallback, errorCallback)
-	result := instance.subscriber.initialize(topic, isHierarchical)
-	if result != EZMQX_OK {
-		Logger.Error("initialization failed", zap.Int("Error code:", int(result)))
-		return nil, result
+	instance, err := GetAMLSubscriberWithContext(context.Background(), topic, isHierarchical, subCallback, errorCallback, nil)
+	return instance, GetErrorCode(err)
+}
+
+// Get AML subscriber instance for given topic with context.
+// TNS query request is canceled when ctx is done, deadline of ctx is applied to it.
+// On failure, returned error is *EZMQXError which holds error code along with failed operation and cause.
+// Flow control of config [SetFlowControl] is used if optionalFlowControl is nil.
+func GetAMLSubscriberWithContext(ctx context.Context, topic string, isHierarchical bool, subCallback EZMQXAmlSubCB, errorCallback EZMQXAmlErrorCB, optionalFlowControl *EZMQXFlowControl) (*EZMQXAMLSubscriber, error) {
+	return GetConfigInstance().GetAMLSubscriberWithContext(ctx, topic, isHierarchical, subCallback, errorCallback, optionalFlowControl)
+}
+
+// Get AML subscriber instance for given topic with context.
+// TNS query request is canceled when ctx is done, deadline of ctx is applied to it.
+// On failure, returned error is *EZMQXError which holds error code along with failed operation and cause.
+// Flow control of config [SetFlowControl] is used if optionalFlowControl is nil.
+func (configInstance *EZMQXConfig) GetAMLSubscriberWithContext(ctx context.Context, topic string, isHierarchical bool, subCallback EZMQXAmlSubCB, errorCallback EZMQXAmlErrorCB, optionalFlowControl *EZMQXFlowControl) (*EZMQXAMLSubscriber, error) {
+	instance := createAmlSubscriber(configInstance.context, optionalFlowControl, subCallback, errorCallback)
+	err := instance.subscriber.initialize(ctx, topic, isHierarchical)
+	if err != nil {
+		Logger.Error("initialization failed", zap.Error(err))
+		return nil, err
 	}
 	instance.isSecured = false
-	return instance, result
+	return instance, nil
 }
 
 // Get AML subscriber instance for given topic.
 // It will work, if EZMQX is configured in standalone mode.
 func GetAMLStandAloneSubscriber(topic EZMQXTopic, subCallback EZMQXAmlSubCB, errorCallback EZMQXAmlErrorCB) (*EZMQXAMLSubscriber, EZMQXErrorCode) {
-	instance := createAmlSubscriber(subCallback, errorCallback)
+	return GetConfigInstance().GetAMLStandAloneSubscriber(topic, subCallback, errorCallback)
+}
+
+// Get AML subscriber instance for given topic.
+// It will work, if EZMQX is configured in standalone mode.
+func (configInstance *EZMQXConfig) GetAMLStandAloneSubscriber(topic EZMQXTopic, subCallback EZMQXAmlSubCB, errorCallback EZMQXAmlErrorCB) (*EZMQXAMLSubscriber, EZMQXErrorCode) {
+	instance := createAmlSubscriber(configInstance.context, nil, subCallback, errorCallback)
 	ezmqxTopicList := list.New()
 	ezmqxTopicList.PushBack(topic)
 	result := instance.subscriber.storeTopics(*ezmqxTopicList)
@@ -69,7 +90,13 @@ func GetAMLStandAloneSubscriber(topic EZMQXTopic, subCallback EZMQXAmlSubCB, err
 // Get AML subscriber instance for given topic list.
 // It will work, if EZMQX is configured in standalone mode.
 func GetAMLStandAloneSubscriber1(topics list.List, subCallback EZMQXAmlSubCB, errorCallback EZMQXAmlErrorCB) (*EZMQXAMLSubscriber, EZMQXErrorCode) {
-	instance := createAmlSubscriber(subCallback, errorCallback)
+	return GetConfigInstance().GetAMLStandAloneSubscriber1(topics, subCallback, errorCallback)
+}
+
+// Get AML subscriber instance for given topic list.
+// It will work, if EZMQX is configured in standalone mode.
+func (configInstance *EZMQXConfig) GetAMLStandAloneSubscriber1(topics list.List, subCallback EZMQXAmlSubCB, errorCallback EZMQXAmlErrorCB) (*EZMQXAMLSubscriber, EZMQXErrorCode) {
+	instance := createAmlSubscriber(configInstance.context, nil, subCallback, errorCallback)
 	result := instance.subscriber.storeTopics(topics)
 	if result != EZMQX_OK {
 		Logger.Error("Store topic failed", zap.Int("Error code:", int(result)))
@@ -79,46 +106,53 @@ func GetAMLStandAloneSubscriber1(topics list.List, subCallback EZMQXAmlSubCB, er
 	return instance, result
 }
 
-// Terminate EZMQX AML subscriber.
-func (instance *EZMQXAMLSubscriber) Terminate() EZMQXErrorCode {
-	return instance.subscriber.terminate()
+// Get AML channel subscriber instance for given topic.
+// Messages and errors are received on returned channel instead of callbacks, channel is closed on terminate.
+// It will work, if EZMQX is configured in docker mode.
+//
+// bufferSize: Maximum number of messages buffered on channel.
+// policy: Drop the message or block the receiver when channel is full.
+func GetAMLChannelSubscriber(topic string, isHierarchical bool, bufferSize int, policy EZMQXOverflowPolicy) (*EZMQXChannelSubscriber[*aml.AMLObject], <-chan EZMQXMessage[*aml.AMLObject], EZMQXErrorCode) {
+	instance, messages, err := GetAMLChannelSubscriberWithContext(context.Background(), topic, isHierarchical, bufferSize, policy, nil)
+	return instance, messages, GetErrorCode(err)
+}
+
+// Get AML channel subscriber instance for given topic with context.
+// TNS query request is canceled when ctx is done, deadline of ctx is applied to it.
+// On failure, returned error is *EZMQXError which holds error code along with failed operation and cause.
+// Flow control of config [SetFlowControl] is used if optionalFlowControl is nil.
+func GetAMLChannelSubscriberWithContext(ctx context.Context, topic string, isHierarchical bool, bufferSize int, policy EZMQXOverflowPolicy, optionalFlowControl *EZMQXFlowControl) (*EZMQXChannelSubscriber[*aml.AMLObject], <-chan EZMQXMessage[*aml.AMLObject], error) {
+	return GetConfigInstance().GetAMLChannelSubscriberWithContext(ctx, topic, isHierarchical, bufferSize, policy, optionalFlowControl)
 }
 
-// Check whether subscriber is terminated or not.
-func (instance *EZMQXAMLSubscriber) IsTerminated() (bool, EZMQXErrorCode) {
-	return instance.subscriber.isTerminated(), EZMQX_OK
+// Get AML channel subscriber instance for given topic with context.
+// TNS query request is canceled when ctx is done, deadline of ctx is applied to it.
+// On failure, returned error is *EZMQXError which holds error code along with failed operation and cause.
+// Flow control of config [SetFlowControl] is used if optionalFlowControl is nil.
+func (configInstance *EZMQXConfig) GetAMLChannelSubscriberWithContext(ctx context.Context, topic string, isHierarchical bool, bufferSize int, policy EZMQXOverflowPolicy, optionalFlowControl *EZMQXFlowControl) (*EZMQXChannelSubscriber[*aml.AMLObject], <-chan EZMQXMessage[*aml.AMLObject], error) {
+	return GetChannelSubscriberWithConfig(ctx, configInstance, topic, isHierarchical, getAmlCodecResolver(configInstance.context), bufferSize, policy, optionalFlowControl)
 }
 
-// Get list of topics that subscribed by this subscriber.
-func (instance *EZMQXAMLSubscriber) GetTopics() (*list.List, EZMQXErrorCode) {
-	return instance.subscriber.getTopics(), EZMQX_OK
+// Get AML channel subscriber instance for given topic list.
+// Messages and errors are received on returned channel instead of callbacks, channel is closed on terminate.
+// It will work, if EZMQX is configured in standalone mode.
+func GetAMLStandAloneChannelSubscriber(topics list.List, bufferSize int, policy EZMQXOverflowPolicy) (*EZMQXChannelSubscriber[*aml.AMLObject], <-chan EZMQXMessage[*aml.AMLObject], EZMQXErrorCode) {
+	return GetConfigInstance().GetAMLStandAloneChannelSubscriber(topics, bufferSize, policy)
 }
 
-// Check whether subscriber is secured or not.
-func (instance *EZMQXAMLSubscriber) IsSecured() (bool, EZMQXErrorCode) {
-	return instance.isSecured, EZMQX_OK
+// Get AML channel subscriber instance for given topic list.
+// Messages and errors are received on returned channel instead of callbacks, channel is closed on terminate.
+// It will work, if EZMQX is configured in standalone mode.
+func (configInstance *EZMQXConfig) GetAMLStandAloneChannelSubscriber(topics list.List, bufferSize int, policy EZMQXOverflowPolicy) (*EZMQXChannelSubscriber[*aml.AMLObject], <-chan EZMQXMessage[*aml.AMLObject], EZMQXErrorCode) {
+	return GetStandAloneChannelSubscriberWithConfig(configInstance, topics, getAmlCodecResolver(configInstance.context), bufferSize, policy)
 }
 
-func createAmlSubscriber(subCallback EZMQXAmlSubCB, errorCallback EZMQXAmlErrorCB) *EZMQXAMLSubscriber {
+func createAmlSubscriber(context *EZMQXContext, flowControl *EZMQXFlowControl, subCallback EZMQXAmlSubCB, errorCallback EZMQXAmlErrorCB) *EZMQXAMLSubscriber {
 	var instance *EZMQXAMLSubscriber
 	instance = &EZMQXAMLSubscriber{}
-	instance.subCallback = subCallback
-	instance.errorCallback = errorCallback
-	instance.subscriber = getEZMQXSubscriber()
-	subscriber := instance.subscriber
-	subscriber.internalCB = func(topic string, ezmqMsg ezmq.EZMQMessage) {
-		representation := subscriber.amlRepDic[topic]
-		if 0 == len(topic) || nil == representation {
-			instance.errorCallback(topic, EZMQX_UNKNOWN_TOPIC)
-			return
-		}
-		ezmqByteData := ezmqMsg.(ezmq.EZMQByteData)
-		amlObject, result := representation.ByteToData(ezmqByteData.ByteData)
-		if result != aml.AML_OK {
-			instance.errorCallback(topic, EZMQX_BROKEN_PAYLOAD)
-			return
-		}
-		instance.subCallback(topic, *amlObject)
-	}
+	instance.EZMQXTypedSubscriber = *createTypedSubscriber(context, flowControl, getAmlCodecResolver(context),
+		func(topic string, amlObject *aml.AMLObject) {
+			subCallback(topic, *amlObject)
+		}, EZMQXSubErrorCB(errorCallback))
 	return instance
 }
@@ -11,10 +11,18 @@ import (
 // Note:
 // (1) Key should be 40-character string encoded in the Z85 encoding format
 func GetSecuredAMLSubscriber(topic EZMQXTopic, serverPublicKey string, clientPublicKey string, clientSecretKey string, subCallback EZMQXAmlSubCB, errorCallback EZMQXAmlErrorCB) (*EZMQXAMLSubscriber, EZMQXErrorCode) {
+	return GetConfigInstance().GetSecuredAMLSubscriber(topic, serverPublicKey, clientPublicKey, clientSecretKey, subCallback, errorCallback)
+}
+
+// Get secured AML subscriber instance for given topic.
+//
+// Note:
+// (1) Key should be 40-character string encoded in the Z85 encoding format
+func (configInstance *EZMQXConfig) GetSecuredAMLSubscriber(topic EZMQXTopic, serverPublicKey string, clientPublicKey string, clientSecretKey string, subCallback EZMQXAmlSubCB, errorCallback EZMQXAmlErrorCB) (*EZMQXAMLSubscriber, EZMQXErrorCode) {
 	if !topic.IsSecured() {
 		return nil, EZMQX_INVALID_PARAM
 	}
-	instance := createAmlSubscriber(subCallback, errorCallback)
+	instance := createAmlSubscriber(configInstance.context, nil, subCallback, errorCallback)
 	result := instance.subscriber.storeSecuredTopics(topic, serverPublicKey, clientPublicKey, clientSecretKey)
 	if result != EZMQX_OK {
 		Logger.Error("Store topic failed", zap.Int("Error code:", int(result)))
@@ -29,12 +37,20 @@ func GetSecuredAMLSubscriber(topic EZMQXTopic, serverPublicKey string, clientPub
 // Note:
 // (1) Key should be 40-character string encoded in the Z85 encoding format
 func GetSecuredAMLSubscriber1(topicKeyMap map[EZMQXTopic]string, clientPublicKey string, clientSecretKey string, subCallback EZMQXAmlSubCB, errorCallback EZMQXAmlErrorCB) (*EZMQXAMLSubscriber, EZMQXErrorCode) {
+	return GetConfigInstance().GetSecuredAMLSubscriber1(topicKeyMap, clientPublicKey, clientSecretKey, subCallback, errorCallback)
+}
+
+// Get secured AML subscriber instance for given topic.
+//
+// Note:
+// (1) Key should be 40-character string encoded in the Z85 encoding format
+func (configInstance *EZMQXConfig) GetSecuredAMLSubscriber1(topicKeyMap map[EZMQXTopic]string, clientPublicKey string, clientSecretKey string, subCallback EZMQXAmlSubCB, errorCallback EZMQXAmlErrorCB) (*EZMQXAMLSubscriber, EZMQXErrorCode) {
 	for topic, _ := range topicKeyMap {
 		if !topic.IsSecured() {
 			return nil, EZMQX_INVALID_PARAM
 		}
 	}
-	instance := createAmlSubscriber(subCallback, errorCallback)
+	instance := createAmlSubscriber(configInstance.context, nil, subCallback, errorCallback)
 	var result EZMQXErrorCode = EZMQX_INVALID_PARAM
 	for topic, serverKey := range topicKeyMap {
 		result = instance.subscriber.storeSecuredTopics(topic, serverKey, clientPublicKey, clientSecretKey)
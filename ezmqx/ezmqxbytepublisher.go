@@ -0,0 +1,57 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmqx
+
+import "context"
+
+// Structure represents EZMQX byte publisher.
+type EZMQXBytePublisher struct {
+	EZMQXTypedPublisher[[]byte]
+}
+
+// Get EZMQX byte publisher instance.
+//
+// Data model is registered to TNS as it is, subscribers should
+// understand the format of published bytes from data model.
+func GetBytePublisher(topic string, dataModel string, optionalPort int) (*EZMQXBytePublisher, EZMQXErrorCode) {
+	instance, err := GetBytePublisherWithContext(context.Background(), topic, dataModel, optionalPort, nil)
+	return instance, GetErrorCode(err)
+}
+
+// Get EZMQX byte publisher instance with context.
+// TNS register request is canceled when ctx is done, deadline of ctx is applied to it.
+// On failure, returned error is *EZMQXError which holds error code along with failed operation and cause.
+// Flow control of config [SetFlowControl] is used if optionalFlowControl is nil.
+func GetBytePublisherWithContext(ctx context.Context, topic string, dataModel string, optionalPort int, optionalFlowControl *EZMQXFlowControl) (*EZMQXBytePublisher, error) {
+	return GetConfigInstance().GetBytePublisherWithContext(ctx, topic, dataModel, optionalPort, optionalFlowControl)
+}
+
+// Get EZMQX byte publisher instance with context.
+// TNS register request is canceled when ctx is done, deadline of ctx is applied to it.
+// On failure, returned error is *EZMQXError which holds error code along with failed operation and cause.
+// Flow control of config [SetFlowControl] is used if optionalFlowControl is nil.
+func (configInstance *EZMQXConfig) GetBytePublisherWithContext(ctx context.Context, topic string, dataModel string, optionalPort int, optionalFlowControl *EZMQXFlowControl) (*EZMQXBytePublisher, error) {
+	publisher, err := createTypedPublisher(ctx, configInstance.context, topic, GetByteCodec(dataModel), optionalPort, optionalFlowControl)
+	if err != nil {
+		return nil, err
+	}
+	var instance *EZMQXBytePublisher
+	instance = &EZMQXBytePublisher{}
+	instance.EZMQXTypedPublisher = *publisher
+	return instance, nil
+}
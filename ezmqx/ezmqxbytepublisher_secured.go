@@ -0,0 +1,26 @@
+// +build !unsecure
+
+package ezmqx
+
+// Get Secured EZMQX byte publisher instance.
+//
+// Note:
+// (1) Key should be 40-character string encoded in the Z85 encoding format
+func GetSecuredBytePublisher(topic string, serverPrivateKey string, dataModel string, optionalPort int) (*EZMQXBytePublisher, EZMQXErrorCode) {
+	return GetConfigInstance().GetSecuredBytePublisher(topic, serverPrivateKey, dataModel, optionalPort)
+}
+
+// Get Secured EZMQX byte publisher instance.
+//
+// Note:
+// (1) Key should be 40-character string encoded in the Z85 encoding format
+func (configInstance *EZMQXConfig) GetSecuredBytePublisher(topic string, serverPrivateKey string, dataModel string, optionalPort int) (*EZMQXBytePublisher, EZMQXErrorCode) {
+	publisher, result := createSecuredTypedPublisher(configInstance.context, topic, serverPrivateKey, GetByteCodec(dataModel), optionalPort)
+	if result != EZMQX_OK {
+		return nil, result
+	}
+	var instance *EZMQXBytePublisher
+	instance = &EZMQXBytePublisher{}
+	instance.EZMQXTypedPublisher = *publisher
+	return instance, EZMQX_OK
+}
@@ -0,0 +1,113 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmqx
+
+import (
+	"container/list"
+	"context"
+	"go.uber.org/zap"
+)
+
+// Callback to get all the subscribed events for a specific topic.
+type EZMQXByteSubCB func(topic string, data []byte)
+
+// Callback to get error for the subscribed topic.
+type EZMQXByteErrorCB func(topic string, errorCode EZMQXErrorCode)
+
+// Structure represents EZMQX byte subscriber.
+type EZMQXByteSubscriber struct {
+	EZMQXTypedSubscriber[[]byte]
+}
+
+// Get byte subscriber instance for given topic.
+// It will work, if EZMQX is configured in docker mode.
+func GetByteSubscriber(topic string, isHierarchical bool, subCallback EZMQXByteSubCB, errorCallback EZMQXByteErrorCB) (*EZMQXByteSubscriber, EZMQXErrorCode) {
+	instance, err := GetByteSubscriberWithContext(context.Background(), topic, isHierarchical, subCallback, errorCallback, nil)
+	return instance, GetErrorCode(err)
+}
+
+// Get byte subscriber instance for given topic with context.
+// TNS query request is canceled when ctx is done, deadline of ctx is applied to it.
+// On failure, returned error is *EZMQXError which holds error code along with failed operation and cause.
+// Flow control of config [SetFlowControl] is used if optionalFlowControl is nil.
+func GetByteSubscriberWithContext(ctx context.Context, topic string, isHierarchical bool, subCallback EZMQXByteSubCB, errorCallback EZMQXByteErrorCB, optionalFlowControl *EZMQXFlowControl) (*EZMQXByteSubscriber, error) {
+	return GetConfigInstance().GetByteSubscriberWithContext(ctx, topic, isHierarchical, subCallback, errorCallback, optionalFlowControl)
+}
+
+// Get byte subscriber instance for given topic with context.
+// TNS query request is canceled when ctx is done, deadline of ctx is applied to it.
+// On failure, returned error is *EZMQXError which holds error code along with failed operation and cause.
+// Flow control of config [SetFlowControl] is used if optionalFlowControl is nil.
+func (configInstance *EZMQXConfig) GetByteSubscriberWithContext(ctx context.Context, topic string, isHierarchical bool, subCallback EZMQXByteSubCB, errorCallback EZMQXByteErrorCB, optionalFlowControl *EZMQXFlowControl) (*EZMQXByteSubscriber, error) {
+	instance := createByteSubscriber(configInstance.context, optionalFlowControl, subCallback, errorCallback)
+	err := instance.subscriber.initialize(ctx, topic, isHierarchical)
+	if err != nil {
+		Logger.Error("initialization failed", zap.Error(err))
+		return nil, err
+	}
+	instance.isSecured = false
+	return instance, nil
+}
+
+// Get byte subscriber instance for given topic.
+// It will work, if EZMQX is configured in standalone mode.
+func GetByteStandAloneSubscriber(topic EZMQXTopic, subCallback EZMQXByteSubCB, errorCallback EZMQXByteErrorCB) (*EZMQXByteSubscriber, EZMQXErrorCode) {
+	return GetConfigInstance().GetByteStandAloneSubscriber(topic, subCallback, errorCallback)
+}
+
+// Get byte subscriber instance for given topic.
+// It will work, if EZMQX is configured in standalone mode.
+func (configInstance *EZMQXConfig) GetByteStandAloneSubscriber(topic EZMQXTopic, subCallback EZMQXByteSubCB, errorCallback EZMQXByteErrorCB) (*EZMQXByteSubscriber, EZMQXErrorCode) {
+	instance := createByteSubscriber(configInstance.context, nil, subCallback, errorCallback)
+	ezmqxTopicList := list.New()
+	ezmqxTopicList.PushBack(topic)
+	result := instance.subscriber.storeTopics(*ezmqxTopicList)
+	if result != EZMQX_OK {
+		Logger.Error("Store topic failed", zap.Int("Error code:", int(result)))
+		return nil, result
+	}
+	instance.isSecured = false
+	return instance, result
+}
+
+// Get byte subscriber instance for given topic list.
+// It will work, if EZMQX is configured in standalone mode.
+func GetByteStandAloneSubscriber1(topics list.List, subCallback EZMQXByteSubCB, errorCallback EZMQXByteErrorCB) (*EZMQXByteSubscriber, EZMQXErrorCode) {
+	return GetConfigInstance().GetByteStandAloneSubscriber1(topics, subCallback, errorCallback)
+}
+
+// Get byte subscriber instance for given topic list.
+// It will work, if EZMQX is configured in standalone mode.
+func (configInstance *EZMQXConfig) GetByteStandAloneSubscriber1(topics list.List, subCallback EZMQXByteSubCB, errorCallback EZMQXByteErrorCB) (*EZMQXByteSubscriber, EZMQXErrorCode) {
+	instance := createByteSubscriber(configInstance.context, nil, subCallback, errorCallback)
+	result := instance.subscriber.storeTopics(topics)
+	if result != EZMQX_OK {
+		Logger.Error("Store topic failed", zap.Int("Error code:", int(result)))
+		return nil, result
+	}
+	instance.isSecured = false
+	return instance, result
+}
+
+func createByteSubscriber(context *EZMQXContext, flowControl *EZMQXFlowControl, subCallback EZMQXByteSubCB, errorCallback EZMQXByteErrorCB) *EZMQXByteSubscriber {
+	var instance *EZMQXByteSubscriber
+	instance = &EZMQXByteSubscriber{}
+	instance.EZMQXTypedSubscriber = *createTypedSubscriber(context, flowControl, getByteCodecResolver(),
+		EZMQXTypedSubCB[[]byte](subCallback), EZMQXSubErrorCB(errorCallback))
+	return instance
+}
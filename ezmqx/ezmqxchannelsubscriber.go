@@ -0,0 +1,163 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmqx
+
+import (
+	"container/list"
+	"context"
+	"go.uber.org/zap"
+)
+
+// Structure represents message received by channel subscriber.
+// Error code is other than EZMQX_OK, if message represents error for the subscribed topic.
+type EZMQXMessage[T any] struct {
+	topic     string
+	value     T
+	envelope  *EZMQXEnvelope
+	errorCode EZMQXErrorCode
+}
+
+// Get topic name of message.
+func (message EZMQXMessage[T]) GetTopic() string {
+	return message.topic
+}
+
+// Get decoded value of message, zero value if message represents error.
+func (message EZMQXMessage[T]) GetValue() T {
+	return message.value
+}
+
+// Get envelope of message, nil if publisher does not send envelope.
+func (message EZMQXMessage[T]) GetEnvelope() *EZMQXEnvelope {
+	return message.envelope
+}
+
+// Get error code of message.
+func (message EZMQXMessage[T]) GetErrorCode() EZMQXErrorCode {
+	return message.errorCode
+}
+
+// Structure represents EZMQX subscriber which delivers values of type T on a channel instead of callbacks.
+// Channel is closed on terminate.
+type EZMQXChannelSubscriber[T any] struct {
+	EZMQXTypedSubscriber[T]
+	messages chan EZMQXMessage[T]
+	gate     *channelGate
+}
+
+// Get channel subscriber instance for given topic with context.
+// It will work, if EZMQX is configured in docker mode.
+//
+// bufferSize: Maximum number of messages buffered on channel.
+// policy: Drop the message or block the receiver when channel is full.
+// On failure, returned error is *EZMQXError which holds error code along with failed operation and cause.
+// Flow control of config [SetFlowControl] is used if optionalFlowControl is nil.
+func GetChannelSubscriber[T any](ctx context.Context, topic string, isHierarchical bool, resolver EZMQXCodecResolver[T], bufferSize int, policy EZMQXOverflowPolicy, optionalFlowControl *EZMQXFlowControl) (*EZMQXChannelSubscriber[T], <-chan EZMQXMessage[T], error) {
+	return GetChannelSubscriberWithConfig(ctx, GetConfigInstance(), topic, isHierarchical, resolver, bufferSize, policy, optionalFlowControl)
+}
+
+// Get channel subscriber instance of given config instance for given topic with context.
+// It will work, if EZMQX is configured in docker mode.
+// On failure, returned error is *EZMQXError which holds error code along with failed operation and cause.
+// Flow control of config [SetFlowControl] is used if optionalFlowControl is nil.
+func GetChannelSubscriberWithConfig[T any](ctx context.Context, configInstance *EZMQXConfig, topic string, isHierarchical bool, resolver EZMQXCodecResolver[T], bufferSize int, policy EZMQXOverflowPolicy, optionalFlowControl *EZMQXFlowControl) (*EZMQXChannelSubscriber[T], <-chan EZMQXMessage[T], error) {
+	if nil == resolver || !isValidChannel(bufferSize, policy) {
+		Logger.Error("Invalid resolver, buffer size or overflow policy")
+		return nil, nil, newError(EZMQX_INVALID_PARAM, "GetChannelSubscriber", "invalid resolver, buffer size or overflow policy")
+	}
+	instance := createChannelSubscriber(configInstance.context, optionalFlowControl, resolver, bufferSize, policy)
+	err := instance.subscriber.initialize(ctx, topic, isHierarchical)
+	if err != nil {
+		Logger.Error("initialization failed", zap.Error(err))
+		return nil, nil, err
+	}
+	return instance, instance.messages, nil
+}
+
+// Get channel subscriber instance for given topic list.
+// It will work, if EZMQX is configured in standalone mode.
+func GetStandAloneChannelSubscriber[T any](topics list.List, resolver EZMQXCodecResolver[T], bufferSize int, policy EZMQXOverflowPolicy) (*EZMQXChannelSubscriber[T], <-chan EZMQXMessage[T], EZMQXErrorCode) {
+	return GetStandAloneChannelSubscriberWithConfig(GetConfigInstance(), topics, resolver, bufferSize, policy)
+}
+
+// Get channel subscriber instance of given config instance for given topic list.
+// It will work, if EZMQX is configured in standalone mode.
+func GetStandAloneChannelSubscriberWithConfig[T any](configInstance *EZMQXConfig, topics list.List, resolver EZMQXCodecResolver[T], bufferSize int, policy EZMQXOverflowPolicy) (*EZMQXChannelSubscriber[T], <-chan EZMQXMessage[T], EZMQXErrorCode) {
+	if nil == resolver || !isValidChannel(bufferSize, policy) {
+		Logger.Error("Invalid resolver, buffer size or overflow policy")
+		return nil, nil, EZMQX_INVALID_PARAM
+	}
+	instance := createChannelSubscriber(configInstance.context, nil, resolver, bufferSize, policy)
+	result := instance.subscriber.storeTopics(topics)
+	if result != EZMQX_OK {
+		Logger.Error("Store topic failed", zap.Int("Error code:", int(result)))
+		return nil, nil, result
+	}
+	return instance, instance.messages, result
+}
+
+// Terminate EZMQX channel subscriber, channel is closed after terminate.
+func (instance *EZMQXChannelSubscriber[T]) Terminate() EZMQXErrorCode {
+	instance.gate.stop()
+	result := instance.subscriber.terminate()
+	instance.gate.close(func() {
+		close(instance.messages)
+	})
+	return result
+}
+
+// Get number of messages dropped by receive queue and channel.
+// Messages are dropped when queue or channel is full with OVERFLOW_DROP policy or linger is expired on terminate.
+func (instance *EZMQXChannelSubscriber[T]) GetDroppedCount() (uint64, EZMQXErrorCode) {
+	return instance.subscriber.getDroppedCount() + instance.gate.getDroppedCount(), EZMQX_OK
+}
+
+func createChannelSubscriber[T any](context *EZMQXContext, flowControl *EZMQXFlowControl, resolver EZMQXCodecResolver[T], bufferSize int, policy EZMQXOverflowPolicy) *EZMQXChannelSubscriber[T] {
+	var instance *EZMQXChannelSubscriber[T]
+	instance = &EZMQXChannelSubscriber[T]{}
+	instance.messages = make(chan EZMQXMessage[T], bufferSize)
+	instance.gate = getChannelGate(policy)
+	instance.EZMQXTypedSubscriber = *createTypedSubscriber(context, flowControl, resolver,
+		func(topic string, value T) {
+			instance.sendMessage(EZMQXMessage[T]{topic: topic, value: value, envelope: instance.subscriber.envelope, errorCode: EZMQX_OK})
+		},
+		func(topic string, errorCode EZMQXErrorCode) {
+			instance.sendMessage(EZMQXMessage[T]{topic: topic, envelope: instance.subscriber.envelope, errorCode: errorCode})
+		})
+	return instance
+}
+
+func (instance *EZMQXChannelSubscriber[T]) sendMessage(message EZMQXMessage[T]) {
+	gate := instance.gate
+	if !gate.enter() {
+		return
+	}
+	defer gate.leave()
+	if gate.policy == OVERFLOW_BLOCK {
+		select {
+		case instance.messages <- message:
+		case <-gate.doneChan:
+		}
+		return
+	}
+	select {
+	case instance.messages <- message:
+	default:
+		gate.drop()
+	}
+}
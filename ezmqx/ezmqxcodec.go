@@ -0,0 +1,316 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmqx
+
+import (
+	"encoding/json"
+	"go.uber.org/zap"
+	"go/aml"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// Interface represents codec which converts values of type T to and from published bytes.
+// Implement this interface to publish and subscribe a new format using typed publisher and subscriber.
+type EZMQXCodec[T any] interface {
+	// Get data model of encoded bytes, it is registered to TNS for published topics.
+	GetDataModel() string
+
+	// Encode value to bytes.
+	Encode(value T) ([]byte, EZMQXErrorCode)
+
+	// Decode bytes to value.
+	Decode(data []byte) (T, EZMQXErrorCode)
+}
+
+// Resolves codec for data model of subscribed topic.
+type EZMQXCodecResolver[T any] func(dataModel string) (EZMQXCodec[T], EZMQXErrorCode)
+
+// Get codec resolver which resolves given codec for its data model only.
+// Topics of other data models are rejected with EZMQX_INVALID_PARAM.
+func GetCodecResolver[T any](codec EZMQXCodec[T]) EZMQXCodecResolver[T] {
+	return func(dataModel string) (EZMQXCodec[T], EZMQXErrorCode) {
+		if dataModel != codec.GetDataModel() {
+			Logger.Error("Data model mismatch")
+			return nil, EZMQX_INVALID_PARAM
+		}
+		return codec, EZMQX_OK
+	}
+}
+
+// Structure represents codec of AMLObject using AML representation.
+type EZMQXAMLCodec struct {
+	representation *aml.Representation
+	repId          string
+}
+
+// Structure represents codec of AML XML string using AML representation.
+type EZMQXXMLCodec struct {
+	amlCodec *EZMQXAMLCodec
+}
+
+// Get AML codec for given AML model ID.
+func GetAMLCodec(amlModelId string) (EZMQXCodec[*aml.AMLObject], EZMQXErrorCode) {
+	return GetConfigInstance().GetAMLCodec(amlModelId)
+}
+
+// Get AML codec for given AML model ID.
+// It can be used as codec resolver of AML topics.
+func (configInstance *EZMQXConfig) GetAMLCodec(amlModelId string) (EZMQXCodec[*aml.AMLObject], EZMQXErrorCode) {
+	return getAmlCodecResolver(configInstance.context)(amlModelId)
+}
+
+// Get XML codec for given AML model ID.
+func GetXMLCodec(amlModelId string) (EZMQXCodec[string], EZMQXErrorCode) {
+	return GetConfigInstance().GetXMLCodec(amlModelId)
+}
+
+// Get XML codec for given AML model ID.
+// It can be used as codec resolver of AML topics.
+func (configInstance *EZMQXConfig) GetXMLCodec(amlModelId string) (EZMQXCodec[string], EZMQXErrorCode) {
+	return getXmlCodecResolver(configInstance.context)(amlModelId)
+}
+
+// Get AML model ID of representation.
+func (instance *EZMQXAMLCodec) GetDataModel() string {
+	return instance.repId
+}
+
+// Encode AMLObject to bytes.
+func (instance *EZMQXAMLCodec) Encode(object *aml.AMLObject) ([]byte, EZMQXErrorCode) {
+	byteData, errorCode := instance.representation.DataToByte(object)
+	if errorCode != aml.AML_OK {
+		Logger.Error("AML DataToByte failed")
+		return nil, EZMQX_INVALID_PARAM
+	}
+	return byteData, EZMQX_OK
+}
+
+// Decode bytes to AMLObject.
+func (instance *EZMQXAMLCodec) Decode(data []byte) (*aml.AMLObject, EZMQXErrorCode) {
+	amlObject, errorCode := instance.representation.ByteToData(data)
+	if errorCode != aml.AML_OK {
+		return nil, EZMQX_BROKEN_PAYLOAD
+	}
+	return amlObject, EZMQX_OK
+}
+
+// Get AML model ID of representation.
+func (instance *EZMQXXMLCodec) GetDataModel() string {
+	return instance.amlCodec.GetDataModel()
+}
+
+// Encode AML XML string to bytes.
+func (instance *EZMQXXMLCodec) Encode(amlString string) ([]byte, EZMQXErrorCode) {
+	amlObject, errorCode := instance.amlCodec.representation.AmlToData(amlString)
+	if errorCode != aml.AML_OK {
+		Logger.Error("AML AmlToData failed")
+		return nil, EZMQX_INVALID_PARAM
+	}
+	return instance.amlCodec.Encode(amlObject)
+}
+
+// Decode bytes to AML XML string.
+func (instance *EZMQXXMLCodec) Decode(data []byte) (string, EZMQXErrorCode) {
+	amlObject, result := instance.amlCodec.Decode(data)
+	if result != EZMQX_OK {
+		return "", result
+	}
+	amlString, errorCode := instance.amlCodec.representation.DataToAml(amlObject)
+	if errorCode != aml.AML_OK {
+		return "", EZMQX_BROKEN_PAYLOAD
+	}
+	return amlString, EZMQX_OK
+}
+
+func getAmlCodecResolver(context *EZMQXContext) EZMQXCodecResolver[*aml.AMLObject] {
+	return func(dataModel string) (EZMQXCodec[*aml.AMLObject], EZMQXErrorCode) {
+		instance, result := getAmlCodec(context, dataModel)
+		if result != EZMQX_OK {
+			return nil, result
+		}
+		return instance, EZMQX_OK
+	}
+}
+
+func getXmlCodecResolver(context *EZMQXContext) EZMQXCodecResolver[string] {
+	return func(dataModel string) (EZMQXCodec[string], EZMQXErrorCode) {
+		amlCodec, result := getAmlCodec(context, dataModel)
+		if result != EZMQX_OK {
+			return nil, result
+		}
+		var instance *EZMQXXMLCodec
+		instance = &EZMQXXMLCodec{}
+		instance.amlCodec = amlCodec
+		return instance, EZMQX_OK
+	}
+}
+
+func getAmlCodec(context *EZMQXContext, amlModelId string) (*EZMQXAMLCodec, EZMQXErrorCode) {
+	representation, result := context.getAmlRep(amlModelId)
+	if result != EZMQX_OK {
+		Logger.Error("Get aml representation failed")
+		return nil, result
+	}
+	repId, errorCode := representation.GetRepresentationId()
+	if errorCode != aml.AML_OK {
+		Logger.Error("Get representation ID failed", zap.Int("Error code:", int(errorCode)))
+		return nil, EZMQX_UNKNOWN_STATE
+	}
+	var instance *EZMQXAMLCodec
+	instance = &EZMQXAMLCodec{}
+	instance.representation = representation
+	instance.repId = repId
+	return instance, EZMQX_OK
+}
+
+// Structure represents codec of byte data, bytes are published as they are.
+type EZMQXByteCodec struct {
+	dataModel string
+}
+
+// Structure represents codec of values marshalled using encoding/json.
+type EZMQXJSONCodec struct {
+}
+
+// Structure represents codec of protobuf messages of a message descriptor.
+type EZMQXProtoCodec struct {
+	descriptor protoreflect.MessageDescriptor
+}
+
+// Get byte codec for given data model.
+func GetByteCodec(dataModel string) EZMQXCodec[[]byte] {
+	var instance *EZMQXByteCodec
+	instance = &EZMQXByteCodec{}
+	instance.dataModel = dataModel
+	return instance
+}
+
+// Get data model of byte data.
+func (instance *EZMQXByteCodec) GetDataModel() string {
+	return instance.dataModel
+}
+
+// Encode byte data, data is returned as it is.
+func (instance *EZMQXByteCodec) Encode(data []byte) ([]byte, EZMQXErrorCode) {
+	return data, EZMQX_OK
+}
+
+// Decode byte data, data is returned as it is.
+func (instance *EZMQXByteCodec) Decode(data []byte) ([]byte, EZMQXErrorCode) {
+	return data, EZMQX_OK
+}
+
+// Get JSON codec, its data model is JSON_DATA_MODEL.
+// Values are decoded into interface{}, json.RawMessage is encoded as it is.
+func GetJSONCodec() EZMQXCodec[interface{}] {
+	var instance *EZMQXJSONCodec
+	instance = &EZMQXJSONCodec{}
+	return instance
+}
+
+// Get JSON data model.
+func (instance *EZMQXJSONCodec) GetDataModel() string {
+	return JSON_DATA_MODEL
+}
+
+// Encode value to JSON bytes.
+func (instance *EZMQXJSONCodec) Encode(value interface{}) ([]byte, EZMQXErrorCode) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		Logger.Error("JSON marshal failed")
+		return nil, EZMQX_INVALID_PARAM
+	}
+	return data, EZMQX_OK
+}
+
+// Decode JSON bytes to value.
+func (instance *EZMQXJSONCodec) Decode(data []byte) (interface{}, EZMQXErrorCode) {
+	var value interface{}
+	err := json.Unmarshal(data, &value)
+	if err != nil {
+		return nil, EZMQX_BROKEN_PAYLOAD
+	}
+	return value, EZMQX_OK
+}
+
+// Get protobuf codec for given message name.
+// Message name should be fully-qualified name of message added using AddProtoModel API.
+func GetProtoCodec(messageName string) (EZMQXCodec[proto.Message], EZMQXErrorCode) {
+	return GetConfigInstance().GetProtoCodec(messageName)
+}
+
+// Get protobuf codec for given message name.
+// It can be used as codec resolver of protobuf topics.
+func (configInstance *EZMQXConfig) GetProtoCodec(messageName string) (EZMQXCodec[proto.Message], EZMQXErrorCode) {
+	return getProtoCodecResolver(configInstance.context)(messageName)
+}
+
+// Get fully-qualified message name.
+func (instance *EZMQXProtoCodec) GetDataModel() string {
+	return string(instance.descriptor.FullName())
+}
+
+// Encode protobuf message to bytes, message type should be same as codec message.
+func (instance *EZMQXProtoCodec) Encode(message proto.Message) ([]byte, EZMQXErrorCode) {
+	if nil == message || message.ProtoReflect().Descriptor().FullName() != instance.descriptor.FullName() {
+		Logger.Error("Message type mismatch")
+		return nil, EZMQX_INVALID_PARAM
+	}
+	data, err := proto.Marshal(message)
+	if err != nil {
+		Logger.Error("Proto marshal failed")
+		return nil, EZMQX_INVALID_PARAM
+	}
+	return data, EZMQX_OK
+}
+
+// Decode bytes to dynamic message of codec message type.
+func (instance *EZMQXProtoCodec) Decode(data []byte) (proto.Message, EZMQXErrorCode) {
+	message := dynamicpb.NewMessage(instance.descriptor)
+	err := proto.Unmarshal(data, message)
+	if err != nil {
+		return nil, EZMQX_BROKEN_PAYLOAD
+	}
+	return message, EZMQX_OK
+}
+
+// Resolves byte codec for any data model of subscribed topic.
+func getByteCodecResolver() EZMQXCodecResolver[[]byte] {
+	return func(dataModel string) (EZMQXCodec[[]byte], EZMQXErrorCode) {
+		if 0 == len(dataModel) {
+			Logger.Error("Data model is empty")
+			return nil, EZMQX_INVALID_PARAM
+		}
+		return GetByteCodec(dataModel), EZMQX_OK
+	}
+}
+
+func getProtoCodecResolver(context *EZMQXContext) EZMQXCodecResolver[proto.Message] {
+	return func(dataModel string) (EZMQXCodec[proto.Message], EZMQXErrorCode) {
+		descriptor, result := context.getProtoDesc(dataModel)
+		if result != EZMQX_OK {
+			return nil, result
+		}
+		var instance *EZMQXProtoCodec
+		instance = &EZMQXProtoCodec{}
+		instance.descriptor = descriptor
+		return instance, EZMQX_OK
+	}
+}
@@ -0,0 +1,151 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmqx
+
+import (
+	"bytes"
+	"compress/gzip"
+	"github.com/klauspost/compress/zstd"
+	"go.uber.org/zap"
+	"io"
+	"io/ioutil"
+	"sync"
+)
+
+type EZMQXCompression int
+
+// Constants represents payload compression codec.
+const (
+	COMPRESSION_NONE = 0
+	COMPRESSION_GZIP = 1
+	COMPRESSION_ZSTD = 2
+)
+
+// Codec names advertised in TNS topic record.
+const COMPRESSION_GZIP_NAME = "gzip"
+const COMPRESSION_ZSTD_NAME = "zstd"
+
+// Maximum size of decompressed payload in bytes, larger payload is rejected with EZMQX_BROKEN_PAYLOAD,
+// so that small compressed frame from a peer can not exhaust memory of subscriber.
+const MAX_DECOMPRESSED_PAYLOAD_SIZE = 16 * 1024 * 1024
+
+var zstdEncoder *zstd.Encoder
+var zstdDecoder *zstd.Decoder
+var zstdOnce sync.Once
+
+// Encoder and decoder are safe for concurrent EncodeAll/DecodeAll.
+func initZstd() {
+	zstdOnce.Do(func() {
+		zstdEncoder, _ = zstd.NewWriter(nil)
+		zstdDecoder, _ = zstd.NewReader(nil, zstd.WithDecoderMaxMemory(MAX_DECOMPRESSED_PAYLOAD_SIZE),
+			zstd.WithDecoderMaxWindow(MAX_DECOMPRESSED_PAYLOAD_SIZE))
+	})
+}
+
+func isValidCompression(compression EZMQXCompression) bool {
+	return compression == COMPRESSION_NONE || compression == COMPRESSION_GZIP || compression == COMPRESSION_ZSTD
+}
+
+func getCompressionName(compression EZMQXCompression) string {
+	switch compression {
+	case COMPRESSION_GZIP:
+		return COMPRESSION_GZIP_NAME
+	case COMPRESSION_ZSTD:
+		return COMPRESSION_ZSTD_NAME
+	}
+	return EMPTY_STRING
+}
+
+func getCompression(name string) (EZMQXCompression, EZMQXErrorCode) {
+	switch name {
+	case EMPTY_STRING:
+		return COMPRESSION_NONE, EZMQX_OK
+	case COMPRESSION_GZIP_NAME:
+		return COMPRESSION_GZIP, EZMQX_OK
+	case COMPRESSION_ZSTD_NAME:
+		return COMPRESSION_ZSTD, EZMQX_OK
+	}
+	Logger.Error("Unknown compression", zap.String("Name: ", name))
+	return COMPRESSION_NONE, EZMQX_UNKNOWN_COMPRESSION
+}
+
+func compressPayload(compression EZMQXCompression, data []byte) ([]byte, EZMQXErrorCode) {
+	switch compression {
+	case COMPRESSION_NONE:
+		return data, EZMQX_OK
+	case COMPRESSION_GZIP:
+		var buffer bytes.Buffer
+		writer := gzip.NewWriter(&buffer)
+		_, err := writer.Write(data)
+		if err == nil {
+			err = writer.Close()
+		}
+		if err != nil {
+			Logger.Error("gzip compression failed")
+			return nil, EZMQX_UNKNOWN_STATE
+		}
+		return buffer.Bytes(), EZMQX_OK
+	case COMPRESSION_ZSTD:
+		initZstd()
+		if nil == zstdEncoder {
+			Logger.Error("zstd encoder is null")
+			return nil, EZMQX_UNKNOWN_STATE
+		}
+		return zstdEncoder.EncodeAll(data, nil), EZMQX_OK
+	}
+	return nil, EZMQX_UNKNOWN_COMPRESSION
+}
+
+func decompressPayload(compression EZMQXCompression, data []byte) ([]byte, EZMQXErrorCode) {
+	switch compression {
+	case COMPRESSION_NONE:
+		return data, EZMQX_OK
+	case COMPRESSION_GZIP:
+		reader, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			Logger.Error("gzip header is broken")
+			return nil, EZMQX_BROKEN_PAYLOAD
+		}
+		defer reader.Close()
+		// one more byte is read to find payload exceeding maximum size
+		decompressed, err := ioutil.ReadAll(io.LimitReader(reader, MAX_DECOMPRESSED_PAYLOAD_SIZE+1))
+		if err != nil {
+			Logger.Error("gzip decompression failed")
+			return nil, EZMQX_BROKEN_PAYLOAD
+		}
+		if len(decompressed) > MAX_DECOMPRESSED_PAYLOAD_SIZE {
+			Logger.Error("gzip payload exceeds maximum size")
+			return nil, EZMQX_BROKEN_PAYLOAD
+		}
+		return decompressed, EZMQX_OK
+	case COMPRESSION_ZSTD:
+		initZstd()
+		if nil == zstdDecoder {
+			Logger.Error("zstd decoder is null")
+			return nil, EZMQX_UNKNOWN_STATE
+		}
+		// decoder fails, if payload exceeds maximum size
+		decompressed, err := zstdDecoder.DecodeAll(data, nil)
+		if err != nil {
+			Logger.Error("zstd decompression failed", zap.Error(err))
+			return nil, EZMQX_BROKEN_PAYLOAD
+		}
+		return decompressed, EZMQX_OK
+	}
+	return nil, EZMQX_UNKNOWN_COMPRESSION
+}
@@ -20,6 +20,8 @@ package ezmqx
 
 import (
 	"container/list"
+	"context"
+	"go.uber.org/zap"
 	"math/rand"
 	"sync"
 	"sync/atomic"
@@ -27,6 +29,10 @@ import (
 )
 
 // Structure represents EZMQX configuration.
+//
+// REST settings of the instance [e.g. SetRestClientFactory, SetTLSConfig] should be set before
+// StartDockerMode/StartStandAloneMode, EZMQX_INITIALIZED is returned if it is started.
+// They are kept on Reset, so the instance can be started again with same settings.
 type EZMQXConfig struct {
 	context *EZMQXContext
 	status  uint32
@@ -36,6 +42,8 @@ var configInstance *EZMQXConfig
 var configMutex = &sync.Mutex{}
 
 // Get EZMQX Config instance.
+// It is the default instance, which is used by package level APIs
+// [e.g. GetAMLPublisher, GetAMLSubscriber, GetEZMQXTopicDiscovery].
 func GetConfigInstance() *EZMQXConfig {
 	configMutex.Lock()
 	defer configMutex.Unlock()
@@ -51,41 +59,98 @@ func GetConfigInstance() *EZMQXConfig {
 	return configInstance
 }
 
+// Get new EZMQX Config instance.
+// Unlike GetConfigInstance, every call returns a new instance which owns its own TNS address,
+// port pool, AML/protobuf models, topic handler and REST client factory. Publishers, subscribers
+// and topic discovery created using methods of the instance belong to it. So, one process can
+// work with multiple TNS servers by creating an instance for each of them.
+// Note: Port pool of every instance starts from LOCAL_PORT_START, so only one instance
+// should be started in docker mode in a process.
+func GetEZMQXConfig() *EZMQXConfig {
+	var instance *EZMQXConfig
+	instance = &EZMQXConfig{}
+	instance.context = createContext(createRestFactory())
+	instance.status = CREATED
+	InitLogger()
+	return instance
+}
+
+// Set REST client factory used for pharos-node, anchor and TNS requests of this instance.
+// EZMQX_INVALID_PARAM is returned if TLS config is set and factory does not implement RestClientTLSFactoryInterface.
+func (configInstance *EZMQXConfig) SetRestClientFactory(factory RestClientFactoryInterface) EZMQXErrorCode {
+	if result := configInstance.checkNotStarted("Set REST client factory"); result != EZMQX_OK {
+		return result
+	}
+	if nil == factory {
+		return EZMQX_INVALID_PARAM
+	}
+	restFactory := configInstance.context.getRestFactory()
+	if restFactory.hasTLSConfig() && !supportsTLS(factory) {
+		Logger.Error("Set REST client factory failed: TLS config is set, but factory does not support TLS")
+		return EZMQX_INVALID_PARAM
+	}
+	restFactory.SetFactory(factory)
+	return EZMQX_OK
+}
+
 // Start/Configure EZMQX in docker mode.
 // It works with Pharos system. In DockerMode, stack automatically use Tns service.
 func (configInstance *EZMQXConfig) StartDockerMode(tnsConfPath string) EZMQXErrorCode {
+	return GetErrorCode(configInstance.StartDockerModeWithContext(context.Background(), tnsConfPath))
+}
+
+// Start/Configure EZMQX in docker mode with context.
+// REST requests to pharos-node, anchor and TNS are canceled when ctx is done, deadline of ctx is applied to them.
+// Returns EZMQX_CANCELED if ctx is done before start.
+// On failure, returned error is *EZMQXError which holds error code along with failed operation and cause.
+func (configInstance *EZMQXConfig) StartDockerModeWithContext(ctx context.Context, tnsConfPath string) error {
+	if err := ctx.Err(); err != nil {
+		Logger.Error("Initialize docker mode failed: Canceled")
+		return GetEZMQXError(EZMQX_CANCELED, "StartDockerMode", err)
+	}
 	if false == atomic.CompareAndSwapUint32(&configInstance.status, CREATED, INITIALIZING) {
 		Logger.Error("Initialize docker mode failed: Invalid state")
-		return EZMQX_UNKNOWN_STATE
+		return toError(EZMQX_UNKNOWN_STATE, "StartDockerMode")
 	}
-	result := configInstance.context.initializeDockerMode(tnsConfPath)
-	if result != EZMQX_OK {
-		Logger.Error("Initialize docker mode failed")
+	err := configInstance.context.initializeDockerMode(ctx, tnsConfPath)
+	if err != nil {
+		Logger.Error("Initialize docker mode failed", zap.Error(err))
 		atomic.StoreUint32(&configInstance.status, CREATED)
-		return result
+		return err
 	}
 	atomic.StoreUint32(&configInstance.status, INITIALIZED)
 	Logger.Debug("Started docker mode")
-	return EZMQX_OK
+	return nil
 }
 
 // Start/Configure EZMQX in stand-alone mode.
 // It works without pharos system.
 // Note: TNS address should be complete Rest address of TNS.
 func (configInstance *EZMQXConfig) StartStandAloneMode(hostAddr string, useTns bool, tnsAddr string) EZMQXErrorCode {
+	return GetErrorCode(configInstance.StartStandAloneModeWithContext(context.Background(), hostAddr, useTns, tnsAddr))
+}
+
+// Start/Configure EZMQX in stand-alone mode with context.
+// Returns EZMQX_CANCELED if ctx is done before start.
+// On failure, returned error is *EZMQXError which holds error code along with failed operation and cause.
+func (configInstance *EZMQXConfig) StartStandAloneModeWithContext(ctx context.Context, hostAddr string, useTns bool, tnsAddr string) error {
+	if err := ctx.Err(); err != nil {
+		Logger.Error("Initialize standalone mode failed: Canceled")
+		return GetEZMQXError(EZMQX_CANCELED, "StartStandAloneMode", err)
+	}
 	if false == atomic.CompareAndSwapUint32(&configInstance.status, CREATED, INITIALIZING) {
 		Logger.Error("Initialize standalone mode failed: Invalid state")
-		return EZMQX_UNKNOWN_STATE
+		return toError(EZMQX_UNKNOWN_STATE, "StartStandAloneMode")
 	}
 	result := configInstance.context.initializeStandAloneMode(hostAddr, useTns, tnsAddr)
 	if result != EZMQX_OK {
 		Logger.Error("Initialize standalone mode failed")
 		atomic.StoreUint32(&configInstance.status, CREATED)
-		return result
+		return toError(result, "StartStandAloneMode")
 	}
 	atomic.StoreUint32(&configInstance.status, INITIALIZED)
 	Logger.Debug("Started standalone mode")
-	return EZMQX_OK
+	return nil
 }
 
 // Add aml model file for publish or subscribe AML data.
@@ -97,6 +162,88 @@ func (configInstance *EZMQXConfig) AddAmlModel(amlFilePath list.List) (*list.Lis
 	return configInstance.context.addAmlRep(amlFilePath)
 }
 
+// Add protobuf model for publish or subscribe protobuf data.
+// Each file should be a serialized FileDescriptorSet [e.g. protoc --include_imports --descriptor_set_out].
+// Returns fully-qualified names of all messages in the given files.
+func (configInstance *EZMQXConfig) AddProtoModel(descSetPath list.List) (*list.List, EZMQXErrorCode) {
+	if atomic.LoadUint32(&configInstance.status) != INITIALIZED {
+		Logger.Error("Not initialized")
+		return nil, EZMQX_NOT_INITIALIZED
+	}
+	return configInstance.context.addProtoDesc(descSetPath)
+}
+
+// Set number of last messages per topic that publishers keep for late-joining subscribers.
+// Cached messages are served on a snapshot end point which is advertised in TNS topic record,
+// and subscribers fetch them on subscribe before receiving live messages.
+// It applies to publishers created after this call. Zero disables the cache [default].
+// Note: Last value cache is not supported for secured publishers.
+func (configInstance *EZMQXConfig) SetLastValueCache(depth int) EZMQXErrorCode {
+	if atomic.LoadUint32(&configInstance.status) != INITIALIZED {
+		Logger.Error("Not initialized")
+		return EZMQX_NOT_INITIALIZED
+	}
+	if depth < 0 {
+		Logger.Error("Invalid last value cache depth")
+		return EZMQX_INVALID_PARAM
+	}
+	configInstance.context.setLastValueDepth(depth)
+	return EZMQX_OK
+}
+
+// Set payload compression codec for publishers.
+// Codec is advertised in TNS topic record and subscribers decompress payload automatically.
+// It applies to publishers created after this call. Default is COMPRESSION_NONE.
+func (configInstance *EZMQXConfig) SetCompression(compression EZMQXCompression) EZMQXErrorCode {
+	if atomic.LoadUint32(&configInstance.status) != INITIALIZED {
+		Logger.Error("Not initialized")
+		return EZMQX_NOT_INITIALIZED
+	}
+	if !isValidCompression(compression) {
+		Logger.Error("Invalid compression")
+		return EZMQX_INVALID_PARAM
+	}
+	configInstance.context.setCompression(compression)
+	return EZMQX_OK
+}
+
+// Set flow control [queue sizes, linger and overflow policy] for publishers and subscribers.
+// It applies to publishers and subscribers created after this call. Nil restores default [no queue].
+// Dropped messages can be checked using GetDroppedCount API of publisher or subscriber.
+func (configInstance *EZMQXConfig) SetFlowControl(flowControl *EZMQXFlowControl) EZMQXErrorCode {
+	if atomic.LoadUint32(&configInstance.status) != INITIALIZED {
+		Logger.Error("Not initialized")
+		return EZMQX_NOT_INITIALIZED
+	}
+	if nil != flowControl && !flowControl.isValid() {
+		Logger.Error("Invalid flow control")
+		return EZMQX_INVALID_PARAM
+	}
+	configInstance.context.setFlowControl(flowControl)
+	return EZMQX_OK
+}
+
+// Set callback for TNS registration state changes of published topics.
+// Topics are re-registered to TNS automatically, when keep alive fails or TNS does not know the topics [e.g. TNS restarted].
+// Callback is called on topic handler or re-registration routine, nil removes the callback.
+func (configInstance *EZMQXConfig) SetRegistrationCallback(callback EZMQXRegistrationCB) EZMQXErrorCode {
+	if atomic.LoadUint32(&configInstance.status) != INITIALIZED {
+		Logger.Error("Not initialized")
+		return EZMQX_NOT_INITIALIZED
+	}
+	configInstance.context.setRegistrationCB(callback)
+	return EZMQX_OK
+}
+
+// Returns EZMQX_INITIALIZED if the instance is started, REST settings can not be changed then.
+func (configInstance *EZMQXConfig) checkNotStarted(op string) EZMQXErrorCode {
+	if atomic.LoadUint32(&configInstance.status) != CREATED {
+		Logger.Error(op + " failed: Already initialized")
+		return EZMQX_INITIALIZED
+	}
+	return EZMQX_OK
+}
+
 // Reset/Terminate EZMQX stack.
 func (configInstance *EZMQXConfig) Reset() EZMQXErrorCode {
 	if false == atomic.CompareAndSwapUint32(&configInstance.status, INITIALIZED, TERMINATING) {
@@ -18,9 +18,14 @@
 package ezmqx
 
 import (
+	"context"
 	"go.uber.org/zap"
 	"go/aml"
 	"go/ezmq"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
 
 	"container/list"
 	"encoding/json"
@@ -46,30 +51,82 @@ type EZMQXContext struct {
 	numOfPort           int
 	usedIdx             int
 	amlRepDic           map[string]*aml.Representation
+	protoDescDic        map[string]protoreflect.MessageDescriptor
 	usedPorts           map[int]bool
 	ports               map[int]int
+	lastValueDepth      int
+	compression         EZMQXCompression
+	flowControl         *EZMQXFlowControl
+	registrationCB      EZMQXRegistrationCB
+	topicHandler        *EZMQXTopicHandler
+	restFactory         *RestFactory
 	mutex               *sync.Mutex
 }
 
 var ctxInstance *EZMQXContext
 
+// Number of started contexts sharing EZMQ API.
+var ezmqUsers int
+var ezmqMutex = &sync.Mutex{}
+
+// Default context used by GetConfigInstance.
 func getContextInstance() *EZMQXContext {
 	if nil == ctxInstance {
-		ctxInstance = &EZMQXContext{}
-		ctxInstance.initialized.Store(false)
-		ctxInstance.reverseProxyEnabled.Store(false)
-		ctxInstance.standAlone = false
-		ctxInstance.amlRepDic = make(map[string]*aml.Representation)
-		ctxInstance.usedPorts = make(map[int]bool)
-		ctxInstance.ports = make(map[int]int)
-		ctxInstance.mutex = &sync.Mutex{}
+		ctxInstance = createContext(GetRestFactory())
 	}
 	return ctxInstance
 }
 
+func createContext(restFactory *RestFactory) *EZMQXContext {
+	var instance *EZMQXContext
+	instance = &EZMQXContext{}
+	instance.initialized.Store(false)
+	instance.terminated.Store(false)
+	instance.reverseProxyEnabled.Store(false)
+	instance.standAlone = false
+	instance.amlRepDic = make(map[string]*aml.Representation)
+	instance.protoDescDic = make(map[string]protoreflect.MessageDescriptor)
+	instance.usedPorts = make(map[int]bool)
+	instance.ports = make(map[int]int)
+	instance.restFactory = restFactory
+	instance.mutex = &sync.Mutex{}
+	return instance
+}
+
+// Initialize EZMQ API, if this is the first started context.
+func initializeEZMQ() EZMQXErrorCode {
+	ezmqMutex.Lock()
+	defer ezmqMutex.Unlock()
+	if 0 == ezmqUsers {
+		if ezmq.EZMQ_OK != ezmq.GetInstance().Initialize() {
+			return EZMQX_UNKNOWN_STATE
+		}
+	}
+	ezmqUsers++
+	return EZMQX_OK
+}
+
+// Terminate EZMQ API, if this is the last started context.
+func terminateEZMQ() {
+	ezmqMutex.Lock()
+	defer ezmqMutex.Unlock()
+	if 0 == ezmqUsers {
+		return
+	}
+	ezmqUsers--
+	if 0 != ezmqUsers {
+		return
+	}
+	Logger.Debug("Try EZMQ API terminate")
+	if ezmq.EZMQ_OK != ezmq.GetInstance().Terminate() {
+		Logger.Debug("EZMQ API terminate failed")
+	}
+	Logger.Debug("EZMQ API terminated")
+}
+
 func (cxtInstance *EZMQXContext) assignDynamicPort() (int, EZMQXErrorCode) {
-	ctxInstance.mutex.Lock()
-	defer ctxInstance.mutex.Unlock()
+	cxtInstance.mutex.Lock()
+	defer cxtInstance.mutex.Unlock()
 	port := 0
 	for {
 		if cxtInstance.numOfPort >= LOCAL_PORT_MAX {
@@ -93,8 +150,8 @@ func (cxtInstance *EZMQXContext) assignDynamicPort() (int, EZMQXErrorCode) {
 }
 
 func (contextInstance *EZMQXContext) releaseDynamicPort(port int) EZMQXErrorCode {
-	ctxInstance.mutex.Lock()
-	defer ctxInstance.mutex.Unlock()
+	contextInstance.mutex.Lock()
+	defer contextInstance.mutex.Unlock()
 	if false == contextInstance.usedPorts[port] {
 		return EZMQX_RELEASE_WRONG_PORT
 	}
@@ -103,8 +160,21 @@ func (contextInstance *EZMQXContext) releaseDynamicPort(port int) EZMQXErrorCode
 	return EZMQX_OK
 }
 
+func (contextInstance *EZMQXContext) getTopicHandler() *EZMQXTopicHandler {
+	contextInstance.mutex.Lock()
+	defer contextInstance.mutex.Unlock()
+	if nil == contextInstance.topicHandler {
+		contextInstance.topicHandler = createTopicHandler(contextInstance)
+	}
+	return contextInstance.topicHandler
+}
+
+func (contextInstance *EZMQXContext) getRestFactory() *RestFactory {
+	return contextInstance.restFactory
+}
+
 func (contextInstance *EZMQXContext) setHostInfo(name string, address string) {
-	ctxInstance.hostName = name
+	contextInstance.hostName = name
 	contextInstance.hostAddr = address
 }
 
@@ -113,30 +183,30 @@ func (cxtInstance *EZMQXContext) setTnsInfo(tnsAddr string) {
 	cxtInstance.tnsAddr = tnsAddr
 }
 
-func (contextInstance *EZMQXContext) readImageName(tnsConfPath string) EZMQXErrorCode {
+func (contextInstance *EZMQXContext) readImageName(tnsConfPath string) error {
 	Logger.Debug("[readImageName] ", zap.String("File path: ", tnsConfPath))
 	fileData, error := ioutil.ReadFile(tnsConfPath)
 	if error != nil {
 		Logger.Error("[readImageName] Unable to read from file")
-		return EZMQX_UNKNOWN_STATE
+		return GetEZMQXError(EZMQX_UNKNOWN_STATE, "readImageName", error)
 	}
 	var data interface{}
 	error = json.Unmarshal(fileData, &data)
 	if error != nil {
 		Logger.Error("[readImageName] Unable to unmarshal json")
-		return EZMQX_UNKNOWN_STATE
+		return GetEZMQXError(EZMQX_UNKNOWN_STATE, "readImageName", error)
 	}
 	stringMap := data.(map[string]interface{})
 	contextInstance.tnsImageName = stringMap[CONFIG_ANCHOR_IMAGE_NAME].(string)
 	Logger.Debug("[readImageName] ", zap.String("imageName: ", contextInstance.tnsImageName))
-	return EZMQX_OK
+	return nil
 }
 
-func (contextInstance *EZMQXContext) parseConfigData(response RestResponse) EZMQXErrorCode {
+func (contextInstance *EZMQXContext) parseConfigData(response RestResponse) error {
 	statusCode := response.GetStatusCode()
 	Logger.Debug("[Config] ", zap.Int(" Status code: ", statusCode))
 	if statusCode != HTTP_OK {
-		return EZMQX_REST_ERROR
+		return newError(EZMQX_REST_ERROR, "parseConfigData", "unexpected status code "+strconv.Itoa(statusCode))
 	}
 	data := response.GetResponse()
 	Logger.Debug("[Config] ", zap.String("Response: ", string(data)))
@@ -144,12 +214,12 @@ func (contextInstance *EZMQXContext) parseConfigData(response RestResponse) EZMQ
 	err := json.Unmarshal([]byte(data), &configData)
 	if err != nil {
 		Logger.Error("[Config] Json unmarshal failed")
-		return EZMQX_REST_ERROR
+		return GetEZMQXError(EZMQX_REST_ERROR, "parseConfigData", err)
 	}
 	config, exists := configData[CONF_PROPS]
 	if !exists {
 		Logger.Error("[Config] No properties key in json response")
-		return EZMQX_REST_ERROR
+		return newError(EZMQX_REST_ERROR, "parseConfigData", CONF_PROPS+" key missing")
 	}
 	anchorKeyExists := false
 	nodeKeyExists := false
@@ -170,16 +240,16 @@ func (contextInstance *EZMQXContext) parseConfigData(response RestResponse) EZMQ
 	}
 	if !anchorKeyExists || !nodeKeyExists {
 		Logger.Error("[Config] Anchor address/ Node address key not exists")
-		return EZMQX_REST_ERROR
+		return newError(EZMQX_REST_ERROR, "parseConfigData", CONF_ANCHOR_ADDR+"/"+CONF_NODE_ADDR+" key missing")
 	}
-	return EZMQX_OK
+	return nil
 }
 
-func (contextInstance *EZMQXContext) parseProperties(config map[string]interface{}) EZMQXErrorCode {
+func (contextInstance *EZMQXContext) parseProperties(config map[string]interface{}) error {
 	properties, exist := config[NODES_PROPS].([]interface{})
 	if !exist {
 		Logger.Error("[TNS info] Properties key not exist")
-		return EZMQX_REST_ERROR
+		return newError(EZMQX_REST_ERROR, "parseProperties", NODES_PROPS+" key missing")
 	}
 	var proxyKeyExist = false
 	for _, property := range properties {
@@ -193,16 +263,16 @@ func (contextInstance *EZMQXContext) parseProperties(config map[string]interface
 		}
 	}
 	if !proxyKeyExist {
-		return EZMQX_REST_ERROR
+		return newError(EZMQX_REST_ERROR, "parseProperties", NODES_REVERSE_PROXY+" key missing")
 	}
-	return EZMQX_OK
+	return nil
 }
 
-func (contextInstance *EZMQXContext) parseTnsInfoResponse(response RestResponse) EZMQXErrorCode {
+func (contextInstance *EZMQXContext) parseTnsInfoResponse(response RestResponse) error {
 	statusCode := response.GetStatusCode()
 	Logger.Debug("[TNS info] ", zap.Int(" Status code: ", statusCode))
 	if statusCode != HTTP_OK {
-		return EZMQX_REST_ERROR
+		return newError(EZMQX_REST_ERROR, "parseTnsInfoResponse", "unexpected status code "+strconv.Itoa(statusCode))
 	}
 	data := response.GetResponse()
 	Logger.Debug("[TNS info] ", zap.String("Response: ", string(data)))
@@ -211,12 +281,12 @@ func (contextInstance *EZMQXContext) parseTnsInfoResponse(response RestResponse)
 	err := json.Unmarshal([]byte(data), &tnsInfoMap)
 	if err != nil {
 		Logger.Error("[TNS info] Unmarshal error")
-		return EZMQX_REST_ERROR
+		return GetEZMQXError(EZMQX_REST_ERROR, "parseTnsInfoResponse", err)
 	}
 	nodes, exists := tnsInfoMap[NODES]
 	if !exists {
 		Logger.Error("[TNS info] Node key not exist")
-		return EZMQX_REST_ERROR
+		return newError(EZMQX_REST_ERROR, "parseTnsInfoResponse", NODES+" key missing")
 	}
 	for _, item := range nodes {
 		stringMap := item.(map[string]interface{})
@@ -224,7 +294,7 @@ func (contextInstance *EZMQXContext) parseTnsInfoResponse(response RestResponse)
 		connected, exists := stringMap[NODES_STATUS].(string)
 		if !exists {
 			Logger.Error("[TNS info] Status key not exist")
-			return EZMQX_REST_ERROR
+			return newError(EZMQX_REST_ERROR, "parseTnsInfoResponse", NODES_STATUS+" key missing")
 		}
 		if strings.Compare("connected", connected) != 0 {
 			fmt.Println("[TNS info] Not connected")
@@ -234,42 +304,44 @@ func (contextInstance *EZMQXContext) parseTnsInfoResponse(response RestResponse)
 		contextInstance.tnsAddr, exists = stringMap[NODES_IP].(string)
 		if !exists {
 			Logger.Error("[TNS info] IP key not exist")
-			return EZMQX_REST_ERROR
+			return newError(EZMQX_REST_ERROR, "parseTnsInfoResponse", NODES_IP+" key missing")
 		}
 
 		config, exists := stringMap[NODES_CONF].(map[string]interface{})
 		if !exists {
 			Logger.Error("[TNS info] config key not exist")
-			return EZMQX_REST_ERROR
+			return newError(EZMQX_REST_ERROR, "parseTnsInfoResponse", NODES_CONF+" key missing")
 		}
 
-		if contextInstance.parseProperties(config) != EZMQX_OK {
+		err = contextInstance.parseProperties(config)
+		if err != nil {
 			Logger.Error("[TNS info] Parse properties error")
-			return EZMQX_REST_ERROR
+			return GetEZMQXError(EZMQX_REST_ERROR, "parseTnsInfoResponse", err)
 		}
 	}
 
+	scheme := contextInstance.getRestFactory().getScheme(REST_ENDPOINT_TNS)
 	if contextInstance.isReverseProxyEnabled() {
-		contextInstance.tnsAddr = HTTP_PREFIX + contextInstance.tnsAddr + COLON + REVERSE_PROXY_KNOWN_PORT + REVERSE_PROXY_PREFIX
+		contextInstance.tnsAddr = scheme + contextInstance.tnsAddr + COLON + REVERSE_PROXY_KNOWN_PORT + REVERSE_PROXY_PREFIX
 	} else {
-		contextInstance.tnsAddr = HTTP_PREFIX + contextInstance.tnsAddr + COLON + TNS_KNOWN_PORT
+		contextInstance.tnsAddr = scheme + contextInstance.tnsAddr + COLON + TNS_KNOWN_PORT
 	}
 	Logger.Debug("[TNS info] ", zap.String("TNS address: ", contextInstance.tnsAddr))
-	return EZMQX_OK
+	return nil
 }
 
-func (contextInstance *EZMQXContext) readHostName(path string) EZMQXErrorCode {
+func (contextInstance *EZMQXContext) readHostName(path string) error {
 	Logger.Debug("[readFromFile] ", zap.String("File path: ", path))
 	data, err := ioutil.ReadFile(path)
 	if err != nil {
 		Logger.Error("[readFromFile] Unable to read from file")
-		return EZMQX_UNKNOWN_STATE
+		return GetEZMQXError(EZMQX_UNKNOWN_STATE, "readHostName", err)
 	}
 	contextInstance.hostName = string(data)
 	//remove trailing /n
 	contextInstance.hostName = contextInstance.hostName[0 : len(contextInstance.hostName)-1]
 	Logger.Debug("[readFromFile] ", zap.String("hostName: ", contextInstance.hostName))
-	return EZMQX_OK
+	return nil
 }
 
 func (contextInstance *EZMQXContext) parseAppsResponse(response RestResponse) *list.List {
@@ -314,21 +386,21 @@ func (contextInstance *EZMQXContext) parseAppsResponse(response RestResponse) *l
 	return idList
 }
 
-func (contextInstance *EZMQXContext) parsePortInfo(port interface{}) EZMQXErrorCode {
+func (contextInstance *EZMQXContext) parsePortInfo(port interface{}) error {
 	ports := port.([]interface{})
 	for _, item := range ports {
 		stringMap := item.(map[string]interface{})
 		privatePort, exists := stringMap[PORTS_PRIVATE]
 		if !exists {
 			Logger.Error("[Running Apps] No private port key in json response")
-			return EZMQX_REST_ERROR
+			return newError(EZMQX_REST_ERROR, "parsePortInfo", PORTS_PRIVATE+" key missing")
 		}
 		priPort := strconv.FormatFloat(privatePort.(float64), 'f', -1, 64)
 		Logger.Debug("[Port info] ", zap.String("Private port: ", priPort))
 		publicPort, exists := stringMap[PORTS_PUBLIC]
 		if !exists {
 			Logger.Error("[Running Apps] No public port key in json response")
-			return EZMQX_REST_ERROR
+			return newError(EZMQX_REST_ERROR, "parsePortInfo", PORTS_PUBLIC+" key missing")
 		}
 		pubPort := strconv.FormatFloat(publicPort.(float64), 'f', -1, 64)
 		Logger.Debug("[Port info] ", zap.String("Public Port: ", pubPort))
@@ -336,14 +408,14 @@ func (contextInstance *EZMQXContext) parsePortInfo(port interface{}) EZMQXErrorC
 		public, _ := strconv.Atoi(pubPort)
 		contextInstance.ports[private] = public
 	}
-	return EZMQX_OK
+	return nil
 }
 
-func (contextInstance *EZMQXContext) parseAppInfo(response RestResponse) EZMQXErrorCode {
+func (contextInstance *EZMQXContext) parseAppInfo(response RestResponse) error {
 	statusCode := response.GetStatusCode()
 	Logger.Debug("[App info] ", zap.Int(" Status code: ", statusCode))
 	if statusCode != HTTP_OK {
-		return EZMQX_REST_ERROR
+		return newError(EZMQX_REST_ERROR, "parseAppInfo", "unexpected status code "+strconv.Itoa(statusCode))
 	}
 	data := response.GetResponse()
 	Logger.Debug("[App info] ", zap.String("Response: ", string(data)))
@@ -351,12 +423,12 @@ func (contextInstance *EZMQXContext) parseAppInfo(response RestResponse) EZMQXEr
 	err := json.Unmarshal([]byte(data), &appInfo)
 	if err != nil {
 		Logger.Error("[Running Apps] Unmarshal error")
-		return EZMQX_REST_ERROR
+		return GetEZMQXError(EZMQX_REST_ERROR, "parseAppInfo", err)
 	}
 	services, exists := appInfo[SERVICES_PROPS]
 	if !exists {
 		Logger.Error("[Running Apps] No services key in json response")
-		return EZMQX_REST_ERROR
+		return newError(EZMQX_REST_ERROR, "parseAppInfo", SERVICES_PROPS+" key missing")
 	}
 	interfaces := services.([]interface{})
 	for _, service := range interfaces {
@@ -364,7 +436,7 @@ func (contextInstance *EZMQXContext) parseAppInfo(response RestResponse) EZMQXEr
 		cid, exists := serviceMap[SERVICES_CON_ID]
 		if !exists {
 			Logger.Error("[Running Apps] No id key in json response")
-			return EZMQX_REST_ERROR
+			return newError(EZMQX_REST_ERROR, "parseAppInfo", SERVICES_CON_ID+" key missing")
 		}
 		hostName := contextInstance.hostName
 		containerId := cid.(string)
@@ -375,94 +447,98 @@ func (contextInstance *EZMQXContext) parseAppInfo(response RestResponse) EZMQXEr
 			port, exists := serviceMap[SERVICES_CON_PORTS]
 			if !exists {
 				Logger.Error("[Running Apps] No ports key in json response")
-				return EZMQX_REST_ERROR
+				return newError(EZMQX_REST_ERROR, "parseAppInfo", SERVICES_CON_PORTS+" key missing")
 			}
-			result := contextInstance.parsePortInfo(port)
-			if result != EZMQX_OK {
+			err = contextInstance.parsePortInfo(port)
+			if err != nil {
 				Logger.Error("[Running Apps] Parse port info failed")
-				return EZMQX_REST_ERROR
+				return GetEZMQXError(EZMQX_REST_ERROR, "parseAppInfo", err)
 			}
 		}
 	}
-	return EZMQX_OK
+	return nil
 }
 
-func (contextInstance *EZMQXContext) initializeDockerMode(tnsConfPath string) EZMQXErrorCode {
-	ezmqResult := ezmq.GetInstance().Initialize()
-	if ezmqResult != ezmq.EZMQ_OK {
+func (contextInstance *EZMQXContext) initializeDockerMode(ctx context.Context, tnsConfPath string) (err error) {
+	if initializeEZMQ() != EZMQX_OK {
 		Logger.Error("Could not initialize EZMQ")
-		return EZMQX_UNKNOWN_STATE
+		return newError(EZMQX_UNKNOWN_STATE, "initializeDockerMode", "ezmq initialize failed")
 	}
+	defer func() {
+		if err != nil {
+			terminateEZMQ()
+		}
+	}()
 
 	//Read image name from TNS config file
-	result := contextInstance.readImageName(tnsConfPath)
-	if result != EZMQX_OK {
-		return result
+	err = contextInstance.readImageName(tnsConfPath)
+	if err != nil {
+		return err
 	}
 
-	restClient := GetRestFactory()
+	restClient := contextInstance.getRestFactory()
 	var response *RestResponse
-	var err EZMQXErrorCode
 
 	// Configuration resource
-	configURL := NODE + PREFIX + API_CONFIG
+	nodeAddr := restClient.applyScheme(REST_ENDPOINT_NODE, NODE)
+	configURL := nodeAddr + PREFIX + API_CONFIG
 	Logger.Debug("[Config] ", zap.String("Rest URL: ", string(configURL)))
-	response, err = restClient.Get(configURL)
-	if err != EZMQX_OK {
+	response, err = restClient.getWithError(ctx, REST_ENDPOINT_NODE, configURL)
+	if err != nil {
 		Logger.Error("[Config] HTTP request failed")
-		return EZMQX_REST_ERROR
+		return err
 	}
-	result = contextInstance.parseConfigData(*response)
-	if result != EZMQX_OK {
+	err = contextInstance.parseConfigData(*response)
+	if err != nil {
 		Logger.Error("[Config] Parse config data failed ")
-		return result
+		return err
 	}
 
 	// Get TNS information
-	anchorTNSURL := contextInstance.anchorAddr + API_SEARCH_NODE
+	anchorTNSURL := restClient.applyScheme(REST_ENDPOINT_ANCHOR, contextInstance.anchorAddr) + API_SEARCH_NODE
 	query := ANCHOR_IMAGE_NAME + contextInstance.tnsImageName
 	Logger.Debug("[TNS info] ", zap.String("Rest URL: ", string(anchorTNSURL)))
-	response, err = restClient.Get(anchorTNSURL + QUESTION_MARK + query)
-	if err != EZMQX_OK {
+	response, err = restClient.getWithError(ctx, REST_ENDPOINT_ANCHOR, anchorTNSURL+QUESTION_MARK+query)
+	if err != nil {
 		Logger.Error("[TNS info] HTTP request failed")
-		return EZMQX_REST_ERROR
+		return err
 	}
-	result = contextInstance.parseTnsInfoResponse(*response)
-	if result != EZMQX_OK {
+	err = contextInstance.parseTnsInfoResponse(*response)
+	if err != nil {
 		Logger.Error("[TNS info] Parse Tns info failed ")
-		return result
+		return err
 	}
 
 	// Get Host Name
-	result = contextInstance.readHostName(HOST_NAME_FILE_PATH)
-	if result != EZMQX_OK {
+	err = contextInstance.readHostName(HOST_NAME_FILE_PATH)
+	if err != nil {
 		Logger.Error("[Config] Read from file failed")
-		return result
+		return err
 	}
 	// Applications resource
 	var idList *list.List = nil
-	appsURL := NODE + PREFIX + API_APPS
+	appsURL := nodeAddr + PREFIX + API_APPS
 	Logger.Debug("[Running Apps] ", zap.String("Rest URL: ", string(appsURL)))
-	response, err = restClient.Get(appsURL)
-	if err != EZMQX_OK {
+	response, err = restClient.getWithError(ctx, REST_ENDPOINT_NODE, appsURL)
+	if err != nil {
 		Logger.Error("[Config] HTTP request failed")
-		return EZMQX_REST_ERROR
+		return err
 	}
 	idList = contextInstance.parseAppsResponse(*response)
 	if nil == idList {
 		Logger.Error("[Running Apps] Parse apps response failed")
-		return EZMQX_REST_ERROR
+		return newError(EZMQX_REST_ERROR, "parseAppsResponse", "invalid running apps response")
 	}
 	// APP info
-	appInfoURL := NODE + PREFIX + API_APPS + SLASH
+	appInfoURL := nodeAddr + PREFIX + API_APPS + SLASH
 	for id := idList.Front(); id != nil; id = id.Next() {
 		appId := id.Value.(string)
 		url := appInfoURL + appId
 		Logger.Debug("[App Info] ", zap.String("Rest URL: ", url))
-		response, err = restClient.Get(url)
-		if err != EZMQX_OK {
+		response, err = restClient.getWithError(ctx, REST_ENDPOINT_NODE, url)
+		if err != nil {
 			Logger.Error("[App info] HTTP request failed")
-			return EZMQX_REST_ERROR
+			return err
 		}
 		contextInstance.parseAppInfo(*response)
 	}
@@ -470,29 +546,29 @@ func (contextInstance *EZMQXContext) initializeDockerMode(tnsConfPath string) EZ
 	contextInstance.terminated.Store(false)
 	contextInstance.tnsEnabled = true
 	Logger.Debug("EZMQX Context created")
-	return EZMQX_OK
+	return nil
 }
 
 func (contextInstance *EZMQXContext) initializeStandAloneMode(hostAddr string, useTns bool, tnsAddr string) EZMQXErrorCode {
-	result := ezmq.GetInstance().Initialize()
-	if result != ezmq.EZMQ_OK {
+	result := initializeEZMQ()
+	if result != EZMQX_OK {
 		Logger.Error("Could not start ezmq context")
 		return EZMQX_UNKNOWN_STATE
 	}
-	ctxInstance.standAlone = true
-	ctxInstance.setHostInfo(LOCAL_HOST, hostAddr)
+	contextInstance.standAlone = true
+	contextInstance.setHostInfo(LOCAL_HOST, hostAddr)
 	if useTns {
-		ctxInstance.setTnsInfo(tnsAddr)
+		contextInstance.setTnsInfo(contextInstance.getRestFactory().applyScheme(REST_ENDPOINT_TNS, tnsAddr))
 	}
-	ctxInstance.initialized.Store(true)
-	ctxInstance.terminated.Store(false)
+	contextInstance.initialized.Store(true)
+	contextInstance.terminated.Store(false)
 	Logger.Debug("EZMQX Context created")
 	return EZMQX_OK
 }
 
 func (cxtInstance *EZMQXContext) getAmlRep(amlModelId string) (*aml.Representation, EZMQXErrorCode) {
-	ctxInstance.mutex.Lock()
-	defer ctxInstance.mutex.Unlock()
+	cxtInstance.mutex.Lock()
+	defer cxtInstance.mutex.Unlock()
 	rep := cxtInstance.amlRepDic[amlModelId]
 	if nil == rep {
 		Logger.Error("No representation found for model ID")
@@ -503,8 +579,8 @@ func (cxtInstance *EZMQXContext) getAmlRep(amlModelId string) (*aml.Representati
 
 func (cxtInstance *EZMQXContext) addAmlRep(amlFilePath list.List) (*list.List, EZMQXErrorCode) {
 	modelId := list.New()
-	ctxInstance.mutex.Lock()
-	defer ctxInstance.mutex.Unlock()
+	cxtInstance.mutex.Lock()
+	defer cxtInstance.mutex.Unlock()
 	for filePath := amlFilePath.Front(); filePath != nil; filePath = filePath.Next() {
 		repObject, err := aml.CreateRepresentation(filePath.Value.(string))
 		if err != aml.AML_OK {
@@ -524,6 +600,61 @@ func (cxtInstance *EZMQXContext) addAmlRep(amlFilePath list.List) (*list.List, E
 	return modelId, EZMQX_OK
 }
 
+func (cxtInstance *EZMQXContext) getProtoDesc(messageName string) (protoreflect.MessageDescriptor, EZMQXErrorCode) {
+	cxtInstance.mutex.Lock()
+	defer cxtInstance.mutex.Unlock()
+	desc := cxtInstance.protoDescDic[messageName]
+	if nil == desc {
+		Logger.Error("No descriptor found for message name")
+		return nil, EZMQX_UNKNOWN_PROTO_MODEL
+	}
+	return desc, EZMQX_OK
+}
+
+func (cxtInstance *EZMQXContext) addProtoDesc(descSetPath list.List) (*list.List, EZMQXErrorCode) {
+	messageNames := list.New()
+	cxtInstance.mutex.Lock()
+	defer cxtInstance.mutex.Unlock()
+	for filePath := descSetPath.Front(); filePath != nil; filePath = filePath.Next() {
+		data, err := ioutil.ReadFile(filePath.Value.(string))
+		if err != nil {
+			Logger.Error("Read descriptor set file failed")
+			return messageNames, EZMQX_INVALID_PROTO_MODEL
+		}
+		descSet := &descriptorpb.FileDescriptorSet{}
+		err = proto.Unmarshal(data, descSet)
+		if err != nil {
+			Logger.Error("Unmarshal descriptor set failed")
+			return messageNames, EZMQX_INVALID_PROTO_MODEL
+		}
+		files, err := protodesc.NewFiles(descSet)
+		if err != nil {
+			Logger.Error("Create file descriptors failed")
+			return messageNames, EZMQX_INVALID_PROTO_MODEL
+		}
+		files.RangeFiles(func(file protoreflect.FileDescriptor) bool {
+			cxtInstance.storeProtoDesc(file.Messages(), messageNames)
+			return true
+		})
+	}
+	return messageNames, EZMQX_OK
+}
+
+func (cxtInstance *EZMQXContext) storeProtoDesc(messages protoreflect.MessageDescriptors, messageNames *list.List) {
+	for i := 0; i < messages.Len(); i++ {
+		message := messages.Get(i)
+		if message.IsMapEntry() {
+			continue
+		}
+		messageName := string(message.FullName())
+		if nil == cxtInstance.protoDescDic[messageName] {
+			cxtInstance.protoDescDic[messageName] = message
+		}
+		messageNames.PushBack(messageName)
+		cxtInstance.storeProtoDesc(message.Messages(), messageNames)
+	}
+}
+
 func (cxtInstance *EZMQXContext) getHostEp(port int) (*EZMQXEndpoint, EZMQXErrorCode) {
 	hostPort := 0
 	if cxtInstance.isCtxStandAlone() {
@@ -547,33 +678,39 @@ func (cxtInstance *EZMQXContext) terminate() EZMQXErrorCode {
 	}
 
 	//terminate topic handler
-	topicHandler := getTopicHandler()
+	topicHandler := cxtInstance.getTopicHandler()
 	topicHandler.terminateHandler()
 	Logger.Debug("Terminated handler")
+	cxtInstance.getRestFactory().closeIdleConnections()
 
 	//clear maps
 	for key := range cxtInstance.ports {
 		delete(cxtInstance.ports, key)
 	}
+	cxtInstance.mutex.Lock()
 	for key := range cxtInstance.usedPorts {
 		delete(cxtInstance.usedPorts, key)
 	}
+	cxtInstance.usedIdx = 0
+	cxtInstance.numOfPort = 0
+	cxtInstance.lastValueDepth = 0
+	cxtInstance.compression = COMPRESSION_NONE
+	cxtInstance.flowControl = nil
+	cxtInstance.mutex.Unlock()
 	for key := range cxtInstance.amlRepDic {
 		delete(cxtInstance.amlRepDic, key)
 	}
+	for key := range cxtInstance.protoDescDic {
+		delete(cxtInstance.protoDescDic, key)
+	}
 	cxtInstance.hostName = ""
 	cxtInstance.hostAddr = ""
 	cxtInstance.anchorAddr = ""
 	cxtInstance.tnsAddr = ""
-	cxtInstance.usedIdx = 0
-	cxtInstance.numOfPort = 0
+	cxtInstance.setRegistrationCB(nil)
 	cxtInstance.standAlone = false
 	cxtInstance.tnsEnabled = false
-	Logger.Debug("Try EZMQ API terminate")
-	if ezmq.EZMQ_OK != ezmq.GetInstance().Terminate() {
-		Logger.Debug("EZMQ API terminate failed")
-	}
-	Logger.Debug("EZMQ API terminated")
+	terminateEZMQ()
 	cxtInstance.terminated.Store(true)
 	cxtInstance.initialized.Store(false)
 	Logger.Debug("EZMQX Context terminated")
@@ -600,6 +737,55 @@ func (cxtInstance *EZMQXContext) isReverseProxyEnabled() bool {
 	return (cxtInstance.reverseProxyEnabled.Load()).(bool)
 }
 
+func (cxtInstance *EZMQXContext) setLastValueDepth(depth int) {
+	cxtInstance.mutex.Lock()
+	defer cxtInstance.mutex.Unlock()
+	cxtInstance.lastValueDepth = depth
+}
+
+func (cxtInstance *EZMQXContext) getLastValueDepth() int {
+	cxtInstance.mutex.Lock()
+	defer cxtInstance.mutex.Unlock()
+	return cxtInstance.lastValueDepth
+}
+
+func (cxtInstance *EZMQXContext) setCompression(compression EZMQXCompression) {
+	cxtInstance.mutex.Lock()
+	defer cxtInstance.mutex.Unlock()
+	cxtInstance.compression = compression
+}
+
+func (cxtInstance *EZMQXContext) getCompression() EZMQXCompression {
+	cxtInstance.mutex.Lock()
+	defer cxtInstance.mutex.Unlock()
+	return cxtInstance.compression
+}
+
+func (cxtInstance *EZMQXContext) setFlowControl(flowControl *EZMQXFlowControl) {
+	cxtInstance.mutex.Lock()
+	defer cxtInstance.mutex.Unlock()
+	cxtInstance.flowControl = flowControl
+}
+
+func (cxtInstance *EZMQXContext) getFlowControl() *EZMQXFlowControl {
+	cxtInstance.mutex.Lock()
+	defer cxtInstance.mutex.Unlock()
+	return cxtInstance.flowControl
+}
+
+func (cxtInstance *EZMQXContext) setRegistrationCB(callback EZMQXRegistrationCB) {
+	cxtInstance.mutex.Lock()
+	defer cxtInstance.mutex.Unlock()
+	cxtInstance.registrationCB = callback
+}
+
+// Callback is read by topic handler routine, so it is guarded by context mutex.
+func (cxtInstance *EZMQXContext) getRegistrationCB() EZMQXRegistrationCB {
+	cxtInstance.mutex.Lock()
+	defer cxtInstance.mutex.Unlock()
+	return cxtInstance.registrationCB
+}
+
 func (cxtInstance *EZMQXContext) ctxGetTnsAddr() string {
 	return cxtInstance.tnsAddr
 }
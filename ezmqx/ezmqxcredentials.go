@@ -0,0 +1,141 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmqx
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Interface of credentials provider, which gives value of Authorization header for REST requests.
+// Rest client retries request once on HTTP_UNAUTHORIZED, after getting Authorization with refresh.
+type EZMQXCredentialsProvider interface {
+	// Returns value of Authorization header [e.g. "Bearer <token>"].
+	// refresh is true, if previous value was rejected by server.
+	GetAuthorization(ctx context.Context, refresh bool) (string, error)
+}
+
+// Callback to get bearer token. refresh is true, if previous token was rejected by server.
+type EZMQXTokenCB func(ctx context.Context, refresh bool) (string, error)
+
+// Structure represents credentials provider with static bearer token.
+type EZMQXStaticCredentials struct {
+	token string
+}
+
+// Get credentials provider with given bearer token.
+func GetStaticCredentials(token string) *EZMQXStaticCredentials {
+	var instance *EZMQXStaticCredentials
+	instance = &EZMQXStaticCredentials{}
+	instance.token = token
+	return instance
+}
+
+// Returns bearer Authorization of token.
+func (instance *EZMQXStaticCredentials) GetAuthorization(ctx context.Context, refresh bool) (string, error) {
+	return getBearerAuthorization(instance.token)
+}
+
+// Structure represents credentials provider which reads bearer token from file.
+type EZMQXFileCredentials struct {
+	path    string
+	token   string
+	modTime time.Time
+	size    int64
+	mutex   *sync.Mutex
+}
+
+// Get credentials provider which reads bearer token from given file.
+// File is read again when it is changed or when server rejects the token. Surrounding white spaces are removed.
+func GetFileCredentials(path string) *EZMQXFileCredentials {
+	var instance *EZMQXFileCredentials
+	instance = &EZMQXFileCredentials{}
+	instance.path = path
+	instance.mutex = &sync.Mutex{}
+	return instance
+}
+
+// Returns bearer Authorization of token in the file.
+func (instance *EZMQXFileCredentials) GetAuthorization(ctx context.Context, refresh bool) (string, error) {
+	instance.mutex.Lock()
+	defer instance.mutex.Unlock()
+	info, err := os.Stat(instance.path)
+	if err != nil {
+		return "", err
+	}
+	if refresh || 0 == len(instance.token) || !info.ModTime().Equal(instance.modTime) || info.Size() != instance.size {
+		data, err := ioutil.ReadFile(instance.path)
+		if err != nil {
+			return "", err
+		}
+		instance.token = strings.TrimSpace(string(data))
+		instance.modTime = info.ModTime()
+		instance.size = info.Size()
+	}
+	return getBearerAuthorization(instance.token)
+}
+
+// Structure represents credentials provider which gets bearer token from callback.
+type EZMQXCallbackCredentials struct {
+	callback EZMQXTokenCB
+}
+
+// Get credentials provider which gets bearer token from given callback.
+// Callback is called for every request, so it should cache the token if it is expensive to get.
+func GetCallbackCredentials(callback EZMQXTokenCB) *EZMQXCallbackCredentials {
+	var instance *EZMQXCallbackCredentials
+	instance = &EZMQXCallbackCredentials{}
+	instance.callback = callback
+	return instance
+}
+
+// Returns bearer Authorization of token given by callback.
+func (instance *EZMQXCallbackCredentials) GetAuthorization(ctx context.Context, refresh bool) (string, error) {
+	if nil == instance.callback {
+		return "", errors.New("token callback is nil")
+	}
+	token, err := instance.callback(ctx, refresh)
+	if err != nil {
+		return "", err
+	}
+	return getBearerAuthorization(token)
+}
+
+func getBearerAuthorization(token string) (string, error) {
+	if 0 == len(token) {
+		return "", errors.New("token is empty")
+	}
+	return BEARER_PREFIX + token, nil
+}
+
+// Set credentials provider for REST requests to pharos-node, anchor and TNS of this instance,
+// nil removes it. Authorization header is attached to every request made by rest client of ezmqx
+// [custom rest client factory should attach it by itself].
+// Provider can refresh credentials of a running instance by itself.
+func (configInstance *EZMQXConfig) SetCredentialsProvider(provider EZMQXCredentialsProvider) EZMQXErrorCode {
+	if result := configInstance.checkNotStarted("Set credentials provider"); result != EZMQX_OK {
+		return result
+	}
+	configInstance.context.getRestFactory().SetCredentialsProvider(provider)
+	return EZMQX_OK
+}
@@ -0,0 +1,157 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmqx
+
+import (
+	"encoding/binary"
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Envelope header: magic, version, sequence, timestamp, publisher ID length, publisher ID.
+// Magic starts with zero byte which is never a valid start of protobuf [AML] or JSON payload.
+const ENVELOPE_MAGIC = "\x00EZX"
+const ENVELOPE_VERSION = 1
+const ENVELOPE_HEADER_LENGTH = 22
+const ENVELOPE_MAX_ID_LENGTH = 255
+
+// Structure represents envelope of published message.
+type EZMQXEnvelope struct {
+	publisherId string
+	sequence    uint64
+	timeStamp   int64
+}
+
+// Get publisher ID of message.
+func (envelope *EZMQXEnvelope) GetPublisherId() string {
+	return envelope.publisherId
+}
+
+// Get sequence number of message on its topic.
+// Sequence number starts from 1 for each topic.
+func (envelope *EZMQXEnvelope) GetSequence() uint64 {
+	return envelope.sequence
+}
+
+// Get publish time of message as unix time in nano seconds.
+func (envelope *EZMQXEnvelope) GetTimeStamp() int64 {
+	return envelope.timeStamp
+}
+
+func encodeEnvelope(envelope *EZMQXEnvelope, data []byte) []byte {
+	idLength := len(envelope.publisherId)
+	buffer := make([]byte, ENVELOPE_HEADER_LENGTH+idLength+len(data))
+	copy(buffer, ENVELOPE_MAGIC)
+	buffer[4] = ENVELOPE_VERSION
+	binary.BigEndian.PutUint64(buffer[5:13], envelope.sequence)
+	binary.BigEndian.PutUint64(buffer[13:21], uint64(envelope.timeStamp))
+	buffer[21] = byte(idLength)
+	copy(buffer[ENVELOPE_HEADER_LENGTH:], envelope.publisherId)
+	copy(buffer[ENVELOPE_HEADER_LENGTH+idLength:], data)
+	return buffer
+}
+
+// Returns nil envelope and data as it is, if data does not have envelope.
+func decodeEnvelope(data []byte) (*EZMQXEnvelope, []byte) {
+	if len(data) < ENVELOPE_HEADER_LENGTH || string(data[0:4]) != ENVELOPE_MAGIC || data[4] != ENVELOPE_VERSION {
+		return nil, data
+	}
+	idLength := int(data[21])
+	if len(data) < ENVELOPE_HEADER_LENGTH+idLength {
+		return nil, data
+	}
+	envelope := &EZMQXEnvelope{}
+	envelope.sequence = binary.BigEndian.Uint64(data[5:13])
+	envelope.timeStamp = int64(binary.BigEndian.Uint64(data[13:21]))
+	envelope.publisherId = string(data[ENVELOPE_HEADER_LENGTH : ENVELOPE_HEADER_LENGTH+idLength])
+	return envelope, data[ENVELOPE_HEADER_LENGTH+idLength:]
+}
+
+func getUniquePublisherId() string {
+	return strconv.FormatInt(time.Now().UnixNano(), 36) + strconv.Itoa(rand.Intn(10000000))
+}
+
+// Sequencer assigns per topic sequence numbers on publisher side.
+type envelopeSequencer struct {
+	publisherId string
+	sequences   map[string]uint64
+	mutex       *sync.Mutex
+}
+
+func getEnvelopeSequencer(publisherId string) *envelopeSequencer {
+	var instance *envelopeSequencer
+	instance = &envelopeSequencer{}
+	instance.publisherId = publisherId
+	instance.sequences = make(map[string]uint64)
+	instance.mutex = &sync.Mutex{}
+	return instance
+}
+
+func (instance *envelopeSequencer) wrap(topic string, data []byte) []byte {
+	instance.mutex.Lock()
+	instance.sequences[topic]++
+	envelope := &EZMQXEnvelope{instance.publisherId, instance.sequences[topic], time.Now().UnixNano()}
+	instance.mutex.Unlock()
+	return encodeEnvelope(envelope, data)
+}
+
+// Tracker verifies sequence numbers per topic and publisher on subscriber side.
+type envelopeTracker struct {
+	sequences map[string]uint64
+	mutex     *sync.Mutex
+}
+
+func getEnvelopeTracker() *envelopeTracker {
+	var instance *envelopeTracker
+	instance = &envelopeTracker{}
+	instance.sequences = make(map[string]uint64)
+	instance.mutex = &sync.Mutex{}
+	return instance
+}
+
+// Returns EZMQX_OK, EZMQX_MESSAGE_GAP or EZMQX_DUPLICATED_MESSAGE.
+// First message of a publisher and restart of sequence from 1 are not treated as gap/duplicate.
+func (instance *envelopeTracker) track(topic string, envelope *EZMQXEnvelope) EZMQXErrorCode {
+	key := topic + SLASH + envelope.publisherId
+	instance.mutex.Lock()
+	defer instance.mutex.Unlock()
+	last, exists := instance.sequences[key]
+	if !exists || 1 == envelope.sequence {
+		instance.sequences[key] = envelope.sequence
+		return EZMQX_OK
+	}
+	if envelope.sequence <= last {
+		return EZMQX_DUPLICATED_MESSAGE
+	}
+	instance.sequences[key] = envelope.sequence
+	if envelope.sequence != last+1 {
+		return EZMQX_MESSAGE_GAP
+	}
+	return EZMQX_OK
+}
+
+// Checks whether message with given envelope or a newer one is already tracked.
+func (instance *envelopeTracker) isDelivered(topic string, envelope *EZMQXEnvelope) bool {
+	key := topic + SLASH + envelope.publisherId
+	instance.mutex.Lock()
+	defer instance.mutex.Unlock()
+	last, exists := instance.sequences[key]
+	return exists && envelope.sequence <= last
+}
@@ -0,0 +1,165 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmqx
+
+import (
+	"errors"
+	"strconv"
+)
+
+var errorCodeNames = map[EZMQXErrorCode]string{
+	EZMQX_OK:                  "EZMQX_OK",
+	EZMQX_INVALID_PARAM:       "EZMQX_INVALID_PARAM",
+	EZMQX_INITIALIZED:         "EZMQX_INITIALIZED",
+	EZMQX_NOT_INITIALIZED:     "EZMQX_NOT_INITIALIZED",
+	EZMQX_TERMINATED:          "EZMQX_TERMINATED",
+	EZMQX_UNKNOWN_STATE:       "EZMQX_UNKNOWN_STATE",
+	EZMQX_SERVICE_UNAVAILABLE: "EZMQX_SERVICE_UNAVAILABLE",
+	EZMQX_INVALID_TOPIC:       "EZMQX_INVALID_TOPIC",
+	EZMQX_DUPLICATED_TOPIC:    "EZMQX_DUPLICATED_TOPIC",
+	EZMQX_UNKNOWN_TOPIC:       "EZMQX_UNKNOWN_TOPIC",
+	EZMQX_INVALID_ENDPOINT:    "EZMQX_INVALID_ENDPOINT",
+	EZMQX_BROKEN_PAYLOAD:      "EZMQX_BROKEN_PAYLOAD",
+	EZMQX_REST_ERROR:          "EZMQX_REST_ERROR",
+	EZMQX_MAXIMUM_PORT_EXCEED: "EZMQX_MAXIMUM_PORT_EXCEED",
+	EZMQX_RELEASE_WRONG_PORT:  "EZMQX_RELEASE_WRONG_PORT",
+	EZMQX_NO_TOPIC_MATCHED:    "EZMQX_NO_TOPIC_MATCHED",
+	EZMQX_TNS_NOT_AVAILABLE:   "EZMQX_TNS_NOT_AVAILABLE",
+	EZMQX_UNKNOWN_AML_MODEL:   "EZMQX_UNKNOWN_AML_MODEL",
+	EZMQX_INVALID_AML_MODEL:   "EZMQX_INVALID_AML_MODEL",
+	EZMQX_SESSION_UNAVAILABLE: "EZMQX_SESSION_UNAVAILABLE",
+	EZMQX_UNKNOWN_PROTO_MODEL: "EZMQX_UNKNOWN_PROTO_MODEL",
+	EZMQX_INVALID_PROTO_MODEL: "EZMQX_INVALID_PROTO_MODEL",
+	EZMQX_MESSAGE_GAP:         "EZMQX_MESSAGE_GAP",
+	EZMQX_DUPLICATED_MESSAGE:  "EZMQX_DUPLICATED_MESSAGE",
+	EZMQX_UNKNOWN_COMPRESSION: "EZMQX_UNKNOWN_COMPRESSION",
+	EZMQX_MESSAGE_DROPPED:     "EZMQX_MESSAGE_DROPPED",
+	EZMQX_CANCELED:            "EZMQX_CANCELED",
+}
+
+// Error returns name of the error code, so that error code can be used as error value.
+//
+// For example: errors.Is(err, EZMQXErrorCode(EZMQX_REST_ERROR))
+func (errorCode EZMQXErrorCode) Error() string {
+	name, exists := errorCodeNames[errorCode]
+	if !exists {
+		return "EZMQX_ERROR(" + strconv.Itoa(int(errorCode)) + ")"
+	}
+	return name
+}
+
+// Structure represents EZMQX error.
+// It wraps EZMQX error code with the operation that failed and underlying cause of failure.
+type EZMQXError struct {
+	code  EZMQXErrorCode
+	op    string
+	cause error
+}
+
+// Get EZMQX error instance.
+// Cause can be nil, if there is no underlying cause.
+func GetEZMQXError(code EZMQXErrorCode, op string, cause error) *EZMQXError {
+	var instance *EZMQXError
+	instance = &EZMQXError{}
+	instance.code = code
+	instance.op = op
+	instance.cause = cause
+	return instance
+}
+
+// Get error code.
+func (instance *EZMQXError) GetErrorCode() EZMQXErrorCode {
+	return instance.code
+}
+
+// Get operation which failed.
+func (instance *EZMQXError) GetOperation() string {
+	return instance.op
+}
+
+// Get underlying cause, nil if there is no underlying cause.
+func (instance *EZMQXError) GetCause() error {
+	return instance.cause
+}
+
+// Error returns error in "operation: cause [error code]" form.
+func (instance *EZMQXError) Error() string {
+	message := instance.code.Error()
+	if nil != instance.cause {
+		message = instance.cause.Error() + " [" + message + "]"
+	}
+	if 0 == len(instance.op) {
+		return message
+	}
+	return instance.op + ": " + message
+}
+
+// Unwrap returns underlying cause.
+func (instance *EZMQXError) Unwrap() error {
+	return instance.cause
+}
+
+// Is reports whether target is same error code as of this error.
+// Target can be EZMQXErrorCode or *EZMQXError.
+func (instance *EZMQXError) Is(target error) bool {
+	switch value := target.(type) {
+	case EZMQXErrorCode:
+		return instance.code == value
+	case *EZMQXError:
+		return instance.code == value.code
+	}
+	return false
+}
+
+// Get error code from given error.
+// Returns EZMQX_OK if err is nil and EZMQX_UNKNOWN_STATE if err is not an EZMQX error.
+func GetErrorCode(err error) EZMQXErrorCode {
+	if nil == err {
+		return EZMQX_OK
+	}
+	for ; nil != err; err = unwrap(err) {
+		switch value := err.(type) {
+		case *EZMQXError:
+			return value.code
+		case EZMQXErrorCode:
+			return value
+		}
+	}
+	return EZMQX_UNKNOWN_STATE
+}
+
+func unwrap(err error) error {
+	wrapper, ok := err.(interface{ Unwrap() error })
+	if !ok {
+		return nil
+	}
+	return wrapper.Unwrap()
+}
+
+// Returns nil for EZMQX_OK, otherwise EZMQX error for given error code and operation.
+func toError(code EZMQXErrorCode, op string) error {
+	if EZMQX_OK == code {
+		return nil
+	}
+	return GetEZMQXError(code, op, nil)
+}
+
+// Returns EZMQX error with cause created from given message.
+func newError(code EZMQXErrorCode, op string, message string) error {
+	return GetEZMQXError(code, op, errors.New(message))
+}
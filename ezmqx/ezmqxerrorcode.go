@@ -41,4 +41,11 @@ const (
 	EZMQX_UNKNOWN_AML_MODEL   = 17
 	EZMQX_INVALID_AML_MODEL   = 18
 	EZMQX_SESSION_UNAVAILABLE = 19
+	EZMQX_UNKNOWN_PROTO_MODEL = 20
+	EZMQX_INVALID_PROTO_MODEL = 21
+	EZMQX_MESSAGE_GAP         = 22
+	EZMQX_DUPLICATED_MESSAGE  = 23
+	EZMQX_UNKNOWN_COMPRESSION = 24
+	EZMQX_MESSAGE_DROPPED     = 25
+	EZMQX_CANCELED            = 26
 )
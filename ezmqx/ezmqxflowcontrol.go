@@ -0,0 +1,306 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmqx
+
+import (
+	"go/aml"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Default queue size and linger of asynchronous publish, used if send queue size is not set.
+const ASYNC_QUEUE_SIZE = 1000
+const ASYNC_LINGER = 1000 * time.Millisecond
+
+type EZMQXOverflowPolicy int
+
+// Constants represents policy when queue is full.
+const (
+	OVERFLOW_DROP  EZMQXOverflowPolicy = 0
+	OVERFLOW_BLOCK EZMQXOverflowPolicy = 1
+)
+
+// Structure represents flow control of publishers and subscribers.
+// Queues are bounded ezmqx queues in front of ezmq sockets. ZMQ high water marks of sockets are
+// not changed, as EZMQ API does not expose socket options.
+type EZMQXFlowControl struct {
+	sendQueueSize    int
+	receiveQueueSize int
+	linger           time.Duration
+	policy           EZMQXOverflowPolicy
+}
+
+// Get EZMQX flow control instance.
+//
+// sendQueueSize: Maximum number of messages queued for send on publisher. Zero means publish on caller's goroutine.
+// receiveQueueSize: Maximum number of received messages queued for callback on subscriber. Zero means callback on receiver goroutine.
+// linger: Maximum time to deliver queued messages on terminate. Messages left after linger are dropped.
+// policy: Drop the message or block the caller when queue is full.
+func GetEZMQXFlowControl(sendQueueSize int, receiveQueueSize int, linger time.Duration, policy EZMQXOverflowPolicy) *EZMQXFlowControl {
+	var instance *EZMQXFlowControl
+	instance = &EZMQXFlowControl{}
+	instance.sendQueueSize = sendQueueSize
+	instance.receiveQueueSize = receiveQueueSize
+	instance.linger = linger
+	instance.policy = policy
+	return instance
+}
+
+// Get send queue size.
+func (flowControl *EZMQXFlowControl) GetSendQueueSize() int {
+	return flowControl.sendQueueSize
+}
+
+// Get receive queue size.
+func (flowControl *EZMQXFlowControl) GetReceiveQueueSize() int {
+	return flowControl.receiveQueueSize
+}
+
+// Get linger time.
+func (flowControl *EZMQXFlowControl) GetLinger() time.Duration {
+	return flowControl.linger
+}
+
+// Get overflow policy.
+func (flowControl *EZMQXFlowControl) GetPolicy() EZMQXOverflowPolicy {
+	return flowControl.policy
+}
+
+func (flowControl *EZMQXFlowControl) isValid() bool {
+	if flowControl.sendQueueSize < 0 || flowControl.receiveQueueSize < 0 || flowControl.linger < 0 {
+		return false
+	}
+	return flowControl.policy == OVERFLOW_DROP || flowControl.policy == OVERFLOW_BLOCK
+}
+
+// Object is set instead of data if serialization is done by handler.
+// Callback is called with handler result, or EZMQX_TERMINATED if message is dropped on terminate.
+type queuedMessage struct {
+	topic    string
+	data     []byte
+	object   *aml.AMLObject
+	callback func(errorCode EZMQXErrorCode)
+}
+
+type messageHandler func(message queuedMessage) EZMQXErrorCode
+
+// Bounded queue drained by a single routine which calls handler for each message.
+// Callbacks of messages are called in order on another routine, so that callback can close the queue.
+type messageQueue struct {
+	// dropped is first field to keep 64 bit alignment for atomic operations
+	dropped  uint64
+	discard  uint32
+	queue    chan queuedMessage
+	handler  messageHandler
+	policy   EZMQXOverflowPolicy
+	linger   time.Duration
+	closed   bool
+	mutex    *sync.RWMutex
+	once     *sync.Once
+	stopChan chan bool
+	doneChan chan bool
+	// callbacks waiting to be called, drained is set after last message is handled
+	callbacks     []func()
+	callbackMutex *sync.Mutex
+	callbackCond  *sync.Cond
+	drained       bool
+}
+
+func getMessageQueue(size int, policy EZMQXOverflowPolicy, linger time.Duration, handler messageHandler) *messageQueue {
+	var instance *messageQueue
+	instance = &messageQueue{}
+	instance.queue = make(chan queuedMessage, size)
+	instance.handler = handler
+	instance.policy = policy
+	instance.linger = linger
+	instance.mutex = &sync.RWMutex{}
+	instance.once = &sync.Once{}
+	instance.stopChan = make(chan bool)
+	instance.doneChan = make(chan bool)
+	instance.callbackMutex = &sync.Mutex{}
+	instance.callbackCond = sync.NewCond(instance.callbackMutex)
+	go instance.drain()
+	go instance.runCallbacks()
+	return instance
+}
+
+func (instance *messageQueue) drain() {
+	for message := range instance.queue {
+		var result EZMQXErrorCode = EZMQX_TERMINATED
+		if 1 == atomic.LoadUint32(&instance.discard) {
+			atomic.AddUint64(&instance.dropped, 1)
+		} else {
+			result = instance.handler(message)
+		}
+		if nil != message.callback {
+			callback := message.callback
+			instance.dispatch(func() { callback(result) })
+		}
+	}
+	instance.callbackMutex.Lock()
+	instance.drained = true
+	instance.callbackCond.Signal()
+	instance.callbackMutex.Unlock()
+	instance.doneChan <- true
+}
+
+func (instance *messageQueue) dispatch(callback func()) {
+	instance.callbackMutex.Lock()
+	defer instance.callbackMutex.Unlock()
+	instance.callbacks = append(instance.callbacks, callback)
+	instance.callbackCond.Signal()
+}
+
+// Calls dispatched callbacks till queue is drained.
+func (instance *messageQueue) runCallbacks() {
+	for {
+		instance.callbackMutex.Lock()
+		for 0 == len(instance.callbacks) && !instance.drained {
+			instance.callbackCond.Wait()
+		}
+		callbacks := instance.callbacks
+		instance.callbacks = nil
+		instance.callbackMutex.Unlock()
+		if 0 == len(callbacks) {
+			return
+		}
+		for _, callback := range callbacks {
+			callback()
+		}
+	}
+}
+
+func (instance *messageQueue) push(message queuedMessage) EZMQXErrorCode {
+	instance.mutex.RLock()
+	defer instance.mutex.RUnlock()
+	if instance.closed {
+		return EZMQX_TERMINATED
+	}
+	if instance.policy == OVERFLOW_BLOCK {
+		// Blocked sender is released on close, so that close can take the lock
+		select {
+		case instance.queue <- message:
+			return EZMQX_OK
+		case <-instance.stopChan:
+			return EZMQX_TERMINATED
+		}
+	}
+	select {
+	case instance.queue <- message:
+		return EZMQX_OK
+	default:
+		atomic.AddUint64(&instance.dropped, 1)
+		return EZMQX_MESSAGE_DROPPED
+	}
+}
+
+// Waits till queued messages are handled or linger is expired.
+// Callbacks of handled messages may be called after it returns.
+func (instance *messageQueue) close() {
+	instance.once.Do(func() {
+		close(instance.stopChan)
+	})
+	instance.mutex.Lock()
+	if instance.closed {
+		instance.mutex.Unlock()
+		return
+	}
+	instance.closed = true
+	close(instance.queue)
+	instance.mutex.Unlock()
+	select {
+	case <-instance.doneChan:
+		return
+	case <-time.After(instance.linger):
+		Logger.Debug("Linger expired, dropping queued messages")
+		atomic.StoreUint32(&instance.discard, 1)
+	}
+	<-instance.doneChan
+}
+
+func (instance *messageQueue) getDroppedCount() uint64 {
+	return atomic.LoadUint64(&instance.dropped)
+}
+
+// Guards sending of received messages on consumer's channel.
+// Senders are released on close, so that channel can be closed safely.
+type channelGate struct {
+	// dropped is first field to keep 64 bit alignment for atomic operations
+	dropped  uint64
+	policy   EZMQXOverflowPolicy
+	closed   bool
+	mutex    *sync.RWMutex
+	once     *sync.Once
+	doneChan chan bool
+}
+
+func getChannelGate(policy EZMQXOverflowPolicy) *channelGate {
+	var instance *channelGate
+	instance = &channelGate{}
+	instance.policy = policy
+	instance.mutex = &sync.RWMutex{}
+	instance.once = &sync.Once{}
+	instance.doneChan = make(chan bool)
+	return instance
+}
+
+// Returns false if gate is closed, otherwise caller should call leave after sending.
+func (instance *channelGate) enter() bool {
+	instance.mutex.RLock()
+	if instance.closed {
+		instance.mutex.RUnlock()
+		return false
+	}
+	return true
+}
+
+func (instance *channelGate) leave() {
+	instance.mutex.RUnlock()
+}
+
+// Stops sending, senders blocked on full channel are released.
+func (instance *channelGate) stop() {
+	instance.once.Do(func() {
+		close(instance.doneChan)
+	})
+}
+
+// Stops sending and closes channel using given function, once all senders left.
+func (instance *channelGate) close(closeChannel func()) {
+	instance.stop()
+	instance.mutex.Lock()
+	defer instance.mutex.Unlock()
+	if instance.closed {
+		return
+	}
+	instance.closed = true
+	closeChannel()
+}
+
+func (instance *channelGate) drop() {
+	atomic.AddUint64(&instance.dropped, 1)
+}
+
+func (instance *channelGate) getDroppedCount() uint64 {
+	return atomic.LoadUint64(&instance.dropped)
+}
+
+func isValidChannel(bufferSize int, policy EZMQXOverflowPolicy) bool {
+	return bufferSize >= 0 && (policy == OVERFLOW_DROP || policy == OVERFLOW_BLOCK)
+}
@@ -0,0 +1,56 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmqx
+
+import "context"
+
+// Structure represents EZMQX JSON publisher.
+// Value is marshalled using encoding/json, json.RawMessage is published as it is.
+type EZMQXJSONPublisher struct {
+	EZMQXTypedPublisher[interface{}]
+}
+
+// Get EZMQX JSON publisher instance.
+// Topic is registered to TNS with JSON_DATA_MODEL as data model.
+func GetJSONPublisher(topic string, optionalPort int) (*EZMQXJSONPublisher, EZMQXErrorCode) {
+	instance, err := GetJSONPublisherWithContext(context.Background(), topic, optionalPort, nil)
+	return instance, GetErrorCode(err)
+}
+
+// Get EZMQX JSON publisher instance with context.
+// TNS register request is canceled when ctx is done, deadline of ctx is applied to it.
+// On failure, returned error is *EZMQXError which holds error code along with failed operation and cause.
+// Flow control of config [SetFlowControl] is used if optionalFlowControl is nil.
+func GetJSONPublisherWithContext(ctx context.Context, topic string, optionalPort int, optionalFlowControl *EZMQXFlowControl) (*EZMQXJSONPublisher, error) {
+	return GetConfigInstance().GetJSONPublisherWithContext(ctx, topic, optionalPort, optionalFlowControl)
+}
+
+// Get EZMQX JSON publisher instance with context.
+// TNS register request is canceled when ctx is done, deadline of ctx is applied to it.
+// On failure, returned error is *EZMQXError which holds error code along with failed operation and cause.
+// Flow control of config [SetFlowControl] is used if optionalFlowControl is nil.
+func (configInstance *EZMQXConfig) GetJSONPublisherWithContext(ctx context.Context, topic string, optionalPort int, optionalFlowControl *EZMQXFlowControl) (*EZMQXJSONPublisher, error) {
+	publisher, err := createTypedPublisher(ctx, configInstance.context, topic, GetJSONCodec(), optionalPort, optionalFlowControl)
+	if err != nil {
+		return nil, err
+	}
+	var instance *EZMQXJSONPublisher
+	instance = &EZMQXJSONPublisher{}
+	instance.EZMQXTypedPublisher = *publisher
+	return instance, nil
+}
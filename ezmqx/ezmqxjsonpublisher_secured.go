@@ -0,0 +1,26 @@
+// +build !unsecure
+
+package ezmqx
+
+// Get Secured EZMQX JSON publisher instance.
+//
+// Note:
+// (1) Key should be 40-character string encoded in the Z85 encoding format
+func GetSecuredJSONPublisher(topic string, serverPrivateKey string, optionalPort int) (*EZMQXJSONPublisher, EZMQXErrorCode) {
+	return GetConfigInstance().GetSecuredJSONPublisher(topic, serverPrivateKey, optionalPort)
+}
+
+// Get Secured EZMQX JSON publisher instance.
+//
+// Note:
+// (1) Key should be 40-character string encoded in the Z85 encoding format
+func (configInstance *EZMQXConfig) GetSecuredJSONPublisher(topic string, serverPrivateKey string, optionalPort int) (*EZMQXJSONPublisher, EZMQXErrorCode) {
+	publisher, result := createSecuredTypedPublisher(configInstance.context, topic, serverPrivateKey, GetJSONCodec(), optionalPort)
+	if result != EZMQX_OK {
+		return nil, result
+	}
+	var instance *EZMQXJSONPublisher
+	instance = &EZMQXJSONPublisher{}
+	instance.EZMQXTypedPublisher = *publisher
+	return instance, EZMQX_OK
+}
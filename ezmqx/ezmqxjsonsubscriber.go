@@ -0,0 +1,105 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmqx
+
+import (
+	"container/list"
+	"context"
+	"go.uber.org/zap"
+)
+
+// Callback to get all the subscribed events for a specific topic.
+// Value is decoded using encoding/json into interface{}.
+type EZMQXJsonSubCB func(topic string, value interface{})
+
+// Callback to get error for the subscribed topic.
+type EZMQXJsonErrorCB func(topic string, errorCode EZMQXErrorCode)
+
+// Structure represents EZMQX JSON subscriber.
+type EZMQXJSONSubscriber struct {
+	EZMQXTypedSubscriber[interface{}]
+}
+
+// Get JSON subscriber instance for given topic.
+// It will work, if EZMQX is configured in docker mode.
+func GetJSONSubscriber(topic string, isHierarchical bool, subCallback EZMQXJsonSubCB, errorCallback EZMQXJsonErrorCB) (*EZMQXJSONSubscriber, EZMQXErrorCode) {
+	instance, err := GetJSONSubscriberWithContext(context.Background(), topic, isHierarchical, subCallback, errorCallback, nil)
+	return instance, GetErrorCode(err)
+}
+
+// Get JSON subscriber instance for given topic with context.
+// TNS query request is canceled when ctx is done, deadline of ctx is applied to it.
+// On failure, returned error is *EZMQXError which holds error code along with failed operation and cause.
+// Flow control of config [SetFlowControl] is used if optionalFlowControl is nil.
+func GetJSONSubscriberWithContext(ctx context.Context, topic string, isHierarchical bool, subCallback EZMQXJsonSubCB, errorCallback EZMQXJsonErrorCB, optionalFlowControl *EZMQXFlowControl) (*EZMQXJSONSubscriber, error) {
+	return GetConfigInstance().GetJSONSubscriberWithContext(ctx, topic, isHierarchical, subCallback, errorCallback, optionalFlowControl)
+}
+
+// Get JSON subscriber instance for given topic with context.
+// TNS query request is canceled when ctx is done, deadline of ctx is applied to it.
+// On failure, returned error is *EZMQXError which holds error code along with failed operation and cause.
+// Flow control of config [SetFlowControl] is used if optionalFlowControl is nil.
+func (configInstance *EZMQXConfig) GetJSONSubscriberWithContext(ctx context.Context, topic string, isHierarchical bool, subCallback EZMQXJsonSubCB, errorCallback EZMQXJsonErrorCB, optionalFlowControl *EZMQXFlowControl) (*EZMQXJSONSubscriber, error) {
+	instance := createJsonSubscriber(configInstance.context, optionalFlowControl, subCallback, errorCallback)
+	err := instance.subscriber.initialize(ctx, topic, isHierarchical)
+	if err != nil {
+		Logger.Error("initialization failed", zap.Error(err))
+		return nil, err
+	}
+	return instance, nil
+}
+
+// Get JSON subscriber instance for given topic.
+// It will work, if EZMQX is configured in standalone mode.
+func GetJSONStandAloneSubscriber(topic EZMQXTopic, subCallback EZMQXJsonSubCB, errorCallback EZMQXJsonErrorCB) (*EZMQXJSONSubscriber, EZMQXErrorCode) {
+	return GetConfigInstance().GetJSONStandAloneSubscriber(topic, subCallback, errorCallback)
+}
+
+// Get JSON subscriber instance for given topic.
+// It will work, if EZMQX is configured in standalone mode.
+func (configInstance *EZMQXConfig) GetJSONStandAloneSubscriber(topic EZMQXTopic, subCallback EZMQXJsonSubCB, errorCallback EZMQXJsonErrorCB) (*EZMQXJSONSubscriber, EZMQXErrorCode) {
+	ezmqxTopicList := list.New()
+	ezmqxTopicList.PushBack(topic)
+	return configInstance.GetJSONStandAloneSubscriber1(*ezmqxTopicList, subCallback, errorCallback)
+}
+
+// Get JSON subscriber instance for given topic list.
+// It will work, if EZMQX is configured in standalone mode.
+func GetJSONStandAloneSubscriber1(topics list.List, subCallback EZMQXJsonSubCB, errorCallback EZMQXJsonErrorCB) (*EZMQXJSONSubscriber, EZMQXErrorCode) {
+	return GetConfigInstance().GetJSONStandAloneSubscriber1(topics, subCallback, errorCallback)
+}
+
+// Get JSON subscriber instance for given topic list.
+// It will work, if EZMQX is configured in standalone mode.
+func (configInstance *EZMQXConfig) GetJSONStandAloneSubscriber1(topics list.List, subCallback EZMQXJsonSubCB, errorCallback EZMQXJsonErrorCB) (*EZMQXJSONSubscriber, EZMQXErrorCode) {
+	instance := createJsonSubscriber(configInstance.context, nil, subCallback, errorCallback)
+	result := instance.subscriber.storeTopics(topics)
+	if result != EZMQX_OK {
+		Logger.Error("Store topic failed", zap.Int("Error code:", int(result)))
+		return nil, result
+	}
+	return instance, result
+}
+
+func createJsonSubscriber(context *EZMQXContext, flowControl *EZMQXFlowControl, subCallback EZMQXJsonSubCB, errorCallback EZMQXJsonErrorCB) *EZMQXJSONSubscriber {
+	var instance *EZMQXJSONSubscriber
+	instance = &EZMQXJSONSubscriber{}
+	instance.EZMQXTypedSubscriber = *createTypedSubscriber(context, flowControl, GetCodecResolver(GetJSONCodec()),
+		EZMQXTypedSubCB[interface{}](subCallback), EZMQXSubErrorCB(errorCallback))
+	return instance
+}
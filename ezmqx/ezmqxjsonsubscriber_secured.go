@@ -0,0 +1,56 @@
+// +build !unsecure
+
+package ezmqx
+
+import (
+	"go.uber.org/zap"
+)
+
+// Get secured JSON subscriber instance for given topic.
+//
+// Note:
+// (1) Key should be 40-character string encoded in the Z85 encoding format
+func GetSecuredJSONSubscriber(topic EZMQXTopic, serverPublicKey string, clientPublicKey string, clientSecretKey string, subCallback EZMQXJsonSubCB, errorCallback EZMQXJsonErrorCB) (*EZMQXJSONSubscriber, EZMQXErrorCode) {
+	return GetConfigInstance().GetSecuredJSONSubscriber(topic, serverPublicKey, clientPublicKey, clientSecretKey, subCallback, errorCallback)
+}
+
+// Get secured JSON subscriber instance for given topic.
+//
+// Note:
+// (1) Key should be 40-character string encoded in the Z85 encoding format
+func (configInstance *EZMQXConfig) GetSecuredJSONSubscriber(topic EZMQXTopic, serverPublicKey string, clientPublicKey string, clientSecretKey string, subCallback EZMQXJsonSubCB, errorCallback EZMQXJsonErrorCB) (*EZMQXJSONSubscriber, EZMQXErrorCode) {
+	topicKeyMap := make(map[EZMQXTopic]string)
+	topicKeyMap[topic] = serverPublicKey
+	return configInstance.GetSecuredJSONSubscriber1(topicKeyMap, clientPublicKey, clientSecretKey, subCallback, errorCallback)
+}
+
+// Get secured JSON subscriber instance for given topic.
+//
+// Note:
+// (1) Key should be 40-character string encoded in the Z85 encoding format
+func GetSecuredJSONSubscriber1(topicKeyMap map[EZMQXTopic]string, clientPublicKey string, clientSecretKey string, subCallback EZMQXJsonSubCB, errorCallback EZMQXJsonErrorCB) (*EZMQXJSONSubscriber, EZMQXErrorCode) {
+	return GetConfigInstance().GetSecuredJSONSubscriber1(topicKeyMap, clientPublicKey, clientSecretKey, subCallback, errorCallback)
+}
+
+// Get secured JSON subscriber instance for given topic.
+//
+// Note:
+// (1) Key should be 40-character string encoded in the Z85 encoding format
+func (configInstance *EZMQXConfig) GetSecuredJSONSubscriber1(topicKeyMap map[EZMQXTopic]string, clientPublicKey string, clientSecretKey string, subCallback EZMQXJsonSubCB, errorCallback EZMQXJsonErrorCB) (*EZMQXJSONSubscriber, EZMQXErrorCode) {
+	for topic, _ := range topicKeyMap {
+		if !topic.IsSecured() {
+			return nil, EZMQX_INVALID_PARAM
+		}
+	}
+	instance := createJsonSubscriber(configInstance.context, nil, subCallback, errorCallback)
+	var result EZMQXErrorCode = EZMQX_INVALID_PARAM
+	for topic, serverKey := range topicKeyMap {
+		result = instance.subscriber.storeSecuredTopics(topic, serverKey, clientPublicKey, clientSecretKey)
+		if result != EZMQX_OK {
+			Logger.Error("Store topic failed", zap.Int("Error code:", int(result)))
+			return nil, result
+		}
+	}
+	instance.isSecured = true
+	return instance, result
+}
@@ -0,0 +1,206 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmqx
+
+import (
+	"container/list"
+	zmq "github.com/pebbe/zmq4"
+	"go.uber.org/zap"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const SNAPSHOT_BIND_PREFIX = "tcp://*:"
+const SNAPSHOT_CONNECT_PREFIX = "tcp://"
+const SNAPSHOT_EPHEMERAL_PORT = "*"
+const SNAPSHOT_POLL_INTERVAL = 100 * time.Millisecond
+const SNAPSHOT_TIMEOUT = 3000 * time.Millisecond
+
+// Keeps last N messages per topic and serves them to subscribers on a side-channel REP socket.
+// Request is a single frame with topic name, reply is count frame followed by cached messages [oldest first].
+type lastValueCache struct {
+	socket       *zmq.Socket
+	depth        int
+	port         int
+	messages     map[string]*list.List
+	mutex        *sync.Mutex
+	shutdownChan chan bool
+	stoppedChan  chan bool
+}
+
+// If port is zero, cache is bound on an ephemeral port.
+func getLastValueCache(depth int, port int) (*lastValueCache, EZMQXErrorCode) {
+	var instance *lastValueCache
+	instance = &lastValueCache{}
+	socket, err := zmq.NewSocket(zmq.REP)
+	if err != nil {
+		Logger.Error("Could not create snapshot socket")
+		return nil, EZMQX_UNKNOWN_STATE
+	}
+	address := SNAPSHOT_BIND_PREFIX + SNAPSHOT_EPHEMERAL_PORT
+	if 0 != port {
+		address = SNAPSHOT_BIND_PREFIX + strconv.Itoa(port)
+	}
+	err = socket.Bind(address)
+	if err != nil {
+		Logger.Error("Could not bind snapshot socket", zap.String("Address: ", address))
+		socket.Close()
+		return nil, EZMQX_UNKNOWN_STATE
+	}
+	if 0 == port {
+		endPoint, err := socket.GetLastEndpoint()
+		if err == nil {
+			port, err = strconv.Atoi(endPoint[strings.LastIndex(endPoint, COLON)+1:])
+		}
+		if err != nil {
+			Logger.Error("Could not get snapshot port")
+			socket.Close()
+			return nil, EZMQX_UNKNOWN_STATE
+		}
+	}
+	instance.socket = socket
+	instance.depth = depth
+	instance.port = port
+	instance.messages = make(map[string]*list.List)
+	instance.mutex = &sync.Mutex{}
+	instance.shutdownChan = make(chan bool)
+	instance.stoppedChan = make(chan bool)
+	go instance.serve()
+	Logger.Debug("Started last value cache", zap.Int("Port: ", port))
+	return instance, EZMQX_OK
+}
+
+func (instance *lastValueCache) getPort() int {
+	return instance.port
+}
+
+func (instance *lastValueCache) store(topic string, data []byte) {
+	instance.mutex.Lock()
+	defer instance.mutex.Unlock()
+	messages := instance.messages[topic]
+	if nil == messages {
+		messages = list.New()
+		instance.messages[topic] = messages
+	}
+	cached := make([]byte, len(data))
+	copy(cached, data)
+	messages.PushBack(cached)
+	for messages.Len() > instance.depth {
+		messages.Remove(messages.Front())
+	}
+}
+
+func (instance *lastValueCache) remove(topic string) {
+	instance.mutex.Lock()
+	defer instance.mutex.Unlock()
+	delete(instance.messages, topic)
+}
+
+func (instance *lastValueCache) getSnapshot(topic string) [][]byte {
+	instance.mutex.Lock()
+	defer instance.mutex.Unlock()
+	reply := [][]byte{[]byte("0")}
+	messages := instance.messages[topic]
+	if nil == messages {
+		return reply
+	}
+	reply[0] = []byte(strconv.Itoa(messages.Len()))
+	for message := messages.Front(); message != nil; message = message.Next() {
+		reply = append(reply, message.Value.([]byte))
+	}
+	return reply
+}
+
+// Socket is used only by this routine till it is closed.
+func (instance *lastValueCache) serve() {
+	poller := zmq.NewPoller()
+	poller.Add(instance.socket, zmq.POLLIN)
+	for {
+		select {
+		case <-instance.shutdownChan:
+			instance.socket.Close()
+			instance.stoppedChan <- true
+			return
+		default:
+		}
+		sockets, err := poller.Poll(SNAPSHOT_POLL_INTERVAL)
+		if err != nil || 0 == len(sockets) {
+			continue
+		}
+		request, err := instance.socket.RecvMessage(0)
+		if err != nil {
+			Logger.Error("Snapshot request receive failed")
+			continue
+		}
+		topic := EMPTY_STRING
+		if len(request) > 0 {
+			topic = request[0]
+		}
+		_, err = instance.socket.SendMessage(instance.getSnapshot(topic))
+		if err != nil {
+			Logger.Error("Snapshot reply send failed", zap.String("Topic: ", topic))
+		}
+	}
+}
+
+func (instance *lastValueCache) stop() {
+	instance.shutdownChan <- true
+	<-instance.stoppedChan
+	Logger.Debug("Stopped last value cache")
+}
+
+// Fetch cached messages of the given topic from publisher's snapshot end point.
+func fetchSnapshot(endPoint *EZMQXEndpoint, topic string) ([][]byte, EZMQXErrorCode) {
+	socket, err := zmq.NewSocket(zmq.REQ)
+	if err != nil {
+		Logger.Error("Could not create snapshot socket")
+		return nil, EZMQX_UNKNOWN_STATE
+	}
+	defer socket.Close()
+	socket.SetLinger(0)
+	err = socket.Connect(SNAPSHOT_CONNECT_PREFIX + endPoint.ToString())
+	if err != nil {
+		Logger.Error("Could not connect snapshot socket")
+		return nil, EZMQX_SESSION_UNAVAILABLE
+	}
+	_, err = socket.SendMessage(topic)
+	if err != nil {
+		Logger.Error("Snapshot request send failed")
+		return nil, EZMQX_SESSION_UNAVAILABLE
+	}
+	poller := zmq.NewPoller()
+	poller.Add(socket, zmq.POLLIN)
+	sockets, err := poller.Poll(SNAPSHOT_TIMEOUT)
+	if err != nil || 0 == len(sockets) {
+		Logger.Error("Snapshot request timed out", zap.String("Topic: ", topic))
+		return nil, EZMQX_SESSION_UNAVAILABLE
+	}
+	reply, err := socket.RecvMessageBytes(0)
+	if err != nil || 0 == len(reply) {
+		Logger.Error("Snapshot reply receive failed")
+		return nil, EZMQX_SESSION_UNAVAILABLE
+	}
+	count, err := strconv.Atoi(string(reply[0]))
+	if err != nil || count != len(reply)-1 {
+		Logger.Error("Invalid snapshot reply")
+		return nil, EZMQX_BROKEN_PAYLOAD
+	}
+	return reply[1:], EZMQX_OK
+}
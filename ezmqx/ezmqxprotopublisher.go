@@ -0,0 +1,67 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmqx
+
+import (
+	"context"
+	"google.golang.org/protobuf/proto"
+)
+
+// Structure represents EZMQX protobuf publisher.
+// Message type should be same as message name given while creating publisher.
+type EZMQXProtoPublisher struct {
+	EZMQXTypedPublisher[proto.Message]
+}
+
+// Get EZMQX protobuf publisher instance.
+// Message name should be fully-qualified name of message added using AddProtoModel API.
+// Topic is registered to TNS with message name as data model.
+func GetProtoPublisher(topic string, messageName string, optionalPort int) (*EZMQXProtoPublisher, EZMQXErrorCode) {
+	instance, err := GetProtoPublisherWithContext(context.Background(), topic, messageName, optionalPort, nil)
+	return instance, GetErrorCode(err)
+}
+
+// Get EZMQX protobuf publisher instance with context.
+// TNS register request is canceled when ctx is done, deadline of ctx is applied to it.
+// On failure, returned error is *EZMQXError which holds error code along with failed operation and cause.
+// Flow control of config [SetFlowControl] is used if optionalFlowControl is nil.
+func GetProtoPublisherWithContext(ctx context.Context, topic string, messageName string, optionalPort int, optionalFlowControl *EZMQXFlowControl) (*EZMQXProtoPublisher, error) {
+	return GetConfigInstance().GetProtoPublisherWithContext(ctx, topic, messageName, optionalPort, optionalFlowControl)
+}
+
+// Get EZMQX protobuf publisher instance with context.
+// TNS register request is canceled when ctx is done, deadline of ctx is applied to it.
+// On failure, returned error is *EZMQXError which holds error code along with failed operation and cause.
+// Flow control of config [SetFlowControl] is used if optionalFlowControl is nil.
+func (configInstance *EZMQXConfig) GetProtoPublisherWithContext(ctx context.Context, topic string, messageName string, optionalPort int, optionalFlowControl *EZMQXFlowControl) (*EZMQXProtoPublisher, error) {
+	if !configInstance.context.isCtxInitialized() {
+		return nil, toError(EZMQX_NOT_INITIALIZED, "GetProtoPublisher")
+	}
+	codec, result := configInstance.GetProtoCodec(messageName)
+	if result != EZMQX_OK {
+		return nil, newError(result, "getProtoDesc", "unknown message "+messageName)
+	}
+	publisher, err := createTypedPublisher(ctx, configInstance.context, topic, codec, optionalPort, optionalFlowControl)
+	if err != nil {
+		return nil, err
+	}
+	var instance *EZMQXProtoPublisher
+	instance = &EZMQXProtoPublisher{}
+	instance.EZMQXTypedPublisher = *publisher
+	return instance, nil
+}
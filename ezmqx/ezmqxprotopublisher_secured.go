@@ -0,0 +1,33 @@
+// +build !unsecure
+
+package ezmqx
+
+// Get Secured EZMQX protobuf publisher instance.
+//
+// Note:
+// (1) Key should be 40-character string encoded in the Z85 encoding format
+func GetSecuredProtoPublisher(topic string, serverPrivateKey string, messageName string, optionalPort int) (*EZMQXProtoPublisher, EZMQXErrorCode) {
+	return GetConfigInstance().GetSecuredProtoPublisher(topic, serverPrivateKey, messageName, optionalPort)
+}
+
+// Get Secured EZMQX protobuf publisher instance.
+//
+// Note:
+// (1) Key should be 40-character string encoded in the Z85 encoding format
+func (configInstance *EZMQXConfig) GetSecuredProtoPublisher(topic string, serverPrivateKey string, messageName string, optionalPort int) (*EZMQXProtoPublisher, EZMQXErrorCode) {
+	if !configInstance.context.isCtxInitialized() {
+		return nil, EZMQX_NOT_INITIALIZED
+	}
+	codec, result := configInstance.GetProtoCodec(messageName)
+	if result != EZMQX_OK {
+		return nil, result
+	}
+	publisher, result := createSecuredTypedPublisher(configInstance.context, topic, serverPrivateKey, codec, optionalPort)
+	if result != EZMQX_OK {
+		return nil, result
+	}
+	var instance *EZMQXProtoPublisher
+	instance = &EZMQXProtoPublisher{}
+	instance.EZMQXTypedPublisher = *publisher
+	return instance, EZMQX_OK
+}
@@ -0,0 +1,115 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmqx
+
+import (
+	"container/list"
+	"context"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+// Callback to get all the subscribed events for a specific topic.
+// Message is a dynamic message of the type advertised as topic data model.
+type EZMQXProtoSubCB func(topic string, message proto.Message)
+
+// Callback to get error for the subscribed topic.
+type EZMQXProtoErrorCB func(topic string, errorCode EZMQXErrorCode)
+
+// Structure represents EZMQX protobuf subscriber.
+type EZMQXProtoSubscriber struct {
+	EZMQXTypedSubscriber[proto.Message]
+}
+
+// Get protobuf subscriber instance for given topic.
+// It will work, if EZMQX is configured in docker mode.
+func GetProtoSubscriber(topic string, isHierarchical bool, subCallback EZMQXProtoSubCB, errorCallback EZMQXProtoErrorCB) (*EZMQXProtoSubscriber, EZMQXErrorCode) {
+	instance, err := GetProtoSubscriberWithContext(context.Background(), topic, isHierarchical, subCallback, errorCallback, nil)
+	return instance, GetErrorCode(err)
+}
+
+// Get protobuf subscriber instance for given topic with context.
+// TNS query request is canceled when ctx is done, deadline of ctx is applied to it.
+// On failure, returned error is *EZMQXError which holds error code along with failed operation and cause.
+// Flow control of config [SetFlowControl] is used if optionalFlowControl is nil.
+func GetProtoSubscriberWithContext(ctx context.Context, topic string, isHierarchical bool, subCallback EZMQXProtoSubCB, errorCallback EZMQXProtoErrorCB, optionalFlowControl *EZMQXFlowControl) (*EZMQXProtoSubscriber, error) {
+	return GetConfigInstance().GetProtoSubscriberWithContext(ctx, topic, isHierarchical, subCallback, errorCallback, optionalFlowControl)
+}
+
+// Get protobuf subscriber instance for given topic with context.
+// TNS query request is canceled when ctx is done, deadline of ctx is applied to it.
+// On failure, returned error is *EZMQXError which holds error code along with failed operation and cause.
+// Flow control of config [SetFlowControl] is used if optionalFlowControl is nil.
+func (configInstance *EZMQXConfig) GetProtoSubscriberWithContext(ctx context.Context, topic string, isHierarchical bool, subCallback EZMQXProtoSubCB, errorCallback EZMQXProtoErrorCB, optionalFlowControl *EZMQXFlowControl) (*EZMQXProtoSubscriber, error) {
+	instance := createProtoSubscriber(configInstance.context, optionalFlowControl, subCallback, errorCallback)
+	err := instance.subscriber.initialize(ctx, topic, isHierarchical)
+	if err != nil {
+		Logger.Error("initialization failed", zap.Error(err))
+		return nil, err
+	}
+	instance.isSecured = false
+	return instance, nil
+}
+
+// Get protobuf subscriber instance for given topic.
+// It will work, if EZMQX is configured in standalone mode.
+func GetProtoStandAloneSubscriber(topic EZMQXTopic, subCallback EZMQXProtoSubCB, errorCallback EZMQXProtoErrorCB) (*EZMQXProtoSubscriber, EZMQXErrorCode) {
+	return GetConfigInstance().GetProtoStandAloneSubscriber(topic, subCallback, errorCallback)
+}
+
+// Get protobuf subscriber instance for given topic.
+// It will work, if EZMQX is configured in standalone mode.
+func (configInstance *EZMQXConfig) GetProtoStandAloneSubscriber(topic EZMQXTopic, subCallback EZMQXProtoSubCB, errorCallback EZMQXProtoErrorCB) (*EZMQXProtoSubscriber, EZMQXErrorCode) {
+	instance := createProtoSubscriber(configInstance.context, nil, subCallback, errorCallback)
+	ezmqxTopicList := list.New()
+	ezmqxTopicList.PushBack(topic)
+	result := instance.subscriber.storeTopics(*ezmqxTopicList)
+	if result != EZMQX_OK {
+		Logger.Error("Store topic failed", zap.Int("Error code:", int(result)))
+		return nil, result
+	}
+	instance.isSecured = false
+	return instance, result
+}
+
+// Get protobuf subscriber instance for given topic list.
+// It will work, if EZMQX is configured in standalone mode.
+func GetProtoStandAloneSubscriber1(topics list.List, subCallback EZMQXProtoSubCB, errorCallback EZMQXProtoErrorCB) (*EZMQXProtoSubscriber, EZMQXErrorCode) {
+	return GetConfigInstance().GetProtoStandAloneSubscriber1(topics, subCallback, errorCallback)
+}
+
+// Get protobuf subscriber instance for given topic list.
+// It will work, if EZMQX is configured in standalone mode.
+func (configInstance *EZMQXConfig) GetProtoStandAloneSubscriber1(topics list.List, subCallback EZMQXProtoSubCB, errorCallback EZMQXProtoErrorCB) (*EZMQXProtoSubscriber, EZMQXErrorCode) {
+	instance := createProtoSubscriber(configInstance.context, nil, subCallback, errorCallback)
+	result := instance.subscriber.storeTopics(topics)
+	if result != EZMQX_OK {
+		Logger.Error("Store topic failed", zap.Int("Error code:", int(result)))
+		return nil, result
+	}
+	instance.isSecured = false
+	return instance, result
+}
+
+func createProtoSubscriber(context *EZMQXContext, flowControl *EZMQXFlowControl, subCallback EZMQXProtoSubCB, errorCallback EZMQXProtoErrorCB) *EZMQXProtoSubscriber {
+	var instance *EZMQXProtoSubscriber
+	instance = &EZMQXProtoSubscriber{}
+	instance.EZMQXTypedSubscriber = *createTypedSubscriber(context, flowControl, getProtoCodecResolver(context),
+		EZMQXTypedSubCB[proto.Message](subCallback), EZMQXSubErrorCB(errorCallback))
+	return instance
+}
@@ -0,0 +1,64 @@
+// +build !unsecure
+
+package ezmqx
+
+import (
+	"go.uber.org/zap"
+)
+
+// Get secured protobuf subscriber instance for given topic.
+//
+// Note:
+// (1) Key should be 40-character string encoded in the Z85 encoding format
+func GetSecuredProtoSubscriber(topic EZMQXTopic, serverPublicKey string, clientPublicKey string, clientSecretKey string, subCallback EZMQXProtoSubCB, errorCallback EZMQXProtoErrorCB) (*EZMQXProtoSubscriber, EZMQXErrorCode) {
+	return GetConfigInstance().GetSecuredProtoSubscriber(topic, serverPublicKey, clientPublicKey, clientSecretKey, subCallback, errorCallback)
+}
+
+// Get secured protobuf subscriber instance for given topic.
+//
+// Note:
+// (1) Key should be 40-character string encoded in the Z85 encoding format
+func (configInstance *EZMQXConfig) GetSecuredProtoSubscriber(topic EZMQXTopic, serverPublicKey string, clientPublicKey string, clientSecretKey string, subCallback EZMQXProtoSubCB, errorCallback EZMQXProtoErrorCB) (*EZMQXProtoSubscriber, EZMQXErrorCode) {
+	if !topic.IsSecured() {
+		return nil, EZMQX_INVALID_PARAM
+	}
+	instance := createProtoSubscriber(configInstance.context, nil, subCallback, errorCallback)
+	result := instance.subscriber.storeSecuredTopics(topic, serverPublicKey, clientPublicKey, clientSecretKey)
+	if result != EZMQX_OK {
+		Logger.Error("Store topic failed", zap.Int("Error code:", int(result)))
+		return nil, result
+	}
+	instance.isSecured = true
+	return instance, result
+}
+
+// Get secured protobuf subscriber instance for given topic.
+//
+// Note:
+// (1) Key should be 40-character string encoded in the Z85 encoding format
+func GetSecuredProtoSubscriber1(topicKeyMap map[EZMQXTopic]string, clientPublicKey string, clientSecretKey string, subCallback EZMQXProtoSubCB, errorCallback EZMQXProtoErrorCB) (*EZMQXProtoSubscriber, EZMQXErrorCode) {
+	return GetConfigInstance().GetSecuredProtoSubscriber1(topicKeyMap, clientPublicKey, clientSecretKey, subCallback, errorCallback)
+}
+
+// Get secured protobuf subscriber instance for given topic.
+//
+// Note:
+// (1) Key should be 40-character string encoded in the Z85 encoding format
+func (configInstance *EZMQXConfig) GetSecuredProtoSubscriber1(topicKeyMap map[EZMQXTopic]string, clientPublicKey string, clientSecretKey string, subCallback EZMQXProtoSubCB, errorCallback EZMQXProtoErrorCB) (*EZMQXProtoSubscriber, EZMQXErrorCode) {
+	for topic, _ := range topicKeyMap {
+		if !topic.IsSecured() {
+			return nil, EZMQX_INVALID_PARAM
+		}
+	}
+	instance := createProtoSubscriber(configInstance.context, nil, subCallback, errorCallback)
+	var result EZMQXErrorCode = EZMQX_INVALID_PARAM
+	for topic, serverKey := range topicKeyMap {
+		result = instance.subscriber.storeSecuredTopics(topic, serverKey, clientPublicKey, clientSecretKey)
+		if result != EZMQX_OK {
+			Logger.Error("Store topic failed", zap.Int("Error code:", int(result)))
+			return nil, result
+		}
+	}
+	instance.isSecured = true
+	return instance, result
+}
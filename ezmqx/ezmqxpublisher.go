@@ -18,10 +18,12 @@
 package ezmqx
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"go.uber.org/zap"
 	"go/ezmq"
+	"sync"
 	"sync/atomic"
 )
 
@@ -32,12 +34,27 @@ type EZMQXPublisher struct {
 	topicHandler  *EZMQXTopicHandler
 	localPort     int
 	status        uint32
+	sequencer     *envelopeSequencer
+	cache         *lastValueCache
+	cachePort     int
+	compression   EZMQXCompression
+	flowControl   *EZMQXFlowControl
+	sendQueue     *messageQueue
+	asyncQueue    *messageQueue
+	asyncMutex    *sync.Mutex
 }
 
-func getPublisher() *EZMQXPublisher {
+// Flow control of context is used if flowControl is nil.
+func getPublisher(context *EZMQXContext, flowControl *EZMQXFlowControl) *EZMQXPublisher {
 	var instance *EZMQXPublisher
 	instance = &EZMQXPublisher{}
-	instance.context = getContextInstance()
+	instance.context = context
+	instance.compression = instance.context.getCompression()
+	instance.flowControl = flowControl
+	if nil == instance.flowControl {
+		instance.flowControl = instance.context.getFlowControl()
+	}
+	instance.asyncMutex = &sync.Mutex{}
 	instance.status = CREATED
 	return instance
 }
@@ -46,6 +63,10 @@ func (instance *EZMQXPublisher) initialize(optionalPort int) EZMQXErrorCode {
 	if !instance.context.isCtxInitialized() {
 		return EZMQX_NOT_INITIALIZED
 	}
+	if nil != instance.flowControl && !instance.flowControl.isValid() {
+		Logger.Error("Invalid flow control")
+		return EZMQX_INVALID_PARAM
+	}
 	if instance.context.isCtxStandAlone() {
 		instance.localPort = optionalPort
 	} else {
@@ -66,39 +87,27 @@ func (instance *EZMQXPublisher) initialize(optionalPort int) EZMQXErrorCode {
 		Logger.Error("Could not start ezmq publisher")
 		return EZMQX_UNKNOWN_STATE
 	}
+	// Start last value cache
+	if instance.context.getLastValueDepth() > 0 {
+		result := instance.startLastValueCache(instance.context.getLastValueDepth())
+		if result != EZMQX_OK {
+			instance.ezmqPublisher.Stop()
+			return result
+		}
+	}
 	// Init topic handler
 	if instance.context.isCtxTnsEnabled() {
-		instance.topicHandler = getTopicHandler()
+		instance.topicHandler = instance.context.getTopicHandler()
 		instance.topicHandler.initHandler()
 		Logger.Debug("Initialized topic handler")
 	}
+	instance.startSendQueue()
 	atomic.StoreUint32(&instance.status, INITIALIZED)
 	return EZMQX_OK
 }
 
-func (instance *EZMQXPublisher) parseTopicResponse(response RestResponse) EZMQXErrorCode {
-	statusCode := response.GetStatusCode()
-	Logger.Debug("parseTopicResponse ", zap.Int(" Status code: ", statusCode))
-	if statusCode != HTTP_CREATED {
-		Logger.Error("parseTopicResponse, status code is not HTTP_CREATED")
-		return EZMQX_REST_ERROR
-	}
-	data := response.GetResponse()
-	result := make(map[string]int)
-	err := json.Unmarshal([]byte(data), &result)
-	if err != nil {
-		Logger.Error("Unmarshal error")
-		return EZMQX_REST_ERROR
-	}
-	interval, exists := result[PAYLOAD_KEEPALIVE_INTERVAL]
-	if !exists {
-		Logger.Error("No keep alive interval key in json response")
-		return EZMQX_REST_ERROR
-	}
-	if interval < 1 {
-		Logger.Error("Invalid keepAlive interval")
-		return EZMQX_REST_ERROR
-	}
+func (instance *EZMQXPublisher) parseTopicResponse(response *EZMQXTNSRegisterResponse) error {
+	interval := response.KeepAliveInterval
 	Logger.Debug("Keep alive interval", zap.Int("Interval: ", interval))
 	topicHandler := instance.topicHandler
 	// fmt.println is used as logger is not supporting for atomic values
@@ -110,75 +119,97 @@ func (instance *EZMQXPublisher) parseTopicResponse(response RestResponse) EZMQXE
 		result := topicHandler.send(KEEPALIVE, "")
 		if result != EZMQX_OK {
 			Logger.Error("Topic handler send failed")
-			return result
+			return toError(result, "parseTopicResponse")
 		}
 	}
-	return EZMQX_OK
+	return nil
 }
 
-func (instance *EZMQXPublisher) registerTopic(topic *EZMQXTopic) EZMQXErrorCode {
-	isValid := validateTopic(topic.GetName())
-	if false == isValid {
-		Logger.Error("Topic validation failed")
-		return EZMQX_INVALID_TOPIC
+func (instance *EZMQXPublisher) registerTopic(ctx context.Context, topic *EZMQXTopic) error {
+	err := validateTopicName("registerTopic", topic.GetName())
+	if err != nil {
+		Logger.Error("Topic validation failed", zap.Error(err))
+		return err
 	}
 	instance.topic = topic
+	return instance.registerTnsTopic(ctx, topic)
+}
+
+// Registers topic with given data model on host end point of this publisher.
+func (instance *EZMQXPublisher) registerDataModelTopic(ctx context.Context, topic string, dataModel string, isSecured bool) error {
+	if 0 == len(dataModel) {
+		Logger.Error("Data model is empty")
+		return newError(EZMQX_INVALID_PARAM, "registerTopic", "data model is empty")
+	}
+	hostEP, errorCode := instance.context.getHostEp(instance.localPort)
+	if errorCode != EZMQX_OK {
+		Logger.Error("Get hostEP failed")
+		return toError(EZMQX_UNKNOWN_STATE, "getHostEp")
+	}
+	ezmqxTopic := GetEZMQXTopic(topic, dataModel, isSecured, hostEP)
+	return instance.registerTopic(ctx, ezmqxTopic)
+}
+
+func (instance *EZMQXPublisher) registerTnsTopic(ctx context.Context, topic *EZMQXTopic) error {
 	context := instance.context
+	topic.compression = instance.compression
+	if nil != instance.cache {
+		var result EZMQXErrorCode
+		topic.snapshotEndPoint, result = context.getHostEp(instance.cachePort)
+		if result != EZMQX_OK {
+			Logger.Error("Get snapshot hostEP failed")
+			return toError(EZMQX_UNKNOWN_STATE, "registerTnsTopic")
+		}
+	}
 	if !context.isCtxTnsEnabled() {
-		return EZMQX_OK
+		return nil
 	}
 	// Send post request to TNS server
-	jsonData := map[string]interface{}{PAYLOAD_NAME: topic.GetName(), PAYLOAD_DATAMODEL: topic.GetDataModel(), PAYLOAD_ENDPOINT: topic.GetEndPoint().ToString(), PAYLOAD_SECURED: topic.IsSecured()}
-	payload := make(map[string]interface{})
-	payload[PAYLOAD_TOPIC] = jsonData
+	payload := EZMQXTNSRegisterRequest{Topic: GetTNSTopic(topic)}
 	fmt.Println("TNS register topic payload: \n\n", payload)
 	jsonValue, err := json.Marshal(payload)
 	if err != nil {
 		Logger.Error("TNS register topic: Json marshal failed")
-		return EZMQX_REST_ERROR
+		return GetEZMQXError(EZMQX_REST_ERROR, "registerTnsTopic", err)
 	}
-	client := GetRestFactory()
-	topicURL := context.ctxGetTnsAddr() + PREFIX + TOPIC
-	Logger.Debug("[TNS register topic] ", zap.String("Rest URL: ", string(topicURL)))
-	response, error := client.Post(topicURL, jsonValue)
-	if error != EZMQX_OK {
+	response, err := getContextTNSClient(context).register(ctx, jsonValue)
+	if err != nil {
 		Logger.Error("TNS register topic: Post request failed")
-		return EZMQX_REST_ERROR
+		return err
 	}
-	result := instance.parseTopicResponse(*response)
-	if result != EZMQX_OK {
+	err = instance.parseTopicResponse(response)
+	if err != nil {
 		Logger.Error("TNS register topic: Parse response failed")
-		return result
+		return err
 	}
+	//store registration, so that topic can be re-registered if TNS forgets it
+	topicHandler := context.getTopicHandler()
+	topicHandler.storeRegistration(topic.GetName(), jsonValue)
 	//send a request to topic handler to add topic to topic list
-	result = getTopicHandler().send(REGISTER, topic.GetName())
+	result := topicHandler.send(REGISTER, topic.GetName())
 	if result != EZMQX_OK {
 		Logger.Error("Topic handler send failed")
-		return result
+		return toError(result, "registerTnsTopic")
 	}
 	Logger.Debug("Sent request to topic handler to add topic to list: ", zap.String("Topic: ", topic.GetName()))
-	return EZMQX_OK
+	return nil
 }
 
 func (instance *EZMQXPublisher) unRegisterTopic(topic *EZMQXTopic) EZMQXErrorCode {
-	context := instance.context
-	if !context.isCtxTnsEnabled() {
+	ezmqxContext := instance.context
+	if !ezmqxContext.isCtxTnsEnabled() {
 		return EZMQX_OK
 	}
-	topicURL := context.ctxGetTnsAddr() + PREFIX + TOPIC
-	query := QUERY_NAME + topic.GetName()
-	Logger.Debug("[TNS unregister topic]", zap.String("Rest URL: ", string(topicURL)))
-	Logger.Debug("[TNS unregister topic]", zap.String("Query: ", string(query)))
-
-	client := GetRestFactory()
-	response, _ := client.Delete(topicURL+QUESTION_MARK+query, nil)
-	Logger.Debug("[TNS unregister topic]", zap.Int("Status: ", response.GetStatusCode()))
-	if response.GetStatusCode() != HTTP_OK {
+	//stop re-registration of topic before deleting it from TNS
+	ezmqxContext.getTopicHandler().dropRegistration(topic.GetName())
+	err := getContextTNSClient(ezmqxContext).Unregister(context.Background(), topic.GetName())
+	if err != nil {
+		Logger.Error("[TNS unregister topic] failed", zap.Error(err))
 		return EZMQX_REST_ERROR
 	}
 
 	//send request to topic handler to remove from topic list
-	result := getTopicHandler().send(UNREGISTER, topic.GetName())
+	result := ezmqxContext.getTopicHandler().send(UNREGISTER, topic.GetName())
 	if result != EZMQX_OK {
 		Logger.Error("Topic handler send failed")
 		return result
@@ -192,6 +223,7 @@ func (instance *EZMQXPublisher) terminate() EZMQXErrorCode {
 		Logger.Error("terminate failed : Not initialized")
 		return EZMQX_UNKNOWN_STATE
 	}
+	instance.stopAsyncQueue()
 	context := instance.context
 	if !context.isCtxStandAlone() {
 		result := instance.context.releaseDynamicPort(instance.localPort)
@@ -201,7 +233,7 @@ func (instance *EZMQXPublisher) terminate() EZMQXErrorCode {
 			Logger.Debug("Released local port")
 		}
 	}
-	if context.isCtxTnsEnabled() {
+	if context.isCtxTnsEnabled() && nil != instance.topic {
 		result := instance.unRegisterTopic(instance.topic)
 		if result != EZMQX_OK {
 			Logger.Error("Unregister topic: failed")
@@ -209,6 +241,8 @@ func (instance *EZMQXPublisher) terminate() EZMQXErrorCode {
 			Logger.Debug("Unregistered topic on TNS")
 		}
 	}
+	instance.stopSendQueue()
+	instance.stopLastValueCache()
 	if nil != instance.ezmqPublisher {
 		result := instance.ezmqPublisher.Stop()
 		if result != EZMQX_OK {
@@ -232,3 +266,159 @@ func (instance *EZMQXPublisher) isTerminated() bool {
 func (instance *EZMQXPublisher) getTopic() *EZMQXTopic {
 	return instance.topic
 }
+
+func (instance *EZMQXPublisher) publish(byteData []byte) EZMQXErrorCode {
+	return instance.publishOnTopic(instance.topic.GetName(), byteData)
+}
+
+func (instance *EZMQXPublisher) publishOnTopic(topic string, byteData []byte) EZMQXErrorCode {
+	byteData, result := compressPayload(instance.compression, byteData)
+	if result != EZMQX_OK {
+		Logger.Error("Compress payload failed")
+		return result
+	}
+	if nil != instance.sequencer {
+		byteData = instance.sequencer.wrap(topic, byteData)
+	}
+	if nil != instance.sendQueue {
+		result = instance.sendQueue.push(queuedMessage{topic: topic, data: byteData})
+	} else {
+		result = instance.send(topic, byteData)
+	}
+	if result == EZMQX_OK && nil != instance.cache {
+		instance.cache.store(topic, byteData)
+	}
+	return result
+}
+
+func (instance *EZMQXPublisher) send(topic string, byteData []byte) EZMQXErrorCode {
+	ezmqPublisher := instance.ezmqPublisher
+	if nil == ezmqPublisher {
+		Logger.Error("Ezmq Publisher failed")
+		return EZMQX_UNKNOWN_STATE
+	}
+	ezmqByteData := ezmq.EZMQByteData{ByteData: byteData}
+	result := ezmqPublisher.PublishOnTopic(topic, ezmqByteData)
+	if result != ezmq.EZMQ_OK {
+		Logger.Error("Publish failed")
+		return EZMQX_UNKNOWN_STATE
+	}
+	return EZMQX_OK
+}
+
+func (instance *EZMQXPublisher) startSendQueue() {
+	flowControl := instance.flowControl
+	if nil == flowControl || flowControl.GetSendQueueSize() < 1 {
+		return
+	}
+	instance.sendQueue = getMessageQueue(flowControl.GetSendQueueSize(), flowControl.GetPolicy(), flowControl.GetLinger(),
+		func(message queuedMessage) EZMQXErrorCode {
+			return instance.send(message.topic, message.data)
+		})
+}
+
+func (instance *EZMQXPublisher) stopSendQueue() {
+	if nil != instance.sendQueue {
+		instance.sendQueue.close()
+	}
+}
+
+// Returns asynchronous publish queue, which is created with given handler on first call.
+// If send queue size is not set, ASYNC_QUEUE_SIZE, OVERFLOW_DROP and ASYNC_LINGER are used.
+func (instance *EZMQXPublisher) getAsyncQueue(handler messageHandler) *messageQueue {
+	instance.asyncMutex.Lock()
+	defer instance.asyncMutex.Unlock()
+	if nil == instance.asyncQueue {
+		size, policy, linger := ASYNC_QUEUE_SIZE, OVERFLOW_DROP, ASYNC_LINGER
+		flowControl := instance.flowControl
+		if nil != flowControl && flowControl.GetSendQueueSize() > 0 {
+			size, policy, linger = flowControl.GetSendQueueSize(), flowControl.GetPolicy(), flowControl.GetLinger()
+		}
+		instance.asyncQueue = getMessageQueue(size, policy, linger, handler)
+	}
+	return instance.asyncQueue
+}
+
+// Pending asynchronous publish requests are published till linger is expired.
+func (instance *EZMQXPublisher) stopAsyncQueue() {
+	instance.asyncMutex.Lock()
+	asyncQueue := instance.asyncQueue
+	instance.asyncMutex.Unlock()
+	if nil != asyncQueue {
+		asyncQueue.close()
+	}
+}
+
+func (instance *EZMQXPublisher) getDroppedCount() uint64 {
+	var dropped uint64
+	if nil != instance.sendQueue {
+		dropped = instance.sendQueue.getDroppedCount()
+	}
+	instance.asyncMutex.Lock()
+	if nil != instance.asyncQueue {
+		dropped += instance.asyncQueue.getDroppedCount()
+	}
+	instance.asyncMutex.Unlock()
+	return dropped
+}
+
+// Release resources of initialized publisher whose topic is not registered.
+func (instance *EZMQXPublisher) release() {
+	instance.stopSendQueue()
+	instance.stopLastValueCache()
+	instance.ezmqPublisher.Stop()
+}
+
+func (instance *EZMQXPublisher) enableEnvelope(publisherId string) EZMQXErrorCode {
+	if len(publisherId) > ENVELOPE_MAX_ID_LENGTH {
+		Logger.Error("Publisher ID is too long")
+		return EZMQX_INVALID_PARAM
+	}
+	if 0 == len(publisherId) {
+		publisherId = getUniquePublisherId()
+	}
+	instance.sequencer = getEnvelopeSequencer(publisherId)
+	return EZMQX_OK
+}
+
+func (instance *EZMQXPublisher) startLastValueCache(depth int) EZMQXErrorCode {
+	context := instance.context
+	port := 0
+	if !context.isCtxStandAlone() {
+		var result EZMQXErrorCode
+		port, result = context.assignDynamicPort()
+		if result != EZMQX_OK {
+			return result
+		}
+	}
+	cache, result := getLastValueCache(depth, port)
+	if result != EZMQX_OK {
+		if !context.isCtxStandAlone() {
+			context.releaseDynamicPort(port)
+		}
+		return result
+	}
+	instance.cache = cache
+	instance.cachePort = cache.getPort()
+	return EZMQX_OK
+}
+
+func (instance *EZMQXPublisher) stopLastValueCache() {
+	if nil == instance.cache {
+		return
+	}
+	instance.cache.stop()
+	instance.cache = nil
+	if !instance.context.isCtxStandAlone() {
+		result := instance.context.releaseDynamicPort(instance.cachePort)
+		if result != EZMQX_OK {
+			Logger.Error("Release snapshot port: failed")
+		}
+	}
+}
+
+func (instance *EZMQXPublisher) removeCachedTopic(topic string) {
+	if nil != instance.cache {
+		instance.cache.remove(topic)
+	}
+}
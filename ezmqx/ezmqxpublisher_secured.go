@@ -38,10 +38,11 @@ func (instance *EZMQXPublisher) initializeSecured(optionalPort int, serverPrivat
 	}
 	// Init topic handler
 	if instance.context.isCtxTnsEnabled() {
-		instance.topicHandler = getTopicHandler()
+		instance.topicHandler = instance.context.getTopicHandler()
 		instance.topicHandler.initHandler()
 		Logger.Debug("Initialized topic handler")
 	}
+	instance.startSendQueue()
 	atomic.StoreUint32(&instance.status, INITIALIZED)
 	return EZMQX_OK
 }
@@ -27,6 +27,7 @@ const TNS_KNOWN_PORT = "48323"
 const TOPIC = "/tns/topic"
 const TNS_KEEP_ALIVE = "/tns/keepalive"
 const HTTP_PREFIX = "http://"
+const HTTPS_PREFIX = "https://"
 const QUERY_NAME = "name="
 const QUERY_HIERARCHICAL = "&hierarchical="
 const QUERY_TRUE = "yes"
@@ -59,6 +60,8 @@ const PAYLOAD_NAME = "name"
 const PAYLOAD_ENDPOINT = "endpoint"
 const PAYLOAD_DATAMODEL = "datamodel"
 const PAYLOAD_SECURED = "secured"
+const PAYLOAD_SNAPSHOT = "snapshot"
+const PAYLOAD_COMPRESSION = "compression"
 const PAYLOAD_KEEPALIVE_INTERVAL = "ka_interval"
 const PAYLOAD_TOPIC_KA = "topic_names"
 const CONF_REVERSE_PROXY = "reverseproxy"
@@ -79,6 +82,9 @@ const HOST_NAME_FILE_PATH = "/etc/hostname"
 // HTTP status codes
 const HTTP_OK = 200
 const HTTP_CREATED = 201
+const HTTP_UNAUTHORIZED = 401
+const HTTP_NOT_FOUND = 404
+const HTTP_CONFLICT = 409
 const CONNECTION_TIMEOUT = 5
 
 // Strings
@@ -91,3 +97,5 @@ const UNREGISTER = "unregister"
 const KEEPALIVE = "keepalive"
 const SHUTDOWN = "shutdown"
 const APPLICATION_JSON = "application/json"
+const AUTHORIZATION = "Authorization"
+const BEARER_PREFIX = "Bearer "
@@ -0,0 +1,326 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmqx
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/zap"
+	"math/rand"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Requests which are not made for a known endpoint [e.g. RestFactory.Get], circuit breaker is not applied to them.
+const restEndpointAny EZMQXRestEndpoint = -1
+
+// HTTP status codes for which idempotent requests are retried.
+const HTTP_BAD_GATEWAY = 502
+const HTTP_SERVICE_UNAVAILABLE = 503
+const HTTP_GATEWAY_TIMEOUT = 504
+
+// Structure represents retry policy of REST requests.
+type EZMQXRetryPolicy struct {
+	maxRetries     int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+}
+
+// Get EZMQX retry policy instance.
+//
+// maxRetries: Maximum number of retries after first attempt.
+// initialBackoff: Backoff before first retry, it is doubled for every next retry.
+// maxBackoff: Maximum backoff. Random jitter of up to half of backoff is applied to every backoff.
+func GetEZMQXRetryPolicy(maxRetries int, initialBackoff time.Duration, maxBackoff time.Duration) *EZMQXRetryPolicy {
+	var instance *EZMQXRetryPolicy
+	instance = &EZMQXRetryPolicy{}
+	instance.maxRetries = maxRetries
+	instance.initialBackoff = initialBackoff
+	instance.maxBackoff = maxBackoff
+	return instance
+}
+
+// Get maximum number of retries.
+func (policy *EZMQXRetryPolicy) GetMaxRetries() int {
+	return policy.maxRetries
+}
+
+// Get initial backoff.
+func (policy *EZMQXRetryPolicy) GetInitialBackoff() time.Duration {
+	return policy.initialBackoff
+}
+
+// Get maximum backoff.
+func (policy *EZMQXRetryPolicy) GetMaxBackoff() time.Duration {
+	return policy.maxBackoff
+}
+
+func (policy *EZMQXRetryPolicy) isValid() bool {
+	return policy.maxRetries >= 0 && policy.initialBackoff > 0 && policy.maxBackoff >= policy.initialBackoff
+}
+
+// Returns backoff with jitter for given retry, retry starts from 0.
+func (policy *EZMQXRetryPolicy) getBackoff(retry int) time.Duration {
+	backoff := policy.initialBackoff
+	for i := 0; i < retry && backoff < policy.maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > policy.maxBackoff {
+		backoff = policy.maxBackoff
+	}
+	half := int64(backoff / 2)
+	return time.Duration(half + rand.Int63n(half+1))
+}
+
+// Structure represents circuit breaker of TNS requests.
+type EZMQXCircuitBreaker struct {
+	failureThreshold int
+	openDuration     time.Duration
+}
+
+// Get EZMQX circuit breaker instance.
+//
+// failureThreshold: Number of consecutive failed requests to a TNS host after which circuit is opened.
+// openDuration: While circuit is open, requests to the host fail fast with EZMQX_TNS_NOT_AVAILABLE.
+// After it, one request is allowed to check the host, circuit is closed if it succeeds.
+func GetEZMQXCircuitBreaker(failureThreshold int, openDuration time.Duration) *EZMQXCircuitBreaker {
+	var instance *EZMQXCircuitBreaker
+	instance = &EZMQXCircuitBreaker{}
+	instance.failureThreshold = failureThreshold
+	instance.openDuration = openDuration
+	return instance
+}
+
+// Get failure threshold.
+func (breaker *EZMQXCircuitBreaker) GetFailureThreshold() int {
+	return breaker.failureThreshold
+}
+
+// Get open duration.
+func (breaker *EZMQXCircuitBreaker) GetOpenDuration() time.Duration {
+	return breaker.openDuration
+}
+
+func (breaker *EZMQXCircuitBreaker) isValid() bool {
+	return breaker.failureThreshold > 0 && breaker.openDuration > 0
+}
+
+// Circuit state of a host.
+type circuitState struct {
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+// Retry policy, circuit breaker and circuit states of rest factory.
+type restResilience struct {
+	retryPolicy    *EZMQXRetryPolicy
+	circuitBreaker *EZMQXCircuitBreaker
+	circuits       map[string]*circuitState
+	mutex          *sync.Mutex
+}
+
+func getRestResilience() *restResilience {
+	var instance *restResilience
+	instance = &restResilience{}
+	instance.circuits = make(map[string]*circuitState)
+	instance.mutex = &sync.Mutex{}
+	return instance
+}
+
+// Set retry policy of REST requests, nil disables retry.
+//
+// GET, PUT and DELETE requests are retried on transport failure and on HTTP 502, 503 and 504.
+// POST requests [e.g. topic registration] are retried only if connection to server fails,
+// as server may have processed them otherwise.
+func (configInstance *EZMQXConfig) SetRetryPolicy(policy *EZMQXRetryPolicy) EZMQXErrorCode {
+	if result := configInstance.checkNotStarted("Set retry policy"); result != EZMQX_OK {
+		return result
+	}
+	if nil != policy && !policy.isValid() {
+		Logger.Error("Invalid retry policy")
+		return EZMQX_INVALID_PARAM
+	}
+	configInstance.context.getRestFactory().setRetryPolicy(policy)
+	return EZMQX_OK
+}
+
+// Set circuit breaker of TNS requests, nil disables it.
+// Circuit is kept for each TNS host, while it is open, TNS requests fail with EZMQX_TNS_NOT_AVAILABLE
+// [e.g. GetAMLPublisher, GetAMLSubscriber and topic discovery]. Setting it resets circuits.
+func (configInstance *EZMQXConfig) SetCircuitBreaker(breaker *EZMQXCircuitBreaker) EZMQXErrorCode {
+	if result := configInstance.checkNotStarted("Set circuit breaker"); result != EZMQX_OK {
+		return result
+	}
+	if nil != breaker && !breaker.isValid() {
+		Logger.Error("Invalid circuit breaker")
+		return EZMQX_INVALID_PARAM
+	}
+	configInstance.context.getRestFactory().setCircuitBreaker(breaker)
+	return EZMQX_OK
+}
+
+func (instance *RestFactory) setRetryPolicy(policy *EZMQXRetryPolicy) {
+	resilience := instance.resilience
+	resilience.mutex.Lock()
+	defer resilience.mutex.Unlock()
+	resilience.retryPolicy = policy
+}
+
+func (instance *RestFactory) setCircuitBreaker(breaker *EZMQXCircuitBreaker) {
+	resilience := instance.resilience
+	resilience.mutex.Lock()
+	defer resilience.mutex.Unlock()
+	resilience.circuitBreaker = breaker
+	resilience.circuits = make(map[string]*circuitState)
+}
+
+// Sends request with retry policy and, for TNS endpoint, circuit breaker of factory.
+func (instance *RestFactory) execute(ctx context.Context, endpoint EZMQXRestEndpoint, method string, requestURL string,
+	send func() (*RestResponse, error)) (*RestResponse, error) {
+	resilience := instance.resilience
+	resilience.mutex.Lock()
+	policy := resilience.retryPolicy
+	resilience.mutex.Unlock()
+
+	host := ""
+	if REST_ENDPOINT_TNS == endpoint {
+		host = getHost(requestURL)
+		err := resilience.allow(host, method+" "+requestURL)
+		if err != nil {
+			return nil, err
+		}
+	}
+	response, err := send()
+	for retry := 0; nil != policy && retry < policy.maxRetries && isRetryable(method, response, err); retry++ {
+		backoff := policy.getBackoff(retry)
+		Logger.Debug("Retry REST request", zap.String("URL: ", requestURL), zap.Duration("Backoff: ", backoff))
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			// host did not fail the canceled request, so previous failure is not counted
+			err = GetEZMQXError(EZMQX_CANCELED, method+" "+requestURL, ctx.Err())
+			resilience.record(host, nil, err)
+			return nil, err
+		case <-timer.C:
+		}
+		response, err = send()
+	}
+	resilience.record(host, response, err)
+	return response, err
+}
+
+// Returns error with EZMQX_TNS_NOT_AVAILABLE, if circuit of host is open.
+func (resilience *restResilience) allow(host string, op string) error {
+	resilience.mutex.Lock()
+	defer resilience.mutex.Unlock()
+	if nil == resilience.circuitBreaker {
+		return nil
+	}
+	circuit := resilience.circuits[host]
+	if nil == circuit || circuit.failures < resilience.circuitBreaker.failureThreshold {
+		return nil
+	}
+	if time.Now().Before(circuit.openUntil) || circuit.probing {
+		return newError(EZMQX_TNS_NOT_AVAILABLE, op, "circuit open for "+host)
+	}
+	// half open: allow one request to check the host
+	circuit.probing = true
+	return nil
+}
+
+// Records result of request to host, circuit is opened when failures reach threshold.
+// Canceled request only allows next check of the host.
+func (resilience *restResilience) record(host string, response *RestResponse, err error) {
+	if 0 == len(host) {
+		return
+	}
+	resilience.mutex.Lock()
+	defer resilience.mutex.Unlock()
+	if nil == resilience.circuitBreaker {
+		return
+	}
+	circuit := resilience.circuits[host]
+	if EZMQX_CANCELED == GetErrorCode(err) {
+		if nil != circuit {
+			circuit.probing = false
+		}
+		return
+	}
+	if !isHostFailure(response, err) {
+		delete(resilience.circuits, host)
+		return
+	}
+	if nil == circuit {
+		circuit = &circuitState{}
+		resilience.circuits[host] = circuit
+	}
+	circuit.failures++
+	circuit.probing = false
+	if circuit.failures >= resilience.circuitBreaker.failureThreshold {
+		Logger.Error("Circuit opened", zap.String("Host: ", host))
+		circuit.openUntil = time.Now().Add(resilience.circuitBreaker.openDuration)
+	}
+}
+
+// Checks whether request should be retried for given response and error.
+func isRetryable(method string, response *RestResponse, err error) bool {
+	if err != nil {
+		if GetErrorCode(err) != EZMQX_REST_ERROR {
+			return false
+		}
+		return isIdempotent(method) || isConnectionFailure(err)
+	}
+	if nil == response {
+		return false
+	}
+	switch response.GetStatusCode() {
+	case HTTP_BAD_GATEWAY, HTTP_SERVICE_UNAVAILABLE, HTTP_GATEWAY_TIMEOUT:
+		return isIdempotent(method)
+	}
+	return false
+}
+
+func isIdempotent(method string) bool {
+	return "GET" == method || "PUT" == method || "DELETE" == method
+}
+
+// Checks whether connection to server failed, so that request was not sent.
+func isConnectionFailure(err error) bool {
+	var opError *net.OpError
+	return errors.As(err, &opError) && "dial" == opError.Op
+}
+
+// Checks whether host failed to serve request.
+func isHostFailure(response *RestResponse, err error) bool {
+	if err != nil {
+		return GetErrorCode(err) == EZMQX_REST_ERROR
+	}
+	return nil == response || response.GetStatusCode() >= 500
+}
+
+func getHost(requestURL string) string {
+	parsedURL, err := url.Parse(requestURL)
+	if err != nil {
+		return requestURL
+	}
+	return parsedURL.Host
+}
@@ -19,16 +19,22 @@ package ezmqx
 
 import (
 	"container/list"
-	"encoding/json"
+	"context"
 	"fmt"
 	"go.uber.org/zap"
 	"go/aml"
 	"go/ezmq"
+	"strconv"
+	"sync"
 	"sync/atomic"
 )
 
 type EZMQXSubCB func(topic string, ezmqMsg ezmq.EZMQMessage)
 
+type EZMQXSubErrorCB func(topic string, errorCode EZMQXErrorCode)
+
+type EZMQXTopicCB func(topic EZMQXTopic) EZMQXErrorCode
+
 type EZMQXSubscriber struct {
 	ezmqSubscriber *ezmq.EZMQSubscriber
 	context        *EZMQXContext
@@ -36,119 +42,138 @@ type EZMQXSubscriber struct {
 	amlRepDic      map[string]*aml.Representation
 	status         uint32
 	internalCB     EZMQXSubCB
+	internalErrCB  EZMQXSubErrorCB
+	topicCB        EZMQXTopicCB
+	topicRemovedCB func(topic string)
+	tracker        *envelopeTracker
+	deliveryMutex  *sync.Mutex
+	compressions   map[string]EZMQXCompression
+	flowControl    *EZMQXFlowControl
+	receiveQueue   *messageQueue
+	// envelope of the message being delivered, valid only during internal callbacks
+	envelope *EZMQXEnvelope
+	// TNS query of subscriber and topic refresh routine
+	queryTopic     string
+	isHierarchical bool
+	topicsMutex    *sync.Mutex
+	refreshChan    chan bool
+	refreshDone    chan bool
 }
 
-func getEZMQXSubscriber() *EZMQXSubscriber {
+// Flow control of context is used if flowControl is nil.
+func getEZMQXSubscriber(context *EZMQXContext, flowControl *EZMQXFlowControl) *EZMQXSubscriber {
 	var instance *EZMQXSubscriber
 	instance = &EZMQXSubscriber{}
-	instance.context = getContextInstance()
+	instance.context = context
+	instance.flowControl = flowControl
+	if nil == instance.flowControl {
+		instance.flowControl = context.getFlowControl()
+	}
 	instance.storedTopics = list.New()
 	instance.amlRepDic = make(map[string]*aml.Representation)
 	instance.ezmqSubscriber = nil
+	instance.tracker = getEnvelopeTracker()
+	instance.deliveryMutex = &sync.Mutex{}
+	instance.compressions = make(map[string]EZMQXCompression)
+	instance.topicsMutex = &sync.Mutex{}
 	instance.status = CREATED
 	return instance
 }
 
-func (instance *EZMQXSubscriber) initialize(topic string, isHierarchical bool) EZMQXErrorCode {
+func (instance *EZMQXSubscriber) initialize(ctx context.Context, topic string, isHierarchical bool) error {
 	context := instance.context
 	if false == context.isCtxInitialized() {
 		Logger.Error("Context is not initialized")
-		return EZMQX_NOT_INITIALIZED
+		return toError(EZMQX_NOT_INITIALIZED, "initialize")
 	}
-	result := validateTopic(topic)
+	if nil != instance.flowControl && !instance.flowControl.isValid() {
+		Logger.Error("Invalid flow control")
+		return newError(EZMQX_INVALID_PARAM, "initialize", "invalid flow control")
+	}
+	result := validateTopicOrPattern(topic)
 	if false == result {
 		Logger.Error("Topic validation failed")
-		return EZMQX_INVALID_TOPIC
+		return newError(EZMQX_INVALID_TOPIC, "initialize", "invalid topic "+topic)
 	}
 	if !context.isCtxTnsEnabled() {
 		Logger.Error("TNS is not enabled")
-		return EZMQX_TNS_NOT_AVAILABLE
+		return toError(EZMQX_TNS_NOT_AVAILABLE, "initialize")
 	}
-	verified, errorCode := instance.verifyTopics(topic, isHierarchical)
-	if errorCode != EZMQX_OK {
+	verified, err := instance.resolveTopics(ctx, topic, isHierarchical)
+	if err != nil {
 		Logger.Error("Verify topics failed")
-		return errorCode
+		return err
 	}
-	return instance.storeTopics(*verified)
+	instance.queryTopic = topic
+	instance.isHierarchical = isHierarchical
+	return toError(instance.storeTopics(*verified), "storeTopics")
 }
 
-func (instance *EZMQXSubscriber) parseTNSResponse(data []byte) (*list.List, EZMQXErrorCode) {
-	ezmqxTopicList := list.New()
-	topics := make(map[string][]interface{})
-	err := json.Unmarshal([]byte(data), &topics)
+// Queries TNS for given topic. For topic pattern, TNS is queried hierarchically
+// with levels before first wildcard and the result is filtered by pattern.
+func (instance *EZMQXSubscriber) resolveTopics(ctx context.Context, topic string, isHierarchical bool) (*list.List, error) {
+	if !isTopicPattern(topic) {
+		return instance.verifyTopics(ctx, topic, isHierarchical)
+	}
+	verified, err := instance.verifyTopics(ctx, getTopicPatternPrefix(topic), true)
 	if err != nil {
-		return nil, EZMQX_REST_ERROR
-	}
-	topicList, exists := topics[PAYLOAD_TOPICS]
-	if !exists {
-		Logger.Error("No topics key exists in json response")
-		return nil, EZMQX_REST_ERROR
-	}
-	for _, item := range topicList {
-		stringMap := item.(map[string]interface{})
-		dataModel, exists := stringMap[PAYLOAD_DATAMODEL].(string)
-		if !exists {
-			Logger.Error("No data model key exists in json response")
-			return nil, EZMQX_REST_ERROR
-		}
-		endPoint, exists := stringMap[PAYLOAD_ENDPOINT].(string)
-		if !exists {
-			Logger.Error("No end point key exists in json response")
-			return nil, EZMQX_REST_ERROR
-		}
-		name, exists := stringMap[PAYLOAD_NAME].(string)
-		if !exists {
-			Logger.Error("No name exists in json response")
-			return nil, EZMQX_REST_ERROR
-		}
-		isSecured, exists := stringMap[PAYLOAD_SECURED].(bool)
-		if !exists {
-			Logger.Error("No secured key exists in json response")
-			return nil, EZMQX_REST_ERROR
-		}
-		ezmqXEndPoint := GetEZMQXEndPoint(endPoint)
-		ezmqxTopic := GetEZMQXTopic(name, dataModel, isSecured, ezmqXEndPoint)
-		topicValue := *ezmqxTopic
-		ezmqxTopicList.PushBack(topicValue)
-	}
-	return ezmqxTopicList, EZMQX_OK
-}
-
-func (instance *EZMQXSubscriber) verifyTopics(topic string, isHierarchical bool) (*list.List, EZMQXErrorCode) {
-	tnsURL := instance.context.ctxGetTnsAddr() + PREFIX + TOPIC
-	Logger.Debug("[TNS get topic]", zap.String("Rest URL:", tnsURL))
-	var hierarchical string
-	if true == isHierarchical {
-		hierarchical = QUERY_TRUE
-	} else {
-		hierarchical = QUERY_FALSE
+		return nil, err
+	}
+	matched := list.New()
+	for element := verified.Front(); element != nil; element = element.Next() {
+		ezmqxTopic := element.Value.(EZMQXTopic)
+		if matchTopic(topic, ezmqxTopic.GetName()) {
+			matched.PushBack(ezmqxTopic)
+		}
 	}
-	query := QUERY_NAME + topic + QUERY_HIERARCHICAL + hierarchical
-	Logger.Debug("[TNS get topic]", zap.String("query:", query))
+	if 0 == matched.Len() {
+		Logger.Error("No topic matched", zap.String("Pattern: ", topic))
+		return nil, newError(EZMQX_NO_TOPIC_MATCHED, "resolveTopics", "no topic matched "+topic)
+	}
+	return matched, nil
+}
 
-	client := GetRestFactory()
-	response, err := client.Get(tnsURL + QUESTION_MARK + query)
-	if err != EZMQX_OK {
+func (instance *EZMQXSubscriber) verifyTopics(ctx context.Context, topic string, isHierarchical bool) (*list.List, error) {
+	tnsTopics, statusCode, err := getContextTNSClient(instance.context).query(ctx, topic, isHierarchical)
+	if err != nil {
 		Logger.Debug("[TNS get topic] request failed")
-		return nil, EZMQX_REST_ERROR
+		return nil, err
 	}
-	if response.GetStatusCode() != HTTP_OK {
+	if statusCode != HTTP_OK {
 		Logger.Debug("[TNS get topic] Response code is not HTTP_OK")
-		return nil, EZMQX_REST_ERROR
+		return nil, newError(EZMQX_REST_ERROR, "verifyTopics", "unexpected status code "+strconv.Itoa(statusCode))
 	}
-	data := response.GetResponse()
-	Logger.Debug("[TNS get topic]", zap.String("response:", string(data)))
-	return instance.parseTNSResponse(data)
+	ezmqxTopicList := list.New()
+	for _, tnsTopic := range tnsTopics {
+		ezmqxTopic, err := tnsTopic.ToEZMQXTopic()
+		if err != nil {
+			return nil, err
+		}
+		ezmqxTopicList.PushBack(*ezmqxTopic)
+	}
+	return ezmqxTopicList, nil
 }
 
 func (instance *EZMQXSubscriber) createSubscriber(endPoint *EZMQXEndpoint) EZMQXErrorCode {
+	flowControl := instance.flowControl
+	if nil == instance.receiveQueue && nil != flowControl && flowControl.GetReceiveQueueSize() > 0 {
+		instance.receiveQueue = getMessageQueue(flowControl.GetReceiveQueueSize(), flowControl.GetPolicy(), flowControl.GetLinger(),
+			func(message queuedMessage) EZMQXErrorCode {
+				instance.deliverLocked(message.topic, message.data)
+				return EZMQX_OK
+			})
+	}
 	instance.ezmqSubscriber = ezmq.GetEZMQSubscriber(endPoint.GetAddr(), endPoint.GetPort(), func(ezmqMsg ezmq.EZMQMessage) {},
 		func(topic string, ezmqMsg ezmq.EZMQMessage) {
 			contentType := ezmqMsg.GetContentType()
 			fmt.Printf("\nTopic: %s", topic)
 			if contentType == ezmq.EZMQ_CONTENT_TYPE_BYTEDATA {
 				byteData := ezmqMsg.(ezmq.EZMQByteData)
-				instance.internalCB(topic, byteData)
+				if nil != instance.receiveQueue {
+					instance.receiveQueue.push(queuedMessage{topic: topic, data: byteData.ByteData})
+				} else {
+					instance.deliverLocked(topic, byteData.ByteData)
+				}
 			} else {
 				Logger.Debug("[Content type is not byte data")
 			}
@@ -160,7 +185,78 @@ func (instance *EZMQXSubscriber) createSubscriber(endPoint *EZMQXEndpoint) EZMQX
 	return EZMQX_OK
 }
 
+func (instance *EZMQXSubscriber) deliverLocked(topic string, byteData []byte) {
+	instance.deliveryMutex.Lock()
+	defer instance.deliveryMutex.Unlock()
+	instance.deliver(topic, byteData)
+}
+
+func (instance *EZMQXSubscriber) deliver(topic string, byteData []byte) {
+	envelope, data := decodeEnvelope(byteData)
+	instance.envelope = envelope
+	defer func() { instance.envelope = nil }()
+	if nil != envelope {
+		result := instance.tracker.track(topic, envelope)
+		if result != EZMQX_OK && nil != instance.internalErrCB {
+			instance.internalErrCB(topic, result)
+		}
+		if result == EZMQX_DUPLICATED_MESSAGE {
+			return
+		}
+	}
+	data, result := decompressPayload(instance.compressions[topic], data)
+	if result != EZMQX_OK {
+		Logger.Error("Decompress payload failed", zap.String("Topic: ", topic))
+		if nil != instance.internalErrCB {
+			instance.internalErrCB(topic, result)
+		}
+		return
+	}
+	instance.internalCB(topic, ezmq.EZMQByteData{ByteData: data})
+}
+
+func (instance *EZMQXSubscriber) storeCompression(topic EZMQXTopic) {
+	instance.deliveryMutex.Lock()
+	defer instance.deliveryMutex.Unlock()
+	instance.compressions[topic.GetName()] = topic.GetCompression()
+}
+
+// Snapshot is fetched without delivery mutex, so live messages of other topics are not blocked.
+// Live messages received during fetch are delivered first, snapshot messages which are already
+// delivered [sequence of envelope is not newer] are skipped.
+func (instance *EZMQXSubscriber) deliverSnapshot(topic EZMQXTopic) {
+	snapshotEndPoint := topic.GetSnapshotEndPoint()
+	if nil == snapshotEndPoint {
+		return
+	}
+	messages, result := fetchSnapshot(snapshotEndPoint, topic.GetName())
+	if result != EZMQX_OK {
+		Logger.Error("Fetch snapshot failed", zap.String("Topic: ", topic.GetName()))
+		return
+	}
+	Logger.Debug("Fetched snapshot", zap.String("Topic: ", topic.GetName()), zap.Int("Messages: ", len(messages)))
+	instance.deliveryMutex.Lock()
+	defer instance.deliveryMutex.Unlock()
+	for _, data := range messages {
+		envelope, _ := decodeEnvelope(data)
+		if nil != envelope && instance.tracker.isDelivered(topic.GetName(), envelope) {
+			continue
+		}
+		instance.deliver(topic.GetName(), data)
+	}
+}
+
 func (instance *EZMQXSubscriber) subscribe(topic EZMQXTopic) EZMQXErrorCode {
+	result := instance.connect(topic)
+	if result != EZMQX_OK {
+		return result
+	}
+	instance.deliverSnapshot(topic)
+	return EZMQX_OK
+}
+
+// Creates ezmq subscriber for first topic, later topics are subscribed with their end point.
+func (instance *EZMQXSubscriber) connect(topic EZMQXTopic) EZMQXErrorCode {
 	endPoint := topic.GetEndPoint()
 	if nil == instance.ezmqSubscriber {
 		result := instance.createSubscriber(endPoint)
@@ -208,11 +304,11 @@ func (instance *EZMQXSubscriber) storeTopics(topics list.List) EZMQXErrorCode {
 			Logger.Error("Invalid topic")
 			return EZMQX_INVALID_TOPIC
 		}
-		instance.amlRepDic[ezmqxTopic.GetName()], result = context.getAmlRep(ezmqxTopic.GetDataModel())
+		result = instance.storeDataModel(ezmqxTopic)
 		if result != EZMQX_OK {
-			Logger.Error("getAmlRep failed", zap.Int("Error code:", int(result)))
 			return result
 		}
+		instance.storeCompression(ezmqxTopic)
 		result = instance.subscribe(ezmqxTopic)
 		if result != EZMQX_OK {
 			Logger.Error("subscribe failed", zap.Int("Error code:", int(result)))
@@ -224,11 +320,39 @@ func (instance *EZMQXSubscriber) storeTopics(topics list.List) EZMQXErrorCode {
 	return EZMQX_OK
 }
 
+func (instance *EZMQXSubscriber) storeDataModel(topic EZMQXTopic) EZMQXErrorCode {
+	var result EZMQXErrorCode
+	if nil != instance.topicCB {
+		result = instance.topicCB(topic)
+		if result != EZMQX_OK {
+			Logger.Error("Store data model failed", zap.Int("Error code:", int(result)))
+		}
+		return result
+	}
+	instance.amlRepDic[topic.GetName()], result = instance.context.getAmlRep(topic.GetDataModel())
+	if result != EZMQX_OK {
+		Logger.Error("getAmlRep failed", zap.Int("Error code:", int(result)))
+	}
+	return result
+}
+
+// Removes data model, compression and codec of topic which is not subscribed anymore.
+func (instance *EZMQXSubscriber) forgetTopic(topic string) {
+	instance.deliveryMutex.Lock()
+	defer instance.deliveryMutex.Unlock()
+	delete(instance.amlRepDic, topic)
+	delete(instance.compressions, topic)
+	if nil != instance.topicRemovedCB {
+		instance.topicRemovedCB(topic)
+	}
+}
+
 func (instance *EZMQXSubscriber) terminate() EZMQXErrorCode {
 	if false == atomic.CompareAndSwapUint32(&instance.status, INITIALIZED, TERMINATING) {
 		Logger.Error("terminate failed : Not initialized")
 		return EZMQX_UNKNOWN_STATE
 	}
+	instance.stopRefresh()
 	ezmqSubscriber := instance.ezmqSubscriber
 	if ezmqSubscriber != nil {
 		result := ezmqSubscriber.Stop()
@@ -238,6 +362,9 @@ func (instance *EZMQXSubscriber) terminate() EZMQXErrorCode {
 			return EZMQX_UNKNOWN_STATE
 		}
 	}
+	if nil != instance.receiveQueue {
+		instance.receiveQueue.close()
+	}
 	atomic.StoreUint32(&instance.status, CREATED)
 	return EZMQX_OK
 }
@@ -249,6 +376,18 @@ func (instance *EZMQXSubscriber) isTerminated() bool {
 	return false
 }
 
+// Returns copy of subscribed topics, as topic refresh routine can update them.
 func (instance *EZMQXSubscriber) getTopics() *list.List {
-	return instance.storedTopics
+	instance.topicsMutex.Lock()
+	defer instance.topicsMutex.Unlock()
+	topics := list.New()
+	topics.PushBackList(instance.storedTopics)
+	return topics
+}
+
+func (instance *EZMQXSubscriber) getDroppedCount() uint64 {
+	if nil == instance.receiveQueue {
+		return 0
+	}
+	return instance.receiveQueue.getDroppedCount()
 }
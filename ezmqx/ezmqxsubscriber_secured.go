@@ -20,11 +20,11 @@ func (instance *EZMQXSubscriber) storeSecuredTopics(ezmqxTopic EZMQXTopic, serve
 		Logger.Error("Invalid topic")
 		return EZMQX_INVALID_TOPIC
 	}
-	instance.amlRepDic[ezmqxTopic.GetName()], result = context.getAmlRep(ezmqxTopic.GetDataModel())
+	result = instance.storeDataModel(ezmqxTopic)
 	if result != EZMQX_OK {
-		Logger.Error("getAmlRep failed", zap.Int("Error code:", int(result)))
 		return result
 	}
+	instance.storeCompression(ezmqxTopic)
 	result = instance.subscribeSecured(ezmqxTopic, serverPublicKey, clientPublicKey, clientSecretKey)
 	if result != EZMQX_OK {
 		Logger.Error("subscribe failed", zap.Int("Error code:", int(result)))
@@ -0,0 +1,268 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmqx
+
+import (
+	"container/list"
+	"context"
+	"go.uber.org/zap"
+	"go/ezmq"
+	"sync/atomic"
+	"time"
+)
+
+type EZMQXTopicChange int
+
+// Constants represents change of topic found on topic refresh or topic discovery watch.
+// TOPIC_CHANGED represents change of other than end point [e.g. data model].
+const (
+	TOPIC_ADDED            = 0
+	TOPIC_REMOVED          = 1
+	TOPIC_ENDPOINT_CHANGED = 2
+	TOPIC_CHANGED          = 3
+)
+
+// Callback to get changes of subscribed topics found on topic refresh.
+type EZMQXTopicChangeCB func(topic EZMQXTopic, change EZMQXTopicChange)
+
+type topicChange struct {
+	topic  EZMQXTopic
+	change EZMQXTopicChange
+}
+
+// Starts routine which queries TNS periodically for the topic of this subscriber.
+// It works only for subscribers created using TNS query [docker mode or TNS enabled].
+func (instance *EZMQXSubscriber) startRefresh(interval time.Duration, callback EZMQXTopicChangeCB) EZMQXErrorCode {
+	if interval <= 0 {
+		Logger.Error("Invalid refresh interval")
+		return EZMQX_INVALID_PARAM
+	}
+	if atomic.LoadUint32(&instance.status) != INITIALIZED {
+		Logger.Error("Subscriber is not initialized")
+		return EZMQX_UNKNOWN_STATE
+	}
+	if 0 == len(instance.queryTopic) {
+		Logger.Error("Subscriber is not created using TNS")
+		return EZMQX_TNS_NOT_AVAILABLE
+	}
+	instance.topicsMutex.Lock()
+	defer instance.topicsMutex.Unlock()
+	if nil != instance.refreshChan {
+		Logger.Error("Topic refresh is already started")
+		return EZMQX_INITIALIZED
+	}
+	instance.refreshChan = make(chan bool)
+	instance.refreshDone = make(chan bool)
+	go instance.refresh(interval, callback, instance.refreshChan, instance.refreshDone)
+	return EZMQX_OK
+}
+
+// Stops topic refresh routine and waits till it is stopped.
+func (instance *EZMQXSubscriber) stopRefresh() {
+	instance.topicsMutex.Lock()
+	refreshChan := instance.refreshChan
+	refreshDone := instance.refreshDone
+	instance.refreshChan = nil
+	instance.refreshDone = nil
+	instance.topicsMutex.Unlock()
+	if nil == refreshChan {
+		return
+	}
+	close(refreshChan)
+	<-refreshDone
+}
+
+func (instance *EZMQXSubscriber) refresh(interval time.Duration, callback EZMQXTopicChangeCB, refreshChan chan bool, refreshDone chan bool) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	defer close(refreshDone)
+	for {
+		select {
+		case <-refreshChan:
+			Logger.Debug("Topic refresh stopped")
+			return
+		case <-ticker.C:
+			instance.refreshTopics(interval, callback)
+		}
+	}
+}
+
+// Queries TNS and applies added, removed and moved topics to ezmq subscriber.
+func (instance *EZMQXSubscriber) refreshTopics(timeout time.Duration, callback EZMQXTopicChangeCB) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	verified, err := instance.resolveTopics(ctx, instance.queryTopic, instance.isHierarchical)
+	cancel()
+	if GetErrorCode(err) == EZMQX_NO_TOPIC_MATCHED {
+		verified, err = list.New(), nil
+	}
+	if err != nil {
+		Logger.Error("Topic refresh: verify topics failed", zap.Error(err))
+		return
+	}
+	latest := make(map[string]EZMQXTopic)
+	for element := verified.Front(); element != nil; element = element.Next() {
+		topic := element.Value.(EZMQXTopic)
+		if topic.IsSecured() {
+			Logger.Debug("Topic refresh: secured topic is ignored", zap.String("Topic: ", topic.GetName()))
+			continue
+		}
+		latest[topic.GetName()] = topic
+	}
+
+	// Changes are collected under lock, socket work and snapshot fetch are done without it
+	var changes []topicChange
+	var kept []EZMQXTopic
+	var dropped []EZMQXTopic
+	var updated []topicChange
+	instance.topicsMutex.Lock()
+	for element := instance.storedTopics.Front(); element != nil; element = element.Next() {
+		topic := element.Value.(EZMQXTopic)
+		latestTopic, exists := latest[topic.GetName()]
+		delete(latest, topic.GetName())
+		if !exists {
+			dropped = append(dropped, topic)
+			changes = append(changes, topicChange{topic, TOPIC_REMOVED})
+		} else if isTopicChanged(&topic, &latestTopic) {
+			dropped = append(dropped, topic)
+			updated = append(updated, topicChange{latestTopic, getTopicChange(topic, latestTopic)})
+		} else {
+			kept = append(kept, topic)
+		}
+	}
+	instance.topicsMutex.Unlock()
+	for _, topic := range latest {
+		updated = append(updated, topicChange{topic, TOPIC_ADDED})
+	}
+
+	for _, change := range changes {
+		instance.removeTopic(change.topic)
+		Logger.Debug("Topic refresh: topic removed", zap.String("Topic: ", change.topic.GetName()))
+	}
+	isReconnected := instance.isDisconnectNeeded(dropped, kept, updated)
+	if isReconnected {
+		var failed []EZMQXTopic
+		kept, failed = instance.reconnect(kept)
+		for _, topic := range failed {
+			instance.removeTopic(topic)
+			changes = append(changes, topicChange{topic, TOPIC_REMOVED})
+		}
+	}
+	for _, change := range updated {
+		// end point of topic is still connected, if only other than end point is changed
+		isConnected := change.change == TOPIC_CHANGED && !isReconnected
+		if EZMQX_OK == instance.applyTopic(change.topic, isConnected) {
+			kept = append(kept, change.topic)
+			Logger.Debug("Topic refresh: topic applied", zap.String("Topic: ", change.topic.GetName()))
+			changes = append(changes, change)
+			continue
+		}
+		instance.removeTopic(change.topic)
+		if change.change != TOPIC_ADDED {
+			// topic is added again on a later refresh
+			changes = append(changes, topicChange{change.topic, TOPIC_REMOVED})
+		}
+	}
+
+	instance.topicsMutex.Lock()
+	instance.storedTopics.Init()
+	for _, topic := range kept {
+		instance.storedTopics.PushBack(topic)
+	}
+	instance.topicsMutex.Unlock()
+	// Callback is called without lock, so that topics can be read from callback
+	if nil != callback {
+		for _, change := range changes {
+			callback(change.topic, change.change)
+		}
+	}
+}
+
+// Returns true if end point of a dropped topic is not used by any remaining topic.
+func (instance *EZMQXSubscriber) isDisconnectNeeded(dropped []EZMQXTopic, kept []EZMQXTopic, updated []topicChange) bool {
+	endPoints := make(map[string]bool)
+	for _, topic := range kept {
+		endPoints[topic.GetEndPoint().ToString()] = true
+	}
+	for _, change := range updated {
+		endPoints[change.topic.GetEndPoint().ToString()] = true
+	}
+	for _, topic := range dropped {
+		if !endPoints[topic.GetEndPoint().ToString()] {
+			return true
+		}
+	}
+	return false
+}
+
+// Recreates ezmq subscriber for given topics, as ezmq subscriber can not disconnect a single end point.
+// Returns topics subscribed again and topics failed to subscribe.
+func (instance *EZMQXSubscriber) reconnect(topics []EZMQXTopic) ([]EZMQXTopic, []EZMQXTopic) {
+	ezmqSubscriber := instance.ezmqSubscriber
+	instance.ezmqSubscriber = nil
+	if nil != ezmqSubscriber && ezmq.EZMQ_OK != ezmqSubscriber.Stop() {
+		Logger.Error("Topic refresh: stop ezmq subscriber failed")
+	}
+	var connected []EZMQXTopic
+	var failed []EZMQXTopic
+	for _, topic := range topics {
+		if EZMQX_OK != instance.connect(topic) {
+			Logger.Error("Topic refresh: subscribe failed", zap.String("Topic: ", topic.GetName()))
+			failed = append(failed, topic)
+			continue
+		}
+		connected = append(connected, topic)
+	}
+	return connected, failed
+}
+
+// Unsubscribes topic and removes its data model, compression and codec.
+func (instance *EZMQXSubscriber) removeTopic(topic EZMQXTopic) {
+	if nil != instance.ezmqSubscriber && ezmq.EZMQ_OK != instance.ezmqSubscriber.UnSubscribeForTopic(topic.GetName()) {
+		Logger.Error("Topic refresh: unsubscribe failed", zap.String("Topic: ", topic.GetName()))
+	}
+	instance.forgetTopic(topic.GetName())
+}
+
+// Stores data model and compression of topic and subscribes to its end point, if it is not connected yet.
+func (instance *EZMQXSubscriber) applyTopic(topic EZMQXTopic, isConnected bool) EZMQXErrorCode {
+	// Data model is stored with delivery mutex, as it is read on delivery of messages
+	instance.deliveryMutex.Lock()
+	result := instance.storeDataModel(topic)
+	instance.deliveryMutex.Unlock()
+	if result != EZMQX_OK {
+		Logger.Error("Topic refresh: store data model failed", zap.String("Topic: ", topic.GetName()))
+		return result
+	}
+	instance.storeCompression(topic)
+	if isConnected {
+		return EZMQX_OK
+	}
+	result = instance.subscribe(topic)
+	if result != EZMQX_OK {
+		Logger.Error("Topic refresh: subscribe failed", zap.String("Topic: ", topic.GetName()))
+	}
+	return result
+}
+
+// Returns TOPIC_ENDPOINT_CHANGED if end point is changed, otherwise TOPIC_CHANGED.
+func getTopicChange(previous EZMQXTopic, latest EZMQXTopic) EZMQXTopicChange {
+	if previous.GetEndPoint().ToString() != latest.GetEndPoint().ToString() {
+		return TOPIC_ENDPOINT_CHANGED
+	}
+	return TOPIC_CHANGED
+}
@@ -0,0 +1,137 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmqx
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"go.uber.org/zap"
+	"io/ioutil"
+)
+
+// REST endpoints which can be secured with TLS.
+type EZMQXRestEndpoint int
+
+// Constants represents REST endpoints.
+const (
+	REST_ENDPOINT_TNS    EZMQXRestEndpoint = 0
+	REST_ENDPOINT_ANCHOR EZMQXRestEndpoint = 1
+	REST_ENDPOINT_NODE   EZMQXRestEndpoint = 2
+)
+
+// Structure represents TLS configuration of REST endpoint.
+type EZMQXTLSConfig struct {
+	caFile     string
+	certFile   string
+	keyFile    string
+	serverName string
+}
+
+// Get EZMQX TLS config instance.
+//
+// caFile: PEM file of CA certificates to verify server, system CA pool is used if it is empty.
+// certFile, keyFile: PEM files of client certificate and key for mutual TLS, both empty for server only TLS.
+// serverName: Server name to verify server certificate, host name of URL is used if it is empty.
+func GetEZMQXTLSConfig(caFile string, certFile string, keyFile string, serverName string) *EZMQXTLSConfig {
+	var instance *EZMQXTLSConfig
+	instance = &EZMQXTLSConfig{}
+	instance.caFile = caFile
+	instance.certFile = certFile
+	instance.keyFile = keyFile
+	instance.serverName = serverName
+	return instance
+}
+
+// Get CA certificates file.
+func (tlsConfig *EZMQXTLSConfig) GetCAFile() string {
+	return tlsConfig.caFile
+}
+
+// Get client certificate file.
+func (tlsConfig *EZMQXTLSConfig) GetCertFile() string {
+	return tlsConfig.certFile
+}
+
+// Get client key file.
+func (tlsConfig *EZMQXTLSConfig) GetKeyFile() string {
+	return tlsConfig.keyFile
+}
+
+// Get server name override.
+func (tlsConfig *EZMQXTLSConfig) GetServerName() string {
+	return tlsConfig.serverName
+}
+
+// Loads certificates and returns TLS configuration for http transport.
+func (tlsConfig *EZMQXTLSConfig) load() (*tls.Config, error) {
+	config := &tls.Config{ServerName: tlsConfig.serverName, MinVersion: tls.VersionTLS12}
+	if 0 != len(tlsConfig.caFile) {
+		data, err := ioutil.ReadFile(tlsConfig.caFile)
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(data) {
+			return nil, errors.New("no certificate found in " + tlsConfig.caFile)
+		}
+	}
+	if 0 != len(tlsConfig.certFile) || 0 != len(tlsConfig.keyFile) {
+		certificate, err := tls.LoadX509KeyPair(tlsConfig.certFile, tlsConfig.keyFile)
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{certificate}
+	}
+	return config, nil
+}
+
+func isValidRestEndpoint(endpoint EZMQXRestEndpoint) bool {
+	return endpoint == REST_ENDPOINT_TNS || endpoint == REST_ENDPOINT_ANCHOR || endpoint == REST_ENDPOINT_NODE
+}
+
+// Set TLS configuration for REST requests to given endpoint, nil tlsConfig disables TLS for it.
+// With TLS, requests to the endpoint use https, for example TNS address found in docker mode
+// and TNS address given in stand alone mode are changed from http:// to https://.
+// EZMQX_INVALID_PARAM is returned if certificates can not be loaded or
+// REST client factory [SetRestClientFactory] does not implement RestClientTLSFactoryInterface.
+func (configInstance *EZMQXConfig) SetTLSConfig(endpoint EZMQXRestEndpoint, tlsConfig *EZMQXTLSConfig) EZMQXErrorCode {
+	if result := configInstance.checkNotStarted("Set TLS config"); result != EZMQX_OK {
+		return result
+	}
+	if !isValidRestEndpoint(endpoint) {
+		Logger.Error("Invalid REST endpoint")
+		return EZMQX_INVALID_PARAM
+	}
+	restFactory := configInstance.context.getRestFactory()
+	if nil == tlsConfig {
+		restFactory.setTLSConfig(endpoint, nil)
+		return EZMQX_OK
+	}
+	if !supportsTLS(restFactory.restInterface) {
+		Logger.Error("Set TLS config failed: REST client factory does not support TLS")
+		return EZMQX_INVALID_PARAM
+	}
+	config, err := tlsConfig.load()
+	if err != nil {
+		Logger.Error("Load TLS config failed", zap.Error(err))
+		return EZMQX_INVALID_PARAM
+	}
+	restFactory.setTLSConfig(endpoint, config)
+	return EZMQX_OK
+}
@@ -0,0 +1,309 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmqx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go.uber.org/zap"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Structure represents topic as registered on TNS [Topic name server] server.
+type EZMQXTNSTopic struct {
+	Name        string `json:"name"`
+	EndPoint    string `json:"endpoint"`
+	DataModel   string `json:"datamodel"`
+	Secured     bool   `json:"secured"`
+	Snapshot    string `json:"snapshot,omitempty"`
+	Compression string `json:"compression,omitempty"`
+}
+
+// Structure represents register topic request of TNS.
+type EZMQXTNSRegisterRequest struct {
+	Topic EZMQXTNSTopic `json:"topic"`
+}
+
+// Structure represents register topic response of TNS.
+type EZMQXTNSRegisterResponse struct {
+	KeepAliveInterval int `json:"ka_interval"`
+}
+
+// Structure represents query topic response of TNS.
+type EZMQXTNSQueryResponse struct {
+	Topics []EZMQXTNSTopic `json:"topics"`
+}
+
+// Structure represents keep alive request of TNS.
+type EZMQXTNSKeepAliveRequest struct {
+	TopicNames []string `json:"topic_names"`
+}
+
+// Structure represents client of TNS [Topic name server] server.
+// It can be used without initializing EZMQX config [e.g. by ops tooling to list and clean up stale topics].
+type EZMQXTNSClient struct {
+	tnsAddr     string
+	restFactory *RestFactory
+}
+
+// Get TNS client for given TNS address [e.g. "http://192.168.0.1:48323"].
+// Client uses global rest factory, use (*EZMQXConfig).GetTNSClient for REST settings of a config.
+func GetTNSClient(tnsAddr string) *EZMQXTNSClient {
+	return getTNSClient(tnsAddr, GetRestFactory())
+}
+
+// Get TNS client for given TNS address, which uses REST settings [e.g. TLS, credentials, retry policy] of config.
+func (configInstance *EZMQXConfig) GetTNSClient(tnsAddr string) *EZMQXTNSClient {
+	return getTNSClient(tnsAddr, configInstance.context.getRestFactory())
+}
+
+func getTNSClient(tnsAddr string, restFactory *RestFactory) *EZMQXTNSClient {
+	var instance *EZMQXTNSClient
+	instance = &EZMQXTNSClient{}
+	instance.tnsAddr = tnsAddr
+	instance.restFactory = restFactory
+	return instance
+}
+
+// Get TNS client of EZMQX context.
+func getContextTNSClient(context *EZMQXContext) *EZMQXTNSClient {
+	return getTNSClient(context.ctxGetTnsAddr(), context.getRestFactory())
+}
+
+// Get TNS address of client.
+func (instance *EZMQXTNSClient) GetTNSAddress() string {
+	return instance.tnsAddr
+}
+
+// Register topic on TNS.
+// Returned error has EZMQX_DUPLICATED_TOPIC, if TNS already has the topic.
+func (instance *EZMQXTNSClient) Register(ctx context.Context, request EZMQXTNSRegisterRequest) (*EZMQXTNSRegisterResponse, error) {
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return nil, GetEZMQXError(EZMQX_INVALID_PARAM, "Register", err)
+	}
+	return instance.register(ctx, payload)
+}
+
+// Unregister topic from TNS.
+// Returned error has EZMQX_UNKNOWN_TOPIC, if TNS does not have the topic.
+func (instance *EZMQXTNSClient) Unregister(ctx context.Context, topic string) error {
+	topicURL := instance.tnsAddr + PREFIX + TOPIC + QUESTION_MARK + QUERY_NAME + escapeQueryTopic(topic)
+	Logger.Debug("[TNS unregister topic]", zap.String("Rest URL: ", topicURL))
+	response, err := instance.restFactory.deleteWithError(ctx, REST_ENDPOINT_TNS, topicURL)
+	if err != nil {
+		return err
+	}
+	return checkStatusCode(response.GetStatusCode(), HTTP_OK, "Unregister")
+}
+
+// Query given topic on TNS.
+// Returned error has EZMQX_UNKNOWN_TOPIC, if TNS does not have the topic.
+func (instance *EZMQXTNSClient) Query(ctx context.Context, topic string) ([]EZMQXTNSTopic, error) {
+	return instance.queryErr(ctx, topic, false, "Query")
+}
+
+// Query topics under given topic hierarchy on TNS.
+// Returned error has EZMQX_UNKNOWN_TOPIC, if TNS does not have any topic.
+func (instance *EZMQXTNSClient) HierarchicalQuery(ctx context.Context, topic string) ([]EZMQXTNSTopic, error) {
+	return instance.queryErr(ctx, topic, true, "HierarchicalQuery")
+}
+
+// Keep given topics alive on TNS.
+// Returned error has EZMQX_UNKNOWN_TOPIC, if TNS does not have one or more topics.
+func (instance *EZMQXTNSClient) KeepAlive(ctx context.Context, request EZMQXTNSKeepAliveRequest) error {
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return GetEZMQXError(EZMQX_INVALID_PARAM, "KeepAlive", err)
+	}
+	keepAliveURL := instance.tnsAddr + PREFIX + TNS_KEEP_ALIVE
+	Logger.Debug("[TNS keep alive]", zap.String("Rest URL:", keepAliveURL))
+	response, err := instance.restFactory.postWithError(ctx, REST_ENDPOINT_TNS, keepAliveURL, payload)
+	if err != nil {
+		return err
+	}
+	return checkStatusCode(response.GetStatusCode(), HTTP_OK, "KeepAlive")
+}
+
+// Posts given register payload to TNS.
+func (instance *EZMQXTNSClient) register(ctx context.Context, payload []byte) (*EZMQXTNSRegisterResponse, error) {
+	topicURL := instance.tnsAddr + PREFIX + TOPIC
+	Logger.Debug("[TNS register topic] ", zap.String("Rest URL: ", topicURL))
+	response, err := instance.restFactory.postWithError(ctx, REST_ENDPOINT_TNS, topicURL, payload)
+	if err != nil {
+		return nil, err
+	}
+	err = checkStatusCode(response.GetStatusCode(), HTTP_CREATED, "Register")
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]int)
+	err = json.Unmarshal(response.GetResponse(), &result)
+	if err != nil {
+		return nil, GetEZMQXError(EZMQX_REST_ERROR, "Register", err)
+	}
+	interval, exists := result[PAYLOAD_KEEPALIVE_INTERVAL]
+	if !exists {
+		return nil, newError(EZMQX_REST_ERROR, "Register", PAYLOAD_KEEPALIVE_INTERVAL+" key missing")
+	}
+	if interval < 1 {
+		return nil, newError(EZMQX_REST_ERROR, "Register", "invalid keep alive interval "+strconv.Itoa(interval))
+	}
+	return &EZMQXTNSRegisterResponse{KeepAliveInterval: interval}, nil
+}
+
+func (instance *EZMQXTNSClient) queryErr(ctx context.Context, topic string, isHierarchical bool, op string) ([]EZMQXTNSTopic, error) {
+	topics, statusCode, err := instance.query(ctx, topic, isHierarchical)
+	if err != nil {
+		return nil, err
+	}
+	err = checkStatusCode(statusCode, HTTP_OK, op)
+	if err != nil {
+		return nil, err
+	}
+	return topics, nil
+}
+
+// Returns topics and status code of TNS response, topics are nil if status code is not HTTP_OK.
+func (instance *EZMQXTNSClient) query(ctx context.Context, topic string, isHierarchical bool) ([]EZMQXTNSTopic, int, error) {
+	tnsURL := instance.tnsAddr + PREFIX + TOPIC
+	var hierarchical string
+	if true == isHierarchical {
+		hierarchical = QUERY_TRUE
+	} else {
+		hierarchical = QUERY_FALSE
+	}
+	query := QUERY_NAME + escapeQueryTopic(topic) + QUERY_HIERARCHICAL + hierarchical
+	Logger.Debug("[TNS query topic]", zap.String("Rest URL:", tnsURL), zap.String("query:", query))
+
+	response, err := instance.restFactory.getWithError(ctx, REST_ENDPOINT_TNS, tnsURL+QUESTION_MARK+query)
+	if err != nil {
+		Logger.Error("[TNS query topic]: request failed")
+		return nil, 0, err
+	}
+	if response.GetStatusCode() != HTTP_OK {
+		return nil, response.GetStatusCode(), nil
+	}
+	Logger.Debug("[TNS query topic]: ", zap.String("response:", string(response.GetResponse())))
+	topics, err := parseQueryResponse(response.GetResponse())
+	return topics, HTTP_OK, err
+}
+
+// Escapes topic for query string, '/' is kept as it is valid in query and TNS expects it.
+func escapeQueryTopic(topic string) string {
+	return strings.Replace(url.QueryEscape(topic), "%2F", SLASH, -1)
+}
+
+func parseQueryResponse(data []byte) ([]EZMQXTNSTopic, error) {
+	var response struct {
+		Topics *[]EZMQXTNSTopic `json:"topics"`
+	}
+	err := json.Unmarshal(data, &response)
+	if err != nil {
+		Logger.Error("parseTNSResponse: Unmarshal failed")
+		return nil, GetEZMQXError(EZMQX_REST_ERROR, "parseTNSResponse", err)
+	}
+	if nil == response.Topics {
+		Logger.Error("No topics key exists in json response")
+		return nil, newError(EZMQX_REST_ERROR, "parseTNSResponse", PAYLOAD_TOPICS+" key missing")
+	}
+	return *response.Topics, nil
+}
+
+// Returns error for unexpected status code, nil if status code is expected one.
+func checkStatusCode(statusCode int, expected int, op string) error {
+	if statusCode == expected {
+		return nil
+	}
+	message := "unexpected status code " + strconv.Itoa(statusCode)
+	switch statusCode {
+	case HTTP_NOT_FOUND:
+		return newError(EZMQX_UNKNOWN_TOPIC, op, message)
+	case HTTP_CONFLICT:
+		return newError(EZMQX_DUPLICATED_TOPIC, op, message)
+	}
+	return newError(EZMQX_REST_ERROR, op, message)
+}
+
+// UnmarshalJSON decodes topic and fails, if name, endpoint, datamodel or secured key is missing.
+func (topic *EZMQXTNSTopic) UnmarshalJSON(data []byte) error {
+	var fields struct {
+		Name        *string `json:"name"`
+		EndPoint    *string `json:"endpoint"`
+		DataModel   *string `json:"datamodel"`
+		Secured     *bool   `json:"secured"`
+		Snapshot    string  `json:"snapshot"`
+		Compression string  `json:"compression"`
+	}
+	err := json.Unmarshal(data, &fields)
+	if err != nil {
+		return err
+	}
+	if nil == fields.DataModel {
+		return errors.New(PAYLOAD_DATAMODEL + " key missing")
+	}
+	if nil == fields.EndPoint {
+		return errors.New(PAYLOAD_ENDPOINT + " key missing")
+	}
+	if nil == fields.Name {
+		return errors.New(PAYLOAD_NAME + " key missing")
+	}
+	if nil == fields.Secured {
+		return errors.New(PAYLOAD_SECURED + " key missing")
+	}
+	topic.Name = *fields.Name
+	topic.EndPoint = *fields.EndPoint
+	topic.DataModel = *fields.DataModel
+	topic.Secured = *fields.Secured
+	topic.Snapshot = fields.Snapshot
+	topic.Compression = fields.Compression
+	return nil
+}
+
+// Get TNS topic of given EZMQX topic.
+func GetTNSTopic(topic *EZMQXTopic) EZMQXTNSTopic {
+	tnsTopic := EZMQXTNSTopic{Name: topic.GetName(), DataModel: topic.GetDataModel(), Secured: topic.IsSecured()}
+	if nil != topic.GetEndPoint() {
+		tnsTopic.EndPoint = topic.GetEndPoint().ToString()
+	}
+	if nil != topic.GetSnapshotEndPoint() {
+		tnsTopic.Snapshot = topic.GetSnapshotEndPoint().ToString()
+	}
+	if COMPRESSION_NONE != topic.GetCompression() {
+		tnsTopic.Compression = getCompressionName(topic.GetCompression())
+	}
+	return tnsTopic
+}
+
+// Get EZMQX topic of TNS topic.
+// Returned error has EZMQX_UNKNOWN_COMPRESSION, if compression of topic is not supported.
+func (topic EZMQXTNSTopic) ToEZMQXTopic() (*EZMQXTopic, error) {
+	ezmqxTopic := GetEZMQXTopic(topic.Name, topic.DataModel, topic.Secured, GetEZMQXEndPoint(topic.EndPoint))
+	if 0 != len(topic.Snapshot) {
+		ezmqxTopic.snapshotEndPoint = GetEZMQXEndPoint(topic.Snapshot)
+	}
+	var result EZMQXErrorCode
+	ezmqxTopic.compression, result = getCompression(topic.Compression)
+	if result != EZMQX_OK {
+		return nil, newError(result, "ToEZMQXTopic", "unknown compression "+topic.Compression)
+	}
+	return ezmqxTopic, nil
+}
@@ -19,10 +19,12 @@ package ezmqx
 
 // Structure represents EZMQX topic.
 type EZMQXTopic struct {
-	name      string
-	dataModel string
-	endPoint  *EZMQXEndpoint
-	isSecured bool
+	name             string
+	dataModel        string
+	endPoint         *EZMQXEndpoint
+	snapshotEndPoint *EZMQXEndpoint
+	isSecured        bool
+	compression      EZMQXCompression
 }
 
 // Get EZMQX topic instance.
@@ -36,6 +38,22 @@ func GetEZMQXTopic(name string, dataModel string, isSecured bool, endPoint *EZMQ
 	return instance
 }
 
+// Get EZMQX topic instance with snapshot end point of publisher's last value cache.
+// Subscriber fetches cached messages from snapshot end point before receiving live messages.
+func GetEZMQXTopic1(name string, dataModel string, isSecured bool, endPoint *EZMQXEndpoint, snapshotEndPoint *EZMQXEndpoint) *EZMQXTopic {
+	instance := GetEZMQXTopic(name, dataModel, isSecured, endPoint)
+	instance.snapshotEndPoint = snapshotEndPoint
+	return instance
+}
+
+// Get EZMQX topic instance with payload compression codec of publisher.
+// Subscriber decompresses payload using this codec.
+func GetEZMQXTopic2(name string, dataModel string, isSecured bool, endPoint *EZMQXEndpoint, snapshotEndPoint *EZMQXEndpoint, compression EZMQXCompression) *EZMQXTopic {
+	instance := GetEZMQXTopic1(name, dataModel, isSecured, endPoint, snapshotEndPoint)
+	instance.compression = compression
+	return instance
+}
+
 // Get topic name.
 func (topic *EZMQXTopic) GetName() string {
 	return topic.name
@@ -55,3 +73,14 @@ func (topic *EZMQXTopic) IsSecured() bool {
 func (topic *EZMQXTopic) GetEndPoint() *EZMQXEndpoint {
 	return topic.endPoint
 }
+
+// Get snapshot end point of last value cache.
+// Returns nil if publisher does not have last value cache.
+func (topic *EZMQXTopic) GetSnapshotEndPoint() *EZMQXEndpoint {
+	return topic.snapshotEndPoint
+}
+
+// Get payload compression codec of topic.
+func (topic *EZMQXTopic) GetCompression() EZMQXCompression {
+	return topic.compression
+}
@@ -19,9 +19,8 @@ package ezmqx
 
 import (
 	"container/list"
-
-	"encoding/json"
-	"go.uber.org/zap"
+	"context"
+	"strconv"
 )
 
 // Structure represents EZMQX topic discovery.
@@ -31,7 +30,12 @@ type EZMQXTopicDiscovery struct {
 
 // Get EZMQX topic discovery instance.
 func GetEZMQXTopicDiscovery() (*EZMQXTopicDiscovery, EZMQXErrorCode) {
-	context := getContextInstance()
+	return GetConfigInstance().GetEZMQXTopicDiscovery()
+}
+
+// Get EZMQX topic discovery instance.
+func (configInstance *EZMQXConfig) GetEZMQXTopicDiscovery() (*EZMQXTopicDiscovery, EZMQXErrorCode) {
+	context := configInstance.context
 	if !context.isCtxInitialized() {
 		return nil, EZMQX_NOT_INITIALIZED
 	}
@@ -43,11 +47,19 @@ func GetEZMQXTopicDiscovery() (*EZMQXTopicDiscovery, EZMQXErrorCode) {
 
 // Query the given topic to TNS [Topic name server] server.
 func (instance *EZMQXTopicDiscovery) Query(topic string) (*EZMQXTopic, EZMQXErrorCode) {
-	topics, result := instance.queryInternal(topic, false)
-	if result != EZMQX_OK {
-		return nil, result
+	ezmqxTopic, err := instance.QueryWithContext(context.Background(), topic)
+	return ezmqxTopic, GetErrorCode(err)
+}
+
+// Query the given topic to TNS [Topic name server] server with context.
+// Request is canceled when ctx is done, deadline of ctx is applied to it.
+// On failure, returned error is *EZMQXError which holds error code along with failed operation and cause.
+func (instance *EZMQXTopicDiscovery) QueryWithContext(ctx context.Context, topic string) (*EZMQXTopic, error) {
+	topics, err := instance.queryInternal(ctx, topic, false)
+	if err != nil {
+		return nil, err
 	}
-	return topics.Front().Value.(*EZMQXTopic), result
+	return topics.Front().Value.(*EZMQXTopic), nil
 }
 
 // Query the given topic to TNS [Topic name server] server.
@@ -55,90 +67,92 @@ func (instance *EZMQXTopicDiscovery) Query(topic string) (*EZMQXTopic, EZMQXErro
 //
 // For example: If topic name is /Topic then in success case TNS will
 // return /Topic/A, /Topic/A/B etc.
+//
+// Topic can be a pattern, where '*' matches one level and '#' matches any number of levels.
+// For example: /Topic/*/B matches /Topic/A/B and /Topic/# matches /Topic/A, /Topic/A/B etc.
 func (instance *EZMQXTopicDiscovery) HierarchicalQuery(topic string) (*list.List, EZMQXErrorCode) {
-	return instance.queryInternal(topic, true)
+	topics, err := instance.HierarchicalQueryWithContext(context.Background(), topic)
+	return topics, GetErrorCode(err)
 }
 
-func (instance *EZMQXTopicDiscovery) queryInternal(topic string, isHierarchical bool) (*list.List, EZMQXErrorCode) {
+// Query the given topic to TNS [Topic name server] server with hierarchical option and context.
+// Request is canceled when ctx is done, deadline of ctx is applied to it.
+// On failure, returned error is *EZMQXError which holds error code along with failed operation and cause.
+func (instance *EZMQXTopicDiscovery) HierarchicalQueryWithContext(ctx context.Context, topic string) (*list.List, error) {
+	return instance.queryInternal(ctx, topic, true)
+}
+
+func (instance *EZMQXTopicDiscovery) queryInternal(ctx context.Context, topic string, isHierarchical bool) (*list.List, error) {
 	if instance.ezmqxCtx.isCtxTerminated() {
-		return nil, EZMQX_TERMINATED
+		return nil, toError(EZMQX_TERMINATED, "Query")
 	}
 	if !instance.ezmqxCtx.isCtxTnsEnabled() {
-		return nil, EZMQX_TNS_NOT_AVAILABLE
+		return nil, toError(EZMQX_TNS_NOT_AVAILABLE, "Query")
 	}
-	result := validateTopic(topic)
-	if false == result {
-		return nil, EZMQX_INVALID_TOPIC
+	if isHierarchical && isTopicPattern(topic) {
+		return instance.queryPattern(ctx, topic)
+	}
+	err := validateTopicName("Query", topic)
+	if err != nil {
+		return nil, err
 	}
-	return instance.verifyTopic(topic, isHierarchical)
+	return instance.verifyTopic(ctx, topic, isHierarchical)
 }
 
-func (instance *EZMQXTopicDiscovery) parseTNSResponse(data []byte) (*list.List, EZMQXErrorCode) {
-	ezmqxTopicList := list.New()
-	topics := make(map[string][]interface{})
-	err := json.Unmarshal([]byte(data), &topics)
+// Queries TNS hierarchically with levels before first wildcard and filters the result by pattern,
+// as TNS supports only prefix hierarchy.
+func (instance *EZMQXTopicDiscovery) queryPattern(ctx context.Context, pattern string) (*list.List, error) {
+	if !validateTopicPattern(pattern) {
+		return nil, newError(EZMQX_INVALID_TOPIC, "Query", "invalid topic pattern "+pattern)
+	}
+	topics, err := instance.verifyTopic(ctx, getTopicPatternPrefix(pattern), true)
 	if err != nil {
-		Logger.Error("parseTNSResponse: Unmarshal failed")
-		return nil, EZMQX_REST_ERROR
+		return nil, err
 	}
-	topicList, exists := topics[PAYLOAD_TOPICS]
-	if !exists {
-		Logger.Error("No topics key exists in json response")
-		return nil, EZMQX_REST_ERROR
+	matched := filterTopics(topics, pattern)
+	if 0 == matched.Len() {
+		return nil, newError(EZMQX_NO_TOPIC_MATCHED, "Query", "no topic matched "+pattern)
 	}
-	for _, item := range topicList {
-		stringMap := item.(map[string]interface{})
-		dataModel, exists := stringMap[PAYLOAD_DATAMODEL].(string)
-		if !exists {
-			Logger.Error("No data model key exists in json response")
-			return nil, EZMQX_REST_ERROR
-		}
-		endPoint, exists := stringMap[PAYLOAD_ENDPOINT].(string)
-		if !exists {
-			Logger.Error("No end point key exists in json response")
-			return nil, EZMQX_REST_ERROR
-		}
-		name, exists := stringMap[PAYLOAD_NAME].(string)
-		if !exists {
-			Logger.Error("No name exists in json response")
-			return nil, EZMQX_REST_ERROR
-		}
-		isSecured, exists := stringMap[PAYLOAD_SECURED].(bool)
-		if !exists {
-			Logger.Error("No secured key exists in json response")
-			return nil, EZMQX_REST_ERROR
+	return matched, nil
+}
+
+// Returns topics [*EZMQXTopic] matching the pattern.
+func filterTopics(topics *list.List, pattern string) *list.List {
+	matched := list.New()
+	for element := topics.Front(); element != nil; element = element.Next() {
+		topic := element.Value.(*EZMQXTopic)
+		if matchTopic(pattern, topic.GetName()) {
+			matched.PushBack(topic)
 		}
-		ezmqXEndPoint := GetEZMQXEndPoint(endPoint)
-		ezmqxTopic := GetEZMQXTopic(name, dataModel, isSecured, ezmqXEndPoint)
-		ezmqxTopicList.PushBack(ezmqxTopic)
 	}
-	return ezmqxTopicList, EZMQX_OK
+	return matched
 }
 
-func (instance *EZMQXTopicDiscovery) verifyTopic(topic string, isHierarchical bool) (*list.List, EZMQXErrorCode) {
-	tnsURL := instance.ezmqxCtx.ctxGetTnsAddr() + PREFIX + TOPIC
-	Logger.Debug("[Topic discovery]", zap.String("Rest URL:", tnsURL))
-
-	var hierarchical string
-	if true == isHierarchical {
-		hierarchical = QUERY_TRUE
-	} else {
-		hierarchical = QUERY_FALSE
+func (instance *EZMQXTopicDiscovery) verifyTopic(ctx context.Context, topic string, isHierarchical bool) (*list.List, error) {
+	topics, statusCode, err := instance.queryTopics(ctx, topic, isHierarchical)
+	if err != nil {
+		return nil, err
 	}
-	query := QUERY_NAME + topic + QUERY_HIERARCHICAL + hierarchical
-	Logger.Debug("[Topic discovery]", zap.String("query:", query))
+	if statusCode != HTTP_OK {
+		Logger.Error("[Topic discovery]: Response code is not HTTP_OK")
+		return nil, newError(EZMQX_REST_ERROR, "verifyTopic", "unexpected status code "+strconv.Itoa(statusCode))
+	}
+	return topics, nil
+}
 
-	client := GetRestFactory()
-	response, err := client.Get(tnsURL + QUESTION_MARK + query)
-	if err != EZMQX_OK {
-		Logger.Error("[Topic discovery]: request failed")
-		return nil, EZMQX_REST_ERROR
+// Returns topics and status code of TNS response, topics are nil if status code is not HTTP_OK.
+func (instance *EZMQXTopicDiscovery) queryTopics(ctx context.Context, topic string, isHierarchical bool) (*list.List, int, error) {
+	tnsTopics, statusCode, err := getContextTNSClient(instance.ezmqxCtx).query(ctx, topic, isHierarchical)
+	if err != nil || statusCode != HTTP_OK {
+		return nil, statusCode, err
 	}
-	if response.GetStatusCode() != HTTP_OK {
-		Logger.Error("[Topic discovery]: Response code is not HTTP_OK")
-		return nil, EZMQX_REST_ERROR
+	ezmqxTopicList := list.New()
+	for _, tnsTopic := range tnsTopics {
+		ezmqxTopic, err := tnsTopic.ToEZMQXTopic()
+		if err != nil {
+			return nil, statusCode, err
+		}
+		ezmqxTopicList.PushBack(ezmqxTopic)
 	}
-	data := response.GetResponse()
-	Logger.Debug("[Topic discovery]: ", zap.String("response:", string(data)))
-	return instance.parseTNSResponse(data)
+	return ezmqxTopicList, HTTP_OK, nil
 }
@@ -19,7 +19,7 @@ package ezmqx
 
 import (
 	"container/list"
-	"encoding/json"
+	"context"
 	"fmt"
 	zmq "github.com/pebbe/zmq4"
 	"go.uber.org/zap"
@@ -32,6 +32,18 @@ import (
 	"time"
 )
 
+type EZMQXRegistrationState int
+
+// Constants represents TNS registration state of published topic.
+const (
+	TOPIC_REGISTERED   = 0
+	TOPIC_UNREGISTERED = 1
+)
+
+// Callback to get TNS registration state changes of published topic.
+// TOPIC_UNREGISTERED is notified when keep alive fails, TOPIC_REGISTERED when topic is re-registered.
+type EZMQXRegistrationCB func(topic string, state EZMQXRegistrationState)
+
 type EZMQXTopicHandler struct {
 	context            *zmq.Context
 	topicServer        *zmq.Socket
@@ -42,33 +54,38 @@ type EZMQXTopicHandler struct {
 	keepAliveInterval  atomic.Value
 	isKeepAliveStarted atomic.Value
 	isRoutineStarted   atomic.Value
-	tnsAddress         string
 	topicList          *list.List
 	shutdownChan       chan string
 	mutex              *sync.Mutex
 	status             uint32
+	// registration payload and state of topics, guarded by registrationMutex
+	registrations     map[string][]byte
+	unregistered      map[string]bool
+	registrationMutex *sync.Mutex
+	// 1, while re-registration worker go routine is running
+	isReRegistering int32
+	// stops re-registration worker and is closed when it is stopped, guarded by registrationMutex
+	reRegistrationCancel context.CancelFunc
+	reRegistrationDone   chan struct{}
 }
 
-var topicHandler *EZMQXTopicHandler
-var handlerMutex = &sync.Mutex{}
-
-func getTopicHandler() *EZMQXTopicHandler {
-	handlerMutex.Lock()
-	defer handlerMutex.Unlock()
-	if nil == topicHandler {
-		topicHandler = &EZMQXTopicHandler{}
-		topicHandler.context = ezmq.GetInstance().GetContext()
-		topicHandler.tnsAddress = getContextInstance().ctxGetTnsAddr()
-		var interval int64 = -1
-		topicHandler.keepAliveInterval.Store(interval)
-		topicHandler.isKeepAliveStarted.Store(false)
-		topicHandler.isRoutineStarted.Store(false)
-		topicHandler.topicList = list.New()
-		topicHandler.shutdownChan = nil
-		topicHandler.mutex = &sync.Mutex{}
-		topicHandler.status = CREATED
-	}
-	return topicHandler
+func createTopicHandler(ezmqxContext *EZMQXContext) *EZMQXTopicHandler {
+	var instance *EZMQXTopicHandler
+	instance = &EZMQXTopicHandler{}
+	instance.context = ezmq.GetInstance().GetContext()
+	instance.ezmqxContext = ezmqxContext
+	var interval int64 = -1
+	instance.keepAliveInterval.Store(interval)
+	instance.isKeepAliveStarted.Store(false)
+	instance.isRoutineStarted.Store(false)
+	instance.topicList = list.New()
+	instance.shutdownChan = nil
+	instance.mutex = &sync.Mutex{}
+	instance.status = CREATED
+	instance.registrations = make(map[string][]byte)
+	instance.unregistered = make(map[string]bool)
+	instance.registrationMutex = &sync.Mutex{}
+	return instance
 }
 
 func (instance *EZMQXTopicHandler) initHandler() {
@@ -102,7 +119,7 @@ func (instance *EZMQXTopicHandler) initHandler() {
 	}
 	//call a go routine [new thread] for handler
 	if false == instance.isRoutineStarted.Load() {
-		topicHandler.isRoutineStarted.Store(true)
+		instance.isRoutineStarted.Store(true)
 		go handleEvents(instance)
 		Logger.Debug("Topic Handler thread started")
 	}
@@ -223,6 +240,33 @@ func (instance *EZMQXTopicHandler) removeTopic(topic string) {
 			Logger.Debug("Removed topic from list", zap.String("Topic: ", topic))
 		}
 	}
+	instance.dropRegistration(topic)
+}
+
+func (instance *EZMQXTopicHandler) storeRegistration(topic string, payload []byte) {
+	instance.registrationMutex.Lock()
+	defer instance.registrationMutex.Unlock()
+	instance.registrations[topic] = payload
+	delete(instance.unregistered, topic)
+}
+
+// Removes stored registration of topic, so that it is not re-registered anymore.
+func (instance *EZMQXTopicHandler) dropRegistration(topic string) {
+	instance.registrationMutex.Lock()
+	defer instance.registrationMutex.Unlock()
+	delete(instance.registrations, topic)
+	delete(instance.unregistered, topic)
+}
+
+// Returns stored registration payload of topic, if topic is still waiting for re-registration.
+func (instance *EZMQXTopicHandler) getPendingRegistration(topic string) ([]byte, bool) {
+	instance.registrationMutex.Lock()
+	defer instance.registrationMutex.Unlock()
+	if !instance.unregistered[topic] {
+		return nil, false
+	}
+	payload, exists := instance.registrations[topic]
+	return payload, exists
 }
 
 func (instance *EZMQXTopicHandler) sendKeepAlive() {
@@ -234,20 +278,148 @@ func (instance *EZMQXTopicHandler) sendKeepAlive() {
 		i++
 	}
 	instance.mutex.Unlock()
-	payload := make(map[string]interface{})
-	payload[PAYLOAD_TOPIC_KA] = topicArray
+	payload := EZMQXTNSKeepAliveRequest{TopicNames: topicArray}
 	fmt.Println("Payload to send: \n\n", payload)
-	jsonPayload, error := json.Marshal(payload)
-	if error != nil {
-		Logger.Error("send Keep alive: json marshal failed")
+	duration := time.Duration(instance.keepAliveInterval.Load().(int64)) * time.Second * 2
+	ctx, cancel := context.WithTimeout(context.Background(), duration)
+	defer cancel()
+	err := getContextTNSClient(instance.ezmqxContext).KeepAlive(ctx, payload)
+	if err != nil {
+		// TNS is not reachable or does not know one or more topics [e.g. TNS restarted]
+		Logger.Error("[Send Keep Alive] failed", zap.Error(err))
+		instance.markUnregistered(topicArray)
+	}
+	instance.startReRegistration(duration)
+}
+
+// Marks given topics as unregistered and notifies registration state change.
+func (instance *EZMQXTopicHandler) markUnregistered(topics []string) {
+	var changed []string
+	instance.registrationMutex.Lock()
+	for _, topic := range topics {
+		_, exists := instance.registrations[topic]
+		if exists && !instance.unregistered[topic] {
+			instance.unregistered[topic] = true
+			changed = append(changed, topic)
+		}
+	}
+	instance.registrationMutex.Unlock()
+	for _, topic := range changed {
+		instance.notifyRegistration(topic, TOPIC_UNREGISTERED)
+	}
+}
+
+// Starts a worker go routine to re-register unregistered topics, so that
+// TNS requests do not block the handler go routine.
+// Does nothing, if a previous worker is still running or handler is terminating.
+// Worker is stopped and waited for in terminateHandler.
+func (instance *EZMQXTopicHandler) startReRegistration(timeout time.Duration) {
+	if atomic.LoadUint32(&instance.status) != INITIALIZED {
 		return
 	}
-	keepAliveURL := instance.tnsAddress + PREFIX + TNS_KEEP_ALIVE
-	Logger.Debug("[Send Keep Alive]", zap.String("Rest URL:", keepAliveURL))
-	client := GetRestFactory()
-	duration := time.Duration(instance.keepAliveInterval.Load().(int64)) * time.Second * 2
-	response, _ := client.Post1(keepAliveURL, jsonPayload, duration)
-	Logger.Debug("[Send Keep Alive] ", zap.Int("Response Status code: ", response.GetStatusCode()))
+	if false == atomic.CompareAndSwapInt32(&instance.isReRegistering, 0, 1) {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	instance.registrationMutex.Lock()
+	instance.reRegistrationCancel = cancel
+	instance.reRegistrationDone = done
+	instance.registrationMutex.Unlock()
+	go func() {
+		defer close(done)
+		defer atomic.StoreInt32(&instance.isReRegistering, 0)
+		instance.reRegisterTopics(ctx, timeout)
+	}()
+}
+
+// Cancels running re-registration worker and waits till it is stopped.
+// Wait is bounded, as worker may call registration callback which terminates the handler.
+func (instance *EZMQXTopicHandler) stopReRegistration() {
+	instance.registrationMutex.Lock()
+	cancel := instance.reRegistrationCancel
+	done := instance.reRegistrationDone
+	instance.reRegistrationCancel = nil
+	instance.reRegistrationDone = nil
+	instance.registrationMutex.Unlock()
+	if nil == cancel {
+		return
+	}
+	cancel()
+	select {
+	case <-done:
+		Logger.Debug("Re-registration worker stopped")
+	case <-time.After(1 * time.Second):
+		Logger.Debug("Timeout occured for re-registration worker")
+	}
+}
+
+// Posts stored registrations of unregistered topics to TNS again.
+// Topic is registered, if TNS creates the topic or already knows it.
+// Topic unregistered by publisher during the post is deleted from TNS again.
+// Worker stops, when ctx is canceled.
+func (instance *EZMQXTopicHandler) reRegisterTopics(workerCtx context.Context, timeout time.Duration) {
+	var topics []string
+	instance.registrationMutex.Lock()
+	for topic := range instance.unregistered {
+		topics = append(topics, topic)
+	}
+	instance.registrationMutex.Unlock()
+	if 0 == len(topics) {
+		return
+	}
+	topicURL := instance.ezmqxContext.ctxGetTnsAddr() + PREFIX + TOPIC
+	client := instance.ezmqxContext.getRestFactory()
+	tnsClient := getContextTNSClient(instance.ezmqxContext)
+	for _, topic := range topics {
+		if workerCtx.Err() != nil {
+			Logger.Debug("[Re-register topic] Worker stopped")
+			return
+		}
+		payload, pending := instance.getPendingRegistration(topic)
+		if !pending {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(workerCtx, timeout)
+		response, err := client.postWithError(ctx, REST_ENDPOINT_TNS, topicURL, payload)
+		cancel()
+		if err != nil || nil == response {
+			Logger.Error("[Re-register topic] Post request failed", zap.String("Topic: ", topic), zap.Error(err))
+			continue
+		}
+		statusCode := response.GetStatusCode()
+		if statusCode != HTTP_CREATED && statusCode != HTTP_OK && statusCode != HTTP_CONFLICT {
+			Logger.Error("[Re-register topic] Unexpected status code", zap.String("Topic: ", topic), zap.Int("Status code: ", statusCode))
+			continue
+		}
+		instance.registrationMutex.Lock()
+		_, registered := instance.registrations[topic]
+		_, exists := instance.unregistered[topic]
+		delete(instance.unregistered, topic)
+		instance.registrationMutex.Unlock()
+		if !registered {
+			// publisher unregistered the topic while posting
+			Logger.Debug("[Re-register topic] Topic unregistered, deleting from TNS", zap.String("Topic: ", topic))
+			ctx, cancel = context.WithTimeout(context.Background(), timeout)
+			err = tnsClient.Unregister(ctx, topic)
+			cancel()
+			if err != nil {
+				Logger.Error("[Re-register topic] Delete request failed", zap.String("Topic: ", topic), zap.Error(err))
+			}
+			continue
+		}
+		if exists {
+			Logger.Debug("[Re-register topic] Registered", zap.String("Topic: ", topic))
+			instance.notifyRegistration(topic, TOPIC_REGISTERED)
+		}
+	}
+}
+
+func (instance *EZMQXTopicHandler) notifyRegistration(topic string, state EZMQXRegistrationState) {
+	callback := instance.ezmqxContext.getRegistrationCB()
+	if nil != callback {
+		callback(topic, state)
+	}
 }
 
 func (instance *EZMQXTopicHandler) terminateHandler() {
@@ -290,7 +462,12 @@ func (instance *EZMQXTopicHandler) terminateHandler() {
 	instance.poller = nil
 	instance.topicServer = nil
 	instance.topicClient = nil
+	instance.stopReRegistration()
 	instance.topicList.Init()
+	instance.registrationMutex.Lock()
+	instance.registrations = make(map[string][]byte)
+	instance.unregistered = make(map[string]bool)
+	instance.registrationMutex.Unlock()
 	var interval int64 = -1
 	instance.keepAliveInterval.Store(interval)
 	instance.isKeepAliveStarted.Store(false)
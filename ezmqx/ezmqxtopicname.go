@@ -0,0 +1,149 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmqx
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Maximum length of topic name accepted by TNS.
+const TOPIC_NAME_MAX_LENGTH = 255
+
+// Segments which are reserved, as they represent relative hierarchy.
+var reservedSegments = map[string]bool{".": true, "..": true}
+
+// Structure represents validated topic name.
+// Topic name is hierarchical [e.g. /Robot/Arm/Sensor], each level separated by '/' is a segment.
+type EZMQXTopicName struct {
+	name     string
+	segments []string
+}
+
+// Parse and validate given topic name.
+// Surrounding white spaces and trailing '/' are removed before validation.
+//
+// Topic name should start with '/', should not exceed TOPIC_NAME_MAX_LENGTH and should not have
+// empty or reserved ['.' and '..'] segments. Segments can have only ASCII letters, digits, '-', '_' and '.'.
+// On failure, returned error is *EZMQXError with EZMQX_INVALID_TOPIC, which explains why name is rejected.
+func ParseTopicName(name string) (*EZMQXTopicName, error) {
+	normalized := strings.TrimSpace(name)
+	for len(normalized) > 1 && strings.HasSuffix(normalized, F_SLASH) {
+		normalized = strings.TrimSuffix(normalized, F_SLASH)
+	}
+	err := checkTopicName(normalized)
+	if err != nil {
+		return nil, GetEZMQXError(EZMQX_INVALID_TOPIC, "ParseTopicName", err)
+	}
+	var instance *EZMQXTopicName
+	instance = &EZMQXTopicName{}
+	instance.name = normalized
+	instance.segments = strings.Split(normalized[1:], F_SLASH)
+	return instance, nil
+}
+
+// Get topic name.
+func (instance *EZMQXTopicName) GetName() string {
+	return instance.name
+}
+
+// String returns topic name.
+func (instance *EZMQXTopicName) String() string {
+	return instance.name
+}
+
+// Get hierarchy segments of topic name.
+// For example: /Robot/Arm returns [Robot, Arm].
+func (instance *EZMQXTopicName) GetSegments() []string {
+	segments := make([]string, len(instance.segments))
+	copy(segments, instance.segments)
+	return segments
+}
+
+// Get parent topic name, nil if topic name has only one segment.
+func (instance *EZMQXTopicName) GetParent() *EZMQXTopicName {
+	if len(instance.segments) < 2 {
+		return nil
+	}
+	var parent *EZMQXTopicName
+	parent = &EZMQXTopicName{}
+	parent.segments = instance.segments[:len(instance.segments)-1]
+	parent.name = F_SLASH + strings.Join(parent.segments, F_SLASH)
+	return parent
+}
+
+// Check whether given topic name is under hierarchy of this topic name.
+// For example: /Robot is ancestor of /Robot/Arm and /Robot/Arm/Sensor.
+func (instance *EZMQXTopicName) IsAncestorOf(topicName *EZMQXTopicName) bool {
+	if nil == topicName || len(topicName.segments) <= len(instance.segments) {
+		return false
+	}
+	for i, segment := range instance.segments {
+		if segment != topicName.segments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns error which explains why topic name is invalid, nil if it is valid.
+// Topic name is checked as it is, without normalization.
+func checkTopicName(name string) error {
+	if 0 == len(name) {
+		return errors.New("topic name is empty")
+	}
+	if !strings.HasPrefix(name, F_SLASH) {
+		return errors.New("topic name " + strconv.Quote(name) + " does not start with '/'")
+	}
+	if len(name) > TOPIC_NAME_MAX_LENGTH {
+		return errors.New("topic name exceeds " + strconv.Itoa(TOPIC_NAME_MAX_LENGTH) + " bytes")
+	}
+	for i, segment := range strings.Split(name[1:], F_SLASH) {
+		if 0 == len(segment) {
+			return errors.New("topic name " + strconv.Quote(name) + " has empty segment at level " + strconv.Itoa(i+1))
+		}
+		if reservedSegments[segment] {
+			return errors.New("segment " + strconv.Quote(segment) + " of topic name is reserved")
+		}
+		for index, character := range segment {
+			if character >= utf8.RuneSelf {
+				return errors.New("segment " + strconv.Quote(segment) + " has non ASCII character " + strconv.QuoteRune(character) + " at index " + strconv.Itoa(index))
+			}
+			if !isTopicCharacter(character) {
+				return errors.New("segment " + strconv.Quote(segment) + " has invalid character " + strconv.QuoteRune(character) + " at index " + strconv.Itoa(index))
+			}
+		}
+	}
+	return nil
+}
+
+func isTopicCharacter(character rune) bool {
+	return ('a' <= character && character <= 'z') || ('A' <= character && character <= 'Z') ||
+		('0' <= character && character <= '9') || '-' == character || '_' == character || '.' == character
+}
+
+// Returns EZMQX error with EZMQX_INVALID_TOPIC for given operation, nil if topic name is valid.
+func validateTopicName(op string, name string) error {
+	err := checkTopicName(name)
+	if err != nil {
+		return GetEZMQXError(EZMQX_INVALID_TOPIC, op, err)
+	}
+	return nil
+}
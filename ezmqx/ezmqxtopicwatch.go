@@ -0,0 +1,196 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmqx
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"sync"
+	"time"
+)
+
+// Number of topic events buffered on watch channel.
+const WATCH_BUFFER_SIZE = 100
+
+// Structure represents topic event of topic discovery watch.
+type EZMQXTopicEvent struct {
+	topic  EZMQXTopic
+	change EZMQXTopicChange
+}
+
+// Get topic of event. For TOPIC_REMOVED, it is last known topic.
+func (event EZMQXTopicEvent) GetTopic() EZMQXTopic {
+	return event.topic
+}
+
+// Get change of topic.
+func (event EZMQXTopicEvent) GetChange() EZMQXTopicChange {
+	return event.change
+}
+
+// Structure represents watch of topic discovery.
+type EZMQXTopicWatch struct {
+	events   chan EZMQXTopicEvent
+	stopChan chan bool
+	doneChan chan bool
+	once     *sync.Once
+}
+
+// Watch topics matching given topic pattern on TNS [Topic name server] server.
+// Topic pattern is queried with hierarchical option, so topics under the pattern are watched.
+// Topic pattern can have '*' and '#' wildcards same as HierarchicalQuery.
+//
+// TNS does not push topic changes, so TNS is polled on given interval and the result is compared
+// with previous one. Existing topics are notified as TOPIC_ADDED on first poll.
+func (instance *EZMQXTopicDiscovery) Watch(topicPattern string, interval time.Duration) (*EZMQXTopicWatch, EZMQXErrorCode) {
+	watch, err := instance.WatchWithContext(context.Background(), topicPattern, interval)
+	return watch, GetErrorCode(err)
+}
+
+// Watch topics matching given topic pattern on TNS [Topic name server] server with context.
+// Watch is stopped when ctx is done, deadline of ctx is applied to it.
+// On failure, returned error is *EZMQXError which holds error code along with failed operation and cause.
+func (instance *EZMQXTopicDiscovery) WatchWithContext(ctx context.Context, topicPattern string, interval time.Duration) (*EZMQXTopicWatch, error) {
+	if interval <= 0 {
+		Logger.Error("Invalid watch interval")
+		return nil, toError(EZMQX_INVALID_PARAM, "Watch")
+	}
+	if instance.ezmqxCtx.isCtxTerminated() {
+		return nil, toError(EZMQX_TERMINATED, "Watch")
+	}
+	if !instance.ezmqxCtx.isCtxTnsEnabled() {
+		return nil, toError(EZMQX_TNS_NOT_AVAILABLE, "Watch")
+	}
+	if !validateTopicOrPattern(topicPattern) {
+		Logger.Error("Invalid topic pattern")
+		return nil, toError(EZMQX_INVALID_TOPIC, "Watch")
+	}
+	var watch *EZMQXTopicWatch
+	watch = &EZMQXTopicWatch{}
+	watch.events = make(chan EZMQXTopicEvent, WATCH_BUFFER_SIZE)
+	watch.stopChan = make(chan bool)
+	watch.doneChan = make(chan bool)
+	watch.once = &sync.Once{}
+	go instance.watch(ctx, watch, topicPattern, interval)
+	return watch, nil
+}
+
+// Get channel of topic events. Channel is closed when watch is stopped.
+func (watch *EZMQXTopicWatch) GetEvents() <-chan EZMQXTopicEvent {
+	return watch.events
+}
+
+// Stop watch and wait till polling routine is stopped.
+func (watch *EZMQXTopicWatch) Stop() EZMQXErrorCode {
+	watch.once.Do(func() {
+		close(watch.stopChan)
+	})
+	<-watch.doneChan
+	return EZMQX_OK
+}
+
+func (instance *EZMQXTopicDiscovery) watch(ctx context.Context, watch *EZMQXTopicWatch, topicPattern string, interval time.Duration) {
+	defer close(watch.doneChan)
+	defer close(watch.events)
+	known := make(map[string]*EZMQXTopic)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if !instance.poll(ctx, watch, topicPattern, interval, known) {
+			return
+		}
+		select {
+		case <-watch.stopChan:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Queries TNS and sends events for the difference with known topics.
+// Returns false if watch is stopped.
+func (instance *EZMQXTopicDiscovery) poll(ctx context.Context, watch *EZMQXTopicWatch, topicPattern string, timeout time.Duration, known map[string]*EZMQXTopic) bool {
+	if instance.ezmqxCtx.isCtxTerminated() {
+		Logger.Debug("Context terminated, stopping watch")
+		return false
+	}
+	queryCtx, cancel := context.WithTimeout(ctx, timeout)
+	topics, statusCode, err := instance.queryTopics(queryCtx, getTopicPatternPrefix(topicPattern), true)
+	cancel()
+	if err != nil {
+		Logger.Error("[Topic watch] query failed", zap.Error(err))
+		return true
+	}
+	latest := make(map[string]*EZMQXTopic)
+	if HTTP_OK == statusCode {
+		for element := topics.Front(); element != nil; element = element.Next() {
+			topic := element.Value.(*EZMQXTopic)
+			if !isTopicPattern(topicPattern) || matchTopic(topicPattern, topic.GetName()) {
+				latest[topic.GetName()] = topic
+			}
+		}
+	} else if HTTP_NOT_FOUND != statusCode {
+		// Not found means none of the topics matched, so only that is treated as empty result
+		Logger.Error("[Topic watch] unexpected status code", zap.Int("Status code: ", statusCode))
+		return true
+	}
+	var events []EZMQXTopicEvent
+	for name, topic := range known {
+		latestTopic, exists := latest[name]
+		if !exists {
+			events = append(events, EZMQXTopicEvent{*topic, TOPIC_REMOVED})
+			delete(known, name)
+		} else if isTopicChanged(topic, latestTopic) {
+			events = append(events, EZMQXTopicEvent{*latestTopic, getTopicChange(*topic, *latestTopic)})
+			known[name] = latestTopic
+		}
+	}
+	for name, topic := range latest {
+		if _, exists := known[name]; !exists {
+			events = append(events, EZMQXTopicEvent{*topic, TOPIC_ADDED})
+			known[name] = topic
+		}
+	}
+	for _, event := range events {
+		select {
+		case watch.events <- event:
+		case <-watch.stopChan:
+			return false
+		case <-ctx.Done():
+			return false
+		}
+	}
+	return true
+}
+
+func isTopicChanged(previous *EZMQXTopic, latest *EZMQXTopic) bool {
+	return previous.GetEndPoint().ToString() != latest.GetEndPoint().ToString() ||
+		previous.GetDataModel() != latest.GetDataModel() ||
+		previous.IsSecured() != latest.IsSecured() ||
+		previous.GetCompression() != latest.GetCompression() ||
+		endPointString(previous.GetSnapshotEndPoint()) != endPointString(latest.GetSnapshotEndPoint())
+}
+
+func endPointString(endPoint *EZMQXEndpoint) string {
+	if nil == endPoint {
+		return ""
+	}
+	return endPoint.ToString()
+}
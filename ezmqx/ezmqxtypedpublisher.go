@@ -0,0 +1,181 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmqx
+
+import "context"
+
+// Callback to get result of asynchronous publish of typed publisher.
+type EZMQXTypedPublishCB[T any] func(value T, errorCode EZMQXErrorCode)
+
+// Structure represents EZMQX publisher of values of type T.
+// Values are encoded by codec and topic is registered to TNS with data model of codec.
+type EZMQXTypedPublisher[T any] struct {
+	publisher *EZMQXPublisher
+	codec     EZMQXCodec[T]
+	isSecured bool
+}
+
+// Get EZMQX typed publisher instance with context.
+// On failure, returned error is *EZMQXError which holds error code along with failed operation and cause.
+// Flow control of config [SetFlowControl] is used if optionalFlowControl is nil.
+func GetTypedPublisher[T any](ctx context.Context, topic string, codec EZMQXCodec[T], optionalPort int, optionalFlowControl *EZMQXFlowControl) (*EZMQXTypedPublisher[T], error) {
+	return GetTypedPublisherWithConfig(ctx, GetConfigInstance(), topic, codec, optionalPort, optionalFlowControl)
+}
+
+// Get EZMQX typed publisher instance of given config instance with context.
+// On failure, returned error is *EZMQXError which holds error code along with failed operation and cause.
+// Flow control of config [SetFlowControl] is used if optionalFlowControl is nil.
+func GetTypedPublisherWithConfig[T any](ctx context.Context, configInstance *EZMQXConfig, topic string, codec EZMQXCodec[T], optionalPort int, optionalFlowControl *EZMQXFlowControl) (*EZMQXTypedPublisher[T], error) {
+	if nil == codec {
+		return nil, newError(EZMQX_INVALID_PARAM, "GetTypedPublisher", "codec is nil")
+	}
+	return createTypedPublisher(ctx, configInstance.context, topic, codec, optionalPort, optionalFlowControl)
+}
+
+// Publish value on the socket for subscribers.
+func (instance *EZMQXTypedPublisher[T]) Publish(value T) EZMQXErrorCode {
+	publisher := instance.publisher
+	if nil == publisher {
+		Logger.Error("Publisher is null")
+		return EZMQX_UNKNOWN_STATE
+	}
+	if publisher.context.isCtxTerminated() {
+		Logger.Error("Context terminated")
+		instance.Terminate()
+		return EZMQX_TERMINATED
+	}
+	data, result := instance.codec.Encode(value)
+	if result != EZMQX_OK {
+		Logger.Error("Encode failed")
+		return result
+	}
+	return publisher.publish(data)
+}
+
+// Publish value asynchronously.
+// Value is encoded on caller's goroutine and published on a worker routine of this publisher, in the order of calls.
+// Result is notified through callback [callback can be nil], EZMQX_TERMINATED if publisher is
+// terminated before value is published.
+//
+// Queue size, overflow policy and linger on terminate follow flow control of publisher.
+// If send queue size is not set, ASYNC_QUEUE_SIZE, OVERFLOW_DROP and ASYNC_LINGER are used.
+// Returns EZMQX_MESSAGE_DROPPED if queue is full with OVERFLOW_DROP policy, callback is not called in that case.
+// Callbacks are called in order of publish on a separate goroutine, callback can call Terminate.
+func (instance *EZMQXTypedPublisher[T]) PublishAsync(value T, callback EZMQXTypedPublishCB[T]) EZMQXErrorCode {
+	publisher := instance.publisher
+	if nil == publisher {
+		Logger.Error("Publisher is null")
+		return EZMQX_UNKNOWN_STATE
+	}
+	if publisher.isTerminated() {
+		Logger.Error("Publisher terminated")
+		return EZMQX_TERMINATED
+	}
+	data, result := instance.codec.Encode(value)
+	if result != EZMQX_OK {
+		Logger.Error("Encode failed")
+		return result
+	}
+	message := queuedMessage{data: data}
+	if nil != callback {
+		message.callback = func(errorCode EZMQXErrorCode) {
+			callback(value, errorCode)
+		}
+	}
+	return publisher.getAsyncQueue(func(message queuedMessage) EZMQXErrorCode {
+		if publisher.context.isCtxTerminated() {
+			return EZMQX_TERMINATED
+		}
+		return publisher.publish(message.data)
+	}).push(message)
+}
+
+// Enable envelope on published messages.
+// Envelope carries per topic sequence number, publisher ID and publish time stamp,
+// which subscribers use to detect lost and duplicated messages.
+// If publisher ID is empty, a unique ID is generated. It should be called before publishing.
+func (instance *EZMQXTypedPublisher[T]) EnableEnvelope(publisherId string) EZMQXErrorCode {
+	publisher := instance.publisher
+	if nil == publisher {
+		return EZMQX_UNKNOWN_STATE
+	}
+	return publisher.enableEnvelope(publisherId)
+}
+
+// Get number of messages dropped by send queue and asynchronous publish queue.
+// Messages are dropped when queue is full with OVERFLOW_DROP policy or linger is expired on terminate.
+func (instance *EZMQXTypedPublisher[T]) GetDroppedCount() (uint64, EZMQXErrorCode) {
+	publisher := instance.publisher
+	if nil == publisher {
+		return 0, EZMQX_UNKNOWN_STATE
+	}
+	return publisher.getDroppedCount(), EZMQX_OK
+}
+
+// Terminate EZMQX typed publisher.
+// Pending asynchronous publish requests are published till linger is expired.
+// Their callbacks may be called after Terminate returns.
+func (instance *EZMQXTypedPublisher[T]) Terminate() EZMQXErrorCode {
+	publisher := instance.publisher
+	if nil == publisher {
+		return EZMQX_UNKNOWN_STATE
+	}
+	return publisher.terminate()
+}
+
+// Check whether publisher is terminated or not.
+func (instance *EZMQXTypedPublisher[T]) IsTerminated() (bool, EZMQXErrorCode) {
+	publisher := instance.publisher
+	if nil == publisher {
+		return false, EZMQX_UNKNOWN_STATE
+	}
+	return publisher.isTerminated(), EZMQX_OK
+}
+
+// Get instance of Topic that used on this publisher.
+func (instance *EZMQXTypedPublisher[T]) GetTopic() (*EZMQXTopic, EZMQXErrorCode) {
+	publisher := instance.publisher
+	if nil == publisher {
+		return nil, EZMQX_UNKNOWN_STATE
+	}
+	return publisher.getTopic(), EZMQX_OK
+}
+
+// Check whether publisher is secured or not.
+func (instance *EZMQXTypedPublisher[T]) IsSecured() (bool, EZMQXErrorCode) {
+	return instance.isSecured, EZMQX_OK
+}
+
+func createTypedPublisher[T any](ctx context.Context, context *EZMQXContext, topic string, codec EZMQXCodec[T], optionalPort int, flowControl *EZMQXFlowControl) (*EZMQXTypedPublisher[T], error) {
+	var instance *EZMQXTypedPublisher[T]
+	instance = &EZMQXTypedPublisher[T]{}
+	instance.codec = codec
+	instance.publisher = getPublisher(context, flowControl)
+	result := instance.publisher.initialize(optionalPort)
+	if result != EZMQX_OK {
+		return nil, toError(result, "initialize")
+	}
+	err := instance.publisher.registerDataModelTopic(ctx, topic, codec.GetDataModel(), false)
+	if err != nil {
+		Logger.Error("Register topic failed, stopping ezmq publisher")
+		instance.publisher.release()
+		return nil, err
+	}
+	instance.isSecured = false
+	return instance, nil
+}
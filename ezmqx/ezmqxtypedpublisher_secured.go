@@ -0,0 +1,24 @@
+// +build !unsecure
+
+package ezmqx
+
+import "context"
+
+func createSecuredTypedPublisher[T any](ezmqxContext *EZMQXContext, topic string, serverPrivateKey string, codec EZMQXCodec[T], optionalPort int) (*EZMQXTypedPublisher[T], EZMQXErrorCode) {
+	var instance *EZMQXTypedPublisher[T]
+	instance = &EZMQXTypedPublisher[T]{}
+	instance.codec = codec
+	instance.publisher = getPublisher(ezmqxContext, nil)
+	result := instance.publisher.initializeSecured(optionalPort, serverPrivateKey)
+	if result != EZMQX_OK {
+		return nil, result
+	}
+	result = GetErrorCode(instance.publisher.registerDataModelTopic(context.Background(), topic, codec.GetDataModel(), true))
+	if result != EZMQX_OK {
+		Logger.Error("Register topic failed, stopping ezmq publisher")
+		instance.publisher.release()
+		return nil, result
+	}
+	instance.isSecured = true
+	return instance, EZMQX_OK
+}
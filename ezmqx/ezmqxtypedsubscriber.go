@@ -0,0 +1,159 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmqx
+
+import (
+	"container/list"
+	"context"
+	"go.uber.org/zap"
+	"go/ezmq"
+	"time"
+)
+
+// Callback to get decoded value of all the subscribed events for a specific topic.
+type EZMQXTypedSubCB[T any] func(topic string, value T)
+
+// Structure represents EZMQX subscriber of values of type T.
+// Values are decoded by codec resolved for data model of each subscribed topic.
+type EZMQXTypedSubscriber[T any] struct {
+	subscriber *EZMQXSubscriber
+	isSecured  bool
+}
+
+// Get typed subscriber instance for given topic with context.
+// It will work, if EZMQX is configured in docker mode.
+// On failure, returned error is *EZMQXError which holds error code along with failed operation and cause.
+// Flow control of config [SetFlowControl] is used if optionalFlowControl is nil.
+func GetTypedSubscriber[T any](ctx context.Context, topic string, isHierarchical bool, resolver EZMQXCodecResolver[T], subCallback EZMQXTypedSubCB[T], errorCallback EZMQXSubErrorCB, optionalFlowControl *EZMQXFlowControl) (*EZMQXTypedSubscriber[T], error) {
+	return GetTypedSubscriberWithConfig(ctx, GetConfigInstance(), topic, isHierarchical, resolver, subCallback, errorCallback, optionalFlowControl)
+}
+
+// Get typed subscriber instance of given config instance for given topic with context.
+// It will work, if EZMQX is configured in docker mode.
+// On failure, returned error is *EZMQXError which holds error code along with failed operation and cause.
+// Flow control of config [SetFlowControl] is used if optionalFlowControl is nil.
+func GetTypedSubscriberWithConfig[T any](ctx context.Context, configInstance *EZMQXConfig, topic string, isHierarchical bool, resolver EZMQXCodecResolver[T], subCallback EZMQXTypedSubCB[T], errorCallback EZMQXSubErrorCB, optionalFlowControl *EZMQXFlowControl) (*EZMQXTypedSubscriber[T], error) {
+	if nil == resolver || nil == subCallback || nil == errorCallback {
+		return nil, newError(EZMQX_INVALID_PARAM, "GetTypedSubscriber", "resolver or callback is nil")
+	}
+	instance := createTypedSubscriber(configInstance.context, optionalFlowControl, resolver, subCallback, errorCallback)
+	err := instance.subscriber.initialize(ctx, topic, isHierarchical)
+	if err != nil {
+		Logger.Error("initialization failed", zap.Error(err))
+		return nil, err
+	}
+	return instance, nil
+}
+
+// Get typed subscriber instance for given topic list.
+// It will work, if EZMQX is configured in standalone mode.
+func GetTypedStandAloneSubscriber[T any](topics list.List, resolver EZMQXCodecResolver[T], subCallback EZMQXTypedSubCB[T], errorCallback EZMQXSubErrorCB) (*EZMQXTypedSubscriber[T], EZMQXErrorCode) {
+	return GetTypedStandAloneSubscriberWithConfig(GetConfigInstance(), topics, resolver, subCallback, errorCallback)
+}
+
+// Get typed subscriber instance of given config instance for given topic list.
+// It will work, if EZMQX is configured in standalone mode.
+func GetTypedStandAloneSubscriberWithConfig[T any](configInstance *EZMQXConfig, topics list.List, resolver EZMQXCodecResolver[T], subCallback EZMQXTypedSubCB[T], errorCallback EZMQXSubErrorCB) (*EZMQXTypedSubscriber[T], EZMQXErrorCode) {
+	if nil == resolver || nil == subCallback || nil == errorCallback {
+		return nil, EZMQX_INVALID_PARAM
+	}
+	instance := createTypedSubscriber(configInstance.context, nil, resolver, subCallback, errorCallback)
+	result := instance.subscriber.storeTopics(topics)
+	if result != EZMQX_OK {
+		Logger.Error("Store topic failed", zap.Int("Error code:", int(result)))
+		return nil, result
+	}
+	return instance, result
+}
+
+// Terminate EZMQX typed subscriber.
+func (instance *EZMQXTypedSubscriber[T]) Terminate() EZMQXErrorCode {
+	return instance.subscriber.terminate()
+}
+
+// Get number of messages dropped by receive queue.
+// Messages are dropped when queue is full with OVERFLOW_DROP policy or linger is expired on terminate.
+func (instance *EZMQXTypedSubscriber[T]) GetDroppedCount() (uint64, EZMQXErrorCode) {
+	return instance.subscriber.getDroppedCount(), EZMQX_OK
+}
+
+// Check whether subscriber is terminated or not.
+func (instance *EZMQXTypedSubscriber[T]) IsTerminated() (bool, EZMQXErrorCode) {
+	return instance.subscriber.isTerminated(), EZMQX_OK
+}
+
+// Get list of topics that subscribed by this subscriber.
+func (instance *EZMQXTypedSubscriber[T]) GetTopics() (*list.List, EZMQXErrorCode) {
+	return instance.subscriber.getTopics(), EZMQX_OK
+}
+
+// Enable periodic TNS query for the topic of this subscriber.
+// Publishers of newly appearing topics are subscribed, vanished topics are unsubscribed
+// and end point changes [e.g. publisher restarted] are followed. Each change is notified to callback.
+// It works only for subscribers created using TNS and is stopped on terminate.
+func (instance *EZMQXTypedSubscriber[T]) EnableTopicRefresh(interval time.Duration, callback EZMQXTopicChangeCB) EZMQXErrorCode {
+	return instance.subscriber.startRefresh(interval, callback)
+}
+
+// Check whether subscriber is secured or not.
+func (instance *EZMQXTypedSubscriber[T]) IsSecured() (bool, EZMQXErrorCode) {
+	return instance.isSecured, EZMQX_OK
+}
+
+func createTypedSubscriber[T any](context *EZMQXContext, flowControl *EZMQXFlowControl, resolver EZMQXCodecResolver[T], subCallback EZMQXTypedSubCB[T], errorCallback EZMQXSubErrorCB) *EZMQXTypedSubscriber[T] {
+	var instance *EZMQXTypedSubscriber[T]
+	instance = &EZMQXTypedSubscriber[T]{}
+	instance.subscriber = getEZMQXSubscriber(context, flowControl)
+	bindCodec(instance.subscriber, resolver, subCallback, errorCallback)
+	return instance
+}
+
+// Binds codec resolved for data model of each subscribed topic to subscriber.
+// Received bytes are decoded by codec of topic and passed to subCallback.
+func bindCodec[T any](subscriber *EZMQXSubscriber, resolver EZMQXCodecResolver[T], subCallback EZMQXTypedSubCB[T], errorCallback EZMQXSubErrorCB) {
+	codecs := make(map[string]EZMQXCodec[T])
+	subscriber.topicCB = func(topic EZMQXTopic) EZMQXErrorCode {
+		codec, result := resolver(topic.GetDataModel())
+		if result != EZMQX_OK {
+			Logger.Error("Resolve codec failed", zap.String("Data model: ", topic.GetDataModel()))
+			return result
+		}
+		codecs[topic.GetName()] = codec
+		return EZMQX_OK
+	}
+	subscriber.topicRemovedCB = func(topic string) {
+		delete(codecs, topic)
+	}
+	subscriber.internalErrCB = func(topic string, errorCode EZMQXErrorCode) {
+		errorCallback(topic, errorCode)
+	}
+	subscriber.internalCB = func(topic string, ezmqMsg ezmq.EZMQMessage) {
+		codec, exists := codecs[topic]
+		if 0 == len(topic) || !exists {
+			errorCallback(topic, EZMQX_UNKNOWN_TOPIC)
+			return
+		}
+		ezmqByteData := ezmqMsg.(ezmq.EZMQByteData)
+		value, result := codec.Decode(ezmqByteData.ByteData)
+		if result != EZMQX_OK {
+			errorCallback(topic, result)
+			return
+		}
+		subCallback(topic, value)
+	}
+}
@@ -18,7 +18,6 @@
 package ezmqx
 
 import (
-	"regexp"
 	"strings"
 )
 
@@ -28,27 +27,89 @@ const LOCAL_PORT_START = 4000
 const LOCAL_PORT_MAX = 100
 const F_SLASH = "/"
 const F_DOUBLE_SLASH = "//"
-const TOPIC_PATTERN = "^(/)[a-zA-Z0-9-_./]+$"
 const EMPTY_STRING = ""
 const KEY_LENGTH = 40
+const JSON_DATA_MODEL = "json"
 
-//const TOPIC_WILD_CARD = "*"
-//const TOPIC_WILD_PATTERN = "/*/";
+const TOPIC_WILD_CARD = "*"
+const TOPIC_MULTI_WILD_CARD = "#"
 const CREATED = 0
 const INITIALIZING = 1
 const INITIALIZED = 2
 const TERMINATING = 3
 
 func validateTopic(topic string) bool {
-	if 0 == len(topic) {
+	return nil == checkTopicName(topic)
+}
+
+// Checks whether topic has wildcard levels.
+func isTopicPattern(topic string) bool {
+	return strings.Contains(topic, TOPIC_WILD_CARD) || strings.Contains(topic, TOPIC_MULTI_WILD_CARD)
+}
+
+// Validates topic pattern.
+// '*' matches exactly one level and '#' matches any number of levels, it is allowed only as last level.
+// First level should not be a wildcard, as TNS is queried hierarchically with levels before first wildcard.
+func validateTopicPattern(pattern string) bool {
+	if 0 == len(pattern) || !strings.HasPrefix(pattern, F_SLASH) {
 		return false
 	}
-	if strings.Contains(topic, F_DOUBLE_SLASH) || strings.HasSuffix(topic, F_SLASH) {
+	levels := strings.Split(pattern[1:], F_SLASH)
+	if isWildCard(levels[0]) {
 		return false
 	}
-	result, _ := regexp.MatchString(TOPIC_PATTERN, topic)
-	if false == result {
-		return result
+	for i, level := range levels {
+		if level == TOPIC_MULTI_WILD_CARD && i != len(levels)-1 {
+			return false
+		}
+		if isWildCard(level) {
+			continue
+		}
+		if isTopicPattern(level) || !validateTopic(F_SLASH+level) {
+			return false
+		}
 	}
 	return true
 }
+
+func validateTopicOrPattern(topic string) bool {
+	if isTopicPattern(topic) {
+		return validateTopicPattern(topic)
+	}
+	return validateTopic(topic)
+}
+
+// Returns levels of topic pattern before first wildcard, it is used as hierarchical query to TNS.
+func getTopicPatternPrefix(pattern string) string {
+	levels := strings.Split(pattern[1:], F_SLASH)
+	prefix := EMPTY_STRING
+	for _, level := range levels {
+		if isWildCard(level) {
+			break
+		}
+		prefix += F_SLASH + level
+	}
+	return prefix
+}
+
+// Checks whether topic matches topic pattern.
+func matchTopic(pattern string, topic string) bool {
+	patternLevels := strings.Split(strings.TrimPrefix(pattern, F_SLASH), F_SLASH)
+	topicLevels := strings.Split(strings.TrimPrefix(topic, F_SLASH), F_SLASH)
+	for i, level := range patternLevels {
+		if level == TOPIC_MULTI_WILD_CARD {
+			return true
+		}
+		if i >= len(topicLevels) {
+			return false
+		}
+		if level != TOPIC_WILD_CARD && level != topicLevels[i] {
+			return false
+		}
+	}
+	return len(patternLevels) == len(topicLevels)
+}
+
+func isWildCard(level string) bool {
+	return level == TOPIC_WILD_CARD || level == TOPIC_MULTI_WILD_CARD
+}
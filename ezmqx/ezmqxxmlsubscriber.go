@@ -19,9 +19,8 @@ package ezmqx
 
 import (
 	"container/list"
+	"context"
 	"go.uber.org/zap"
-	"go/aml"
-	"go/ezmq"
 )
 
 // Callback to get all the subscribed events for a specific topic.
@@ -32,29 +31,50 @@ type EZMQXXmlErrorCB func(topic string, errorCode EZMQXErrorCode)
 
 // Structure represents EZMQX XML subscriber.
 type EZMQXXMLSubscriber struct {
-	subscriber    *EZMQXSubscriber
-	subCallback   EZMQXXmlSubCB
-	errorCallback EZMQXXmlErrorCB
-	isSecured     bool
+	EZMQXTypedSubscriber[string]
 }
 
 // Get XML subscriber instance for given topic.
 // It will work, if EZMQX is configured in docker mode.
+// Topic can be a pattern with '*' and '#' wildcards, see HierarchicalQuery of topic discovery.
 func GetXMLSubscriber(topic string, isHierarchical bool, subCallback EZMQXXmlSubCB, errorCallback EZMQXXmlErrorCB) (*EZMQXXMLSubscriber, EZMQXErrorCode) {
-	instance := createXmlSubscriber(subCallback, errorCallback)
-	result := instance.subscriber.initialize(topic, isHierarchical)
-	if result != EZMQX_OK {
-		Logger.Error("initialization failed", zap.Int("Error code:", int(result)))
-		return nil, result
+	instance, err := GetXMLSubscriberWithContext(context.Background(), topic, isHierarchical, subCallback, errorCallback, nil)
+	return instance, GetErrorCode(err)
+}
+
+// Get XML subscriber instance for given topic with context.
+// TNS query request is canceled when ctx is done, deadline of ctx is applied to it.
+// On failure, returned error is *EZMQXError which holds error code along with failed operation and cause.
+// Flow control of config [SetFlowControl] is used if optionalFlowControl is nil.
+func GetXMLSubscriberWithContext(ctx context.Context, topic string, isHierarchical bool, subCallback EZMQXXmlSubCB, errorCallback EZMQXXmlErrorCB, optionalFlowControl *EZMQXFlowControl) (*EZMQXXMLSubscriber, error) {
+	return GetConfigInstance().GetXMLSubscriberWithContext(ctx, topic, isHierarchical, subCallback, errorCallback, optionalFlowControl)
+}
+
+// Get XML subscriber instance for given topic with context.
+// TNS query request is canceled when ctx is done, deadline of ctx is applied to it.
+// On failure, returned error is *EZMQXError which holds error code along with failed operation and cause.
+// Flow control of config [SetFlowControl] is used if optionalFlowControl is nil.
+func (configInstance *EZMQXConfig) GetXMLSubscriberWithContext(ctx context.Context, topic string, isHierarchical bool, subCallback EZMQXXmlSubCB, errorCallback EZMQXXmlErrorCB, optionalFlowControl *EZMQXFlowControl) (*EZMQXXMLSubscriber, error) {
+	instance := createXmlSubscriber(configInstance.context, optionalFlowControl, subCallback, errorCallback)
+	err := instance.subscriber.initialize(ctx, topic, isHierarchical)
+	if err != nil {
+		Logger.Error("initialization failed", zap.Error(err))
+		return nil, err
 	}
 	instance.isSecured = false
-	return instance, result
+	return instance, nil
 }
 
 // Get XML subscriber instance for given topic.
 // It will work, if EZMQX is configured in standalone mode.
 func GetXMLStandAloneSubscriber(topic EZMQXTopic, subCallback EZMQXXmlSubCB, errorCallback EZMQXXmlErrorCB) (*EZMQXXMLSubscriber, EZMQXErrorCode) {
-	instance := createXmlSubscriber(subCallback, errorCallback)
+	return GetConfigInstance().GetXMLStandAloneSubscriber(topic, subCallback, errorCallback)
+}
+
+// Get XML subscriber instance for given topic.
+// It will work, if EZMQX is configured in standalone mode.
+func (configInstance *EZMQXConfig) GetXMLStandAloneSubscriber(topic EZMQXTopic, subCallback EZMQXXmlSubCB, errorCallback EZMQXXmlErrorCB) (*EZMQXXMLSubscriber, EZMQXErrorCode) {
+	instance := createXmlSubscriber(configInstance.context, nil, subCallback, errorCallback)
 	ezmqxTopicList := list.New()
 	ezmqxTopicList.PushBack(topic)
 	result := instance.subscriber.storeTopics(*ezmqxTopicList)
@@ -69,7 +89,13 @@ func GetXMLStandAloneSubscriber(topic EZMQXTopic, subCallback EZMQXXmlSubCB, err
 // Get XML subscriber instance for given topic list.
 // It will work, if EZMQX is configured in standalone mode.
 func GetXMLStandAloneSubscriber1(topics list.List, subCallback EZMQXXmlSubCB, errorCallback EZMQXXmlErrorCB) (*EZMQXXMLSubscriber, EZMQXErrorCode) {
-	instance := createXmlSubscriber(subCallback, errorCallback)
+	return GetConfigInstance().GetXMLStandAloneSubscriber1(topics, subCallback, errorCallback)
+}
+
+// Get XML subscriber instance for given topic list.
+// It will work, if EZMQX is configured in standalone mode.
+func (configInstance *EZMQXConfig) GetXMLStandAloneSubscriber1(topics list.List, subCallback EZMQXXmlSubCB, errorCallback EZMQXXmlErrorCB) (*EZMQXXMLSubscriber, EZMQXErrorCode) {
+	instance := createXmlSubscriber(configInstance.context, nil, subCallback, errorCallback)
 	result := instance.subscriber.storeTopics(topics)
 	if result != EZMQX_OK {
 		Logger.Error("Store topic failed", zap.Int("Error code:", int(result)))
@@ -79,47 +105,51 @@ func GetXMLStandAloneSubscriber1(topics list.List, subCallback EZMQXXmlSubCB, er
 	return instance, result
 }
 
-// Terminate EZMQX XML subscriber.
-func (instance *EZMQXXMLSubscriber) Terminate() EZMQXErrorCode {
-	return instance.subscriber.terminate()
+// Get XML channel subscriber instance for given topic.
+// Messages and errors are received on returned channel instead of callbacks, channel is closed on terminate.
+// It will work, if EZMQX is configured in docker mode.
+//
+// bufferSize: Maximum number of messages buffered on channel.
+// policy: Drop the message or block the receiver when channel is full.
+func GetXMLChannelSubscriber(topic string, isHierarchical bool, bufferSize int, policy EZMQXOverflowPolicy) (*EZMQXChannelSubscriber[string], <-chan EZMQXMessage[string], EZMQXErrorCode) {
+	instance, messages, err := GetXMLChannelSubscriberWithContext(context.Background(), topic, isHierarchical, bufferSize, policy, nil)
+	return instance, messages, GetErrorCode(err)
+}
+
+// Get XML channel subscriber instance for given topic with context.
+// TNS query request is canceled when ctx is done, deadline of ctx is applied to it.
+// On failure, returned error is *EZMQXError which holds error code along with failed operation and cause.
+// Flow control of config [SetFlowControl] is used if optionalFlowControl is nil.
+func GetXMLChannelSubscriberWithContext(ctx context.Context, topic string, isHierarchical bool, bufferSize int, policy EZMQXOverflowPolicy, optionalFlowControl *EZMQXFlowControl) (*EZMQXChannelSubscriber[string], <-chan EZMQXMessage[string], error) {
+	return GetConfigInstance().GetXMLChannelSubscriberWithContext(ctx, topic, isHierarchical, bufferSize, policy, optionalFlowControl)
 }
 
-// Check whether subscriber is terminated or not.
-func (instance *EZMQXXMLSubscriber) IsTerminated() (bool, EZMQXErrorCode) {
-	return instance.subscriber.isTerminated(), EZMQX_OK
+// Get XML channel subscriber instance for given topic with context.
+// TNS query request is canceled when ctx is done, deadline of ctx is applied to it.
+// On failure, returned error is *EZMQXError which holds error code along with failed operation and cause.
+// Flow control of config [SetFlowControl] is used if optionalFlowControl is nil.
+func (configInstance *EZMQXConfig) GetXMLChannelSubscriberWithContext(ctx context.Context, topic string, isHierarchical bool, bufferSize int, policy EZMQXOverflowPolicy, optionalFlowControl *EZMQXFlowControl) (*EZMQXChannelSubscriber[string], <-chan EZMQXMessage[string], error) {
+	return GetChannelSubscriberWithConfig(ctx, configInstance, topic, isHierarchical, getXmlCodecResolver(configInstance.context), bufferSize, policy, optionalFlowControl)
 }
 
-// Get list of topics that subscribed by this subscriber.
-func (instance *EZMQXXMLSubscriber) GetTopics() (*list.List, EZMQXErrorCode) {
-	return instance.subscriber.getTopics(), EZMQX_OK
+// Get XML channel subscriber instance for given topic list.
+// Messages and errors are received on returned channel instead of callbacks, channel is closed on terminate.
+// It will work, if EZMQX is configured in standalone mode.
+func GetXMLStandAloneChannelSubscriber(topics list.List, bufferSize int, policy EZMQXOverflowPolicy) (*EZMQXChannelSubscriber[string], <-chan EZMQXMessage[string], EZMQXErrorCode) {
+	return GetConfigInstance().GetXMLStandAloneChannelSubscriber(topics, bufferSize, policy)
 }
 
-// Check whether subscriber is secured or not.
-func (instance *EZMQXXMLSubscriber) IsSecured() (bool, EZMQXErrorCode) {
-	return instance.isSecured, EZMQX_OK
+// Get XML channel subscriber instance for given topic list.
+// Messages and errors are received on returned channel instead of callbacks, channel is closed on terminate.
+// It will work, if EZMQX is configured in standalone mode.
+func (configInstance *EZMQXConfig) GetXMLStandAloneChannelSubscriber(topics list.List, bufferSize int, policy EZMQXOverflowPolicy) (*EZMQXChannelSubscriber[string], <-chan EZMQXMessage[string], EZMQXErrorCode) {
+	return GetStandAloneChannelSubscriberWithConfig(configInstance, topics, getXmlCodecResolver(configInstance.context), bufferSize, policy)
 }
 
-func createXmlSubscriber(subCallback EZMQXXmlSubCB, errorCallback EZMQXXmlErrorCB) *EZMQXXMLSubscriber {
+func createXmlSubscriber(context *EZMQXContext, flowControl *EZMQXFlowControl, subCallback EZMQXXmlSubCB, errorCallback EZMQXXmlErrorCB) *EZMQXXMLSubscriber {
 	var instance *EZMQXXMLSubscriber
 	instance = &EZMQXXMLSubscriber{}
-	instance.subCallback = subCallback
-	instance.errorCallback = errorCallback
-	instance.subscriber = getEZMQXSubscriber()
-	subscriber := instance.subscriber
-	subscriber.internalCB = func(topic string, ezmqMsg ezmq.EZMQMessage) {
-		representation := subscriber.amlRepDic[topic]
-		if 0 == len(topic) || nil == representation {
-			instance.errorCallback(topic, EZMQX_UNKNOWN_TOPIC)
-			return
-		}
-		ezmqByteData := ezmqMsg.(ezmq.EZMQByteData)
-		amlObject, result := representation.ByteToData(ezmqByteData.ByteData)
-		if result != aml.AML_OK {
-			instance.errorCallback(topic, EZMQX_BROKEN_PAYLOAD)
-			return
-		}
-		amlString, result := representation.DataToAml(amlObject)
-		instance.subCallback(topic, amlString)
-	}
+	instance.EZMQXTypedSubscriber = *createTypedSubscriber(context, flowControl, getXmlCodecResolver(context),
+		EZMQXTypedSubCB[string](subCallback), EZMQXSubErrorCB(errorCallback))
 	return instance
 }
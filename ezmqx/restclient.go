@@ -19,13 +19,17 @@ package ezmqx
 
 import (
 	"bytes"
+	"context"
+	"crypto/tls"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"time"
 )
 
 type RestClient struct {
-	client http.Client
+	client      http.Client
+	credentials EZMQXCredentialsProvider
 }
 
 func GetRestClient(timeout time.Duration) *RestClient {
@@ -38,67 +42,120 @@ func GetRestClient(timeout time.Duration) *RestClient {
 	return instance
 }
 
+// Get rest client which uses given TLS configuration for https requests.
+// Client has its own transport, so client should be reused to reuse connections.
+func GetTLSRestClient(timeout time.Duration, tlsConfig *tls.Config) *RestClient {
+	instance := GetRestClient(timeout)
+	instance.client.Transport = newTLSTransport(tlsConfig)
+	return instance
+}
+
+func newTLSTransport(tlsConfig *tls.Config) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	return transport
+}
+
+// Set credentials provider, which gives Authorization header of requests. nil removes it.
+func (instance *RestClient) SetCredentialsProvider(provider EZMQXCredentialsProvider) {
+	instance.credentials = provider
+}
+
 func (instance *RestClient) Get(url string) (*RestResponse, EZMQXErrorCode) {
-	response, err := instance.client.Get(url)
-	if err != nil {
-		Logger.Error("HTTP request failed")
-		return nil, EZMQX_REST_ERROR
-	}
-	data, err := ioutil.ReadAll(response.Body)
-	if err != nil {
-		Logger.Error("Failed to read response body")
-		return nil, EZMQX_REST_ERROR
-	}
-	res := GetRestResponse(response.StatusCode, data)
-	return res, EZMQX_OK
+	return instance.GetWithContext(context.Background(), url)
 }
 
 func (instance *RestClient) Put(url string, data []byte) (*RestResponse, EZMQXErrorCode) {
-	req, err := http.NewRequest("PUT", url, bytes.NewBuffer(data))
-	if err != nil {
-		Logger.Error("Form delete request failed")
-		return nil, EZMQX_REST_ERROR
-	}
-	response, err := instance.client.Do(req)
-	if err != nil {
-		Logger.Error("Delete request failed")
-		return nil, EZMQX_REST_ERROR
-	}
-	resData, error := ioutil.ReadAll(response.Body)
-	if error != nil {
-		Logger.Error("Failed to read response body")
-		return nil, EZMQX_REST_ERROR
-	}
-	res := GetRestResponse(response.StatusCode, resData)
-	return res, EZMQX_OK
+	return instance.PutWithContext(context.Background(), url, data)
 }
 
 func (instance *RestClient) Post(url string, data []byte) (*RestResponse, EZMQXErrorCode) {
-	response, err := instance.client.Post(url, APPLICATION_JSON, bytes.NewBuffer(data))
+	return instance.PostWithContext(context.Background(), url, data)
+}
+
+func (instance *RestClient) Delete(url string, data []byte) (*RestResponse, EZMQXErrorCode) {
+	return instance.DeleteWithContext(context.Background(), url, data)
+}
+
+func (instance *RestClient) GetWithContext(ctx context.Context, url string) (*RestResponse, EZMQXErrorCode) {
+	return instance.do(ctx, "GET", url, nil, true)
+}
+
+func (instance *RestClient) PutWithContext(ctx context.Context, url string, data []byte) (*RestResponse, EZMQXErrorCode) {
+	return instance.do(ctx, "PUT", url, data, true)
+}
+
+func (instance *RestClient) PostWithContext(ctx context.Context, url string, data []byte) (*RestResponse, EZMQXErrorCode) {
+	return instance.do(ctx, "POST", url, data, true)
+}
+
+func (instance *RestClient) DeleteWithContext(ctx context.Context, url string, data []byte) (*RestResponse, EZMQXErrorCode) {
+	return instance.do(ctx, "DELETE", url, nil, false)
+}
+
+func (instance *RestClient) do(ctx context.Context, method string, url string, data []byte, readBody bool) (*RestResponse, EZMQXErrorCode) {
+	response, err := instance.doRequest(ctx, method, url, data, readBody)
+	return response, GetErrorCode(err)
+}
+
+// Sends request and, if server responds HTTP_UNAUTHORIZED, sends it once again with refreshed credentials.
+func (instance *RestClient) doRequest(ctx context.Context, method string, url string, data []byte, readBody bool) (*RestResponse, error) {
+	op := method + " " + url
+	response, err := instance.send(ctx, method, url, data, false)
 	if err != nil {
-		Logger.Error("Post request failed")
-		return nil, EZMQX_REST_ERROR
+		return nil, err
+	}
+	if response.StatusCode == HTTP_UNAUTHORIZED && nil != instance.credentials {
+		response.Body.Close()
+		Logger.Debug("Unauthorized, retry with refreshed credentials")
+		response, err = instance.send(ctx, method, url, data, true)
+		if err != nil {
+			return nil, err
+		}
 	}
-	resData, error := ioutil.ReadAll(response.Body)
-	if error != nil {
+	defer response.Body.Close()
+	if !readBody {
+		return GetRestResponse(response.StatusCode, nil), nil
+	}
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
 		Logger.Error("Failed to read response body")
-		return nil, EZMQX_REST_ERROR
+		return nil, GetEZMQXError(EZMQX_REST_ERROR, op, err)
 	}
-	res := GetRestResponse(response.StatusCode, resData)
-	return res, EZMQX_OK
+	res := GetRestResponse(response.StatusCode, body)
+	return res, nil
 }
 
-func (instance *RestClient) Delete(url string, data []byte) (*RestResponse, EZMQXErrorCode) {
-	req, err := http.NewRequest("DELETE", url, nil)
+func (instance *RestClient) send(ctx context.Context, method string, url string, data []byte, refresh bool) (*http.Response, error) {
+	op := method + " " + url
+	var body io.Reader
+	if nil != data {
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, url, body)
 	if err != nil {
-		Logger.Error("Form delete request failed")
-		return nil, EZMQX_REST_ERROR
+		Logger.Error("Form request failed")
+		return nil, GetEZMQXError(EZMQX_REST_ERROR, op, err)
+	}
+	if "POST" == method {
+		req.Header.Set("Content-Type", APPLICATION_JSON)
 	}
-	response, err := instance.client.Do(req)
+	if nil != instance.credentials {
+		authorization, err := instance.credentials.GetAuthorization(ctx, refresh)
+		if err != nil {
+			Logger.Error("Get authorization failed")
+			return nil, GetEZMQXError(EZMQX_REST_ERROR, op, err)
+		}
+		req.Header.Set(AUTHORIZATION, authorization)
+	}
+	response, err := instance.client.Do(req.WithContext(ctx))
 	if err != nil {
-		Logger.Error("Delete request failed")
-		return nil, EZMQX_REST_ERROR
+		if nil != ctx.Err() {
+			Logger.Error("HTTP request canceled")
+			return nil, GetEZMQXError(EZMQX_CANCELED, op, ctx.Err())
+		}
+		Logger.Error("HTTP request failed")
+		return nil, GetEZMQXError(EZMQX_REST_ERROR, op, err)
 	}
-	res := GetRestResponse(response.StatusCode, nil)
-	return res, EZMQX_OK
+	return response, nil
 }
@@ -17,7 +17,10 @@
 
 package ezmqx
 
-import "time"
+import (
+	"crypto/tls"
+	"time"
+)
 
 type RestClientFactory struct {
 }
@@ -26,3 +29,8 @@ func (instance RestClientFactory) GetRestClient(timeout time.Duration) RestClien
 	client := GetRestClient(timeout)
 	return client
 }
+
+func (instance RestClientFactory) GetTLSRestClient(timeout time.Duration, tlsConfig *tls.Config) RestClientInterface {
+	client := GetTLSRestClient(timeout, tlsConfig)
+	return client
+}
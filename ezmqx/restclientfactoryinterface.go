@@ -17,8 +17,17 @@
 
 package ezmqx
 
-import "time"
+import (
+	"crypto/tls"
+	"time"
+)
 
 type RestClientFactoryInterface interface {
 	GetRestClient(timeout time.Duration) RestClientInterface
 }
+
+// Optional interface of rest client factory to support TLS configuration of REST endpoints.
+// If factory does not implement it, TLS config can not be set [SetTLSConfig returns EZMQX_INVALID_PARAM].
+type RestClientTLSFactoryInterface interface {
+	GetTLSRestClient(timeout time.Duration, tlsConfig *tls.Config) RestClientInterface
+}
@@ -17,9 +17,20 @@
 
 package ezmqx
 
+import "context"
+
 type RestClientInterface interface {
 	Get(url string) (*RestResponse, EZMQXErrorCode)
 	Put(url string, data []byte) (*RestResponse, EZMQXErrorCode)
 	Post(url string, data []byte) (*RestResponse, EZMQXErrorCode)
 	Delete(url string, data []byte) (*RestResponse, EZMQXErrorCode)
 }
+
+// Optional interface of rest client to support cancellation and deadline of requests.
+// If rest client does not implement it, context is checked only before the request.
+type RestClientContextInterface interface {
+	GetWithContext(ctx context.Context, url string) (*RestResponse, EZMQXErrorCode)
+	PutWithContext(ctx context.Context, url string, data []byte) (*RestResponse, EZMQXErrorCode)
+	PostWithContext(ctx context.Context, url string, data []byte) (*RestResponse, EZMQXErrorCode)
+	DeleteWithContext(ctx context.Context, url string, data []byte) (*RestResponse, EZMQXErrorCode)
+}
@@ -17,49 +17,327 @@
 
 package ezmqx
 
-import "time"
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
 
 var restFactoryInstance *RestFactory
 
 type RestFactory struct {
 	restInterface RestClientFactoryInterface
 	timeout       time.Duration
+	tlsConfigs    map[EZMQXRestEndpoint]*tls.Config
+	transports    map[EZMQXRestEndpoint]*http.Transport
+	tlsMutex      *sync.RWMutex
+	credentials   atomic.Value
+	resilience    *restResilience
 }
 
 func GetRestFactory() *RestFactory {
 	if nil == restFactoryInstance {
-		restFactoryInstance = &RestFactory{}
-		restFactoryInstance.restInterface = RestClientFactory{}
-		restFactoryInstance.timeout = time.Duration(CONNECTION_TIMEOUT * time.Second)
+		restFactoryInstance = createRestFactory()
 	}
 	return restFactoryInstance
 }
 
+func createRestFactory() *RestFactory {
+	var instance *RestFactory
+	instance = &RestFactory{}
+	instance.restInterface = RestClientFactory{}
+	instance.timeout = time.Duration(CONNECTION_TIMEOUT * time.Second)
+	instance.tlsConfigs = make(map[EZMQXRestEndpoint]*tls.Config)
+	instance.transports = make(map[EZMQXRestEndpoint]*http.Transport)
+	instance.tlsMutex = &sync.RWMutex{}
+	instance.resilience = getRestResilience()
+	return instance
+}
+
 func (instance *RestFactory) SetFactory(factory RestClientFactoryInterface) {
 	instance.restInterface = factory
 }
 
+// Set credentials provider, which gives Authorization header of every request. nil removes it.
+// It is set on rest clients of ezmqx [RestClient], custom rest client should attach Authorization by itself.
+func (instance *RestFactory) SetCredentialsProvider(provider EZMQXCredentialsProvider) {
+	instance.credentials.Store(credentialsHolder{provider})
+}
+
+// Holder is stored, as atomic value does not accept nil.
+type credentialsHolder struct {
+	provider EZMQXCredentialsProvider
+}
+
+func (instance *RestFactory) getCredentialsProvider() EZMQXCredentialsProvider {
+	holder, ok := instance.credentials.Load().(credentialsHolder)
+	if !ok {
+		return nil
+	}
+	return holder.provider
+}
+
+// Sets credentials provider of factory on rest client of ezmqx.
+func (instance *RestFactory) withCredentials(restClient RestClientInterface) RestClientInterface {
+	if client, ok := restClient.(*RestClient); ok {
+		client.SetCredentialsProvider(instance.getCredentialsProvider())
+	}
+	return restClient
+}
+
 func (instance *RestFactory) Get(url string) (*RestResponse, EZMQXErrorCode) {
-	restClient := instance.restInterface.GetRestClient(instance.timeout)
-	return restClient.Get(url)
+	return instance.GetWithContext(context.Background(), url)
 }
 
 func (instance *RestFactory) Put(url string, data []byte) (*RestResponse, EZMQXErrorCode) {
-	restClient := instance.restInterface.GetRestClient(instance.timeout)
-	return restClient.Put(url, data)
+	return instance.PutWithContext(context.Background(), url, data)
 }
 
 func (instance *RestFactory) Post(url string, data []byte) (*RestResponse, EZMQXErrorCode) {
-	restClient := instance.restInterface.GetRestClient(instance.timeout)
-	return restClient.Post(url, data)
+	return instance.PostWithContext(context.Background(), url, data)
 }
 
 func (instance *RestFactory) Post1(url string, data []byte, timeout time.Duration) (*RestResponse, EZMQXErrorCode) {
-	restClient := instance.restInterface.GetRestClient(timeout)
-	return restClient.Post(url, data)
+	return instance.post(context.Background(), url, data, timeout)
 }
 
 func (instance *RestFactory) Delete(url string, data []byte) (*RestResponse, EZMQXErrorCode) {
-	restClient := instance.restInterface.GetRestClient(instance.timeout)
-	return restClient.Delete(url, data)
+	return instance.DeleteWithContext(context.Background(), url, data)
+}
+
+// Request is canceled when ctx is done. Deadline of ctx is applied along with connection timeout.
+// Retry policy of factory is applied, TLS configuration of REST endpoints [SetTLSConfig] is not:
+// https URLs are requested with system TLS configuration.
+func (instance *RestFactory) GetWithContext(ctx context.Context, url string) (*RestResponse, EZMQXErrorCode) {
+	response, err := instance.execute(ctx, restEndpointAny, "GET", url, func() (*RestResponse, error) {
+		restClient := instance.getRestClient(restEndpointAny)
+		if contextClient, ok := restClient.(RestClientContextInterface); ok {
+			response, result := contextClient.GetWithContext(ctx, url)
+			return response, toError(result, "GET "+url)
+		}
+		response, result := withContext(ctx, func() (*RestResponse, EZMQXErrorCode) {
+			return restClient.Get(url)
+		})
+		return response, toError(result, "GET "+url)
+	})
+	return response, GetErrorCode(err)
+}
+
+func (instance *RestFactory) PutWithContext(ctx context.Context, url string, data []byte) (*RestResponse, EZMQXErrorCode) {
+	response, err := instance.execute(ctx, restEndpointAny, "PUT", url, func() (*RestResponse, error) {
+		restClient := instance.getRestClient(restEndpointAny)
+		if contextClient, ok := restClient.(RestClientContextInterface); ok {
+			response, result := contextClient.PutWithContext(ctx, url, data)
+			return response, toError(result, "PUT "+url)
+		}
+		response, result := withContext(ctx, func() (*RestResponse, EZMQXErrorCode) {
+			return restClient.Put(url, data)
+		})
+		return response, toError(result, "PUT "+url)
+	})
+	return response, GetErrorCode(err)
+}
+
+func (instance *RestFactory) PostWithContext(ctx context.Context, url string, data []byte) (*RestResponse, EZMQXErrorCode) {
+	return instance.post(ctx, url, data, instance.timeout)
+}
+
+func (instance *RestFactory) post(ctx context.Context, url string, data []byte, timeout time.Duration) (*RestResponse, EZMQXErrorCode) {
+	response, err := instance.execute(ctx, restEndpointAny, "POST", url, func() (*RestResponse, error) {
+		restClient := instance.getRestClientWithTimeout(restEndpointAny, timeout)
+		if contextClient, ok := restClient.(RestClientContextInterface); ok {
+			response, result := contextClient.PostWithContext(ctx, url, data)
+			return response, toError(result, "POST "+url)
+		}
+		response, result := withContext(ctx, func() (*RestResponse, EZMQXErrorCode) {
+			return restClient.Post(url, data)
+		})
+		return response, toError(result, "POST "+url)
+	})
+	return response, GetErrorCode(err)
+}
+
+func (instance *RestFactory) DeleteWithContext(ctx context.Context, url string, data []byte) (*RestResponse, EZMQXErrorCode) {
+	response, err := instance.execute(ctx, restEndpointAny, "DELETE", url, func() (*RestResponse, error) {
+		restClient := instance.getRestClient(restEndpointAny)
+		if contextClient, ok := restClient.(RestClientContextInterface); ok {
+			response, result := contextClient.DeleteWithContext(ctx, url, data)
+			return response, toError(result, "DELETE "+url)
+		}
+		response, result := withContext(ctx, func() (*RestResponse, EZMQXErrorCode) {
+			return restClient.Delete(url, data)
+		})
+		return response, toError(result, "DELETE "+url)
+	})
+	return response, GetErrorCode(err)
+}
+
+func (instance *RestFactory) setTLSConfig(endpoint EZMQXRestEndpoint, tlsConfig *tls.Config) {
+	instance.tlsMutex.Lock()
+	defer instance.tlsMutex.Unlock()
+	// transport of previous configuration is not used anymore
+	transport := instance.transports[endpoint]
+	if nil != transport {
+		transport.CloseIdleConnections()
+		delete(instance.transports, endpoint)
+	}
+	if nil == tlsConfig {
+		delete(instance.tlsConfigs, endpoint)
+		return
+	}
+	instance.tlsConfigs[endpoint] = tlsConfig
+}
+
+func (instance *RestFactory) hasTLSConfig() bool {
+	instance.tlsMutex.RLock()
+	defer instance.tlsMutex.RUnlock()
+	return len(instance.tlsConfigs) > 0
+}
+
+// Returns true if rest clients of factory can be configured with TLS.
+func supportsTLS(factory RestClientFactoryInterface) bool {
+	if _, ok := factory.(RestClientFactory); ok {
+		return true
+	}
+	_, ok := factory.(RestClientTLSFactoryInterface)
+	return ok
+}
+
+func (instance *RestFactory) getTLSConfig(endpoint EZMQXRestEndpoint) *tls.Config {
+	instance.tlsMutex.RLock()
+	defer instance.tlsMutex.RUnlock()
+	return instance.tlsConfigs[endpoint]
+}
+
+// Returns scheme of given endpoint, https if TLS is set for it.
+func (instance *RestFactory) getScheme(endpoint EZMQXRestEndpoint) string {
+	if nil != instance.getTLSConfig(endpoint) {
+		return HTTPS_PREFIX
+	}
+	return HTTP_PREFIX
+}
+
+// Changes http URL to https, if TLS is set for given endpoint.
+func (instance *RestFactory) applyScheme(endpoint EZMQXRestEndpoint, url string) string {
+	if strings.HasPrefix(url, HTTP_PREFIX) {
+		return instance.getScheme(endpoint) + strings.TrimPrefix(url, HTTP_PREFIX)
+	}
+	return url
+}
+
+// Returns transport of factory for TLS configuration of given endpoint, nil if TLS is not set.
+// Transport is kept, so that connections are reused across rest clients of the endpoint.
+func (instance *RestFactory) getTLSTransport(endpoint EZMQXRestEndpoint) *http.Transport {
+	instance.tlsMutex.Lock()
+	defer instance.tlsMutex.Unlock()
+	tlsConfig := instance.tlsConfigs[endpoint]
+	if nil == tlsConfig {
+		return nil
+	}
+	transport := instance.transports[endpoint]
+	if nil == transport {
+		transport = newTLSTransport(tlsConfig)
+		instance.transports[endpoint] = transport
+	}
+	return transport
+}
+
+// Closes idle connections of TLS transports of factory.
+func (instance *RestFactory) closeIdleConnections() {
+	instance.tlsMutex.RLock()
+	defer instance.tlsMutex.RUnlock()
+	for _, transport := range instance.transports {
+		transport.CloseIdleConnections()
+	}
+}
+
+// Returns rest client for given endpoint. If TLS is set for the endpoint and factory supports TLS,
+// client uses TLS configuration of the endpoint.
+func (instance *RestFactory) getRestClient(endpoint EZMQXRestEndpoint) RestClientInterface {
+	return instance.getRestClientWithTimeout(endpoint, instance.timeout)
+}
+
+func (instance *RestFactory) getRestClientWithTimeout(endpoint EZMQXRestEndpoint, timeout time.Duration) RestClientInterface {
+	if _, ok := instance.restInterface.(RestClientFactory); ok {
+		// rest client of ezmqx uses transport of this factory
+		transport := instance.getTLSTransport(endpoint)
+		if nil != transport {
+			client := GetRestClient(timeout)
+			client.client.Transport = transport
+			return instance.withCredentials(client)
+		}
+		return instance.withCredentials(instance.restInterface.GetRestClient(timeout))
+	}
+	tlsConfig := instance.getTLSConfig(endpoint)
+	if nil != tlsConfig {
+		if factory, ok := instance.restInterface.(RestClientTLSFactoryInterface); ok {
+			return instance.withCredentials(factory.GetTLSRestClient(timeout, tlsConfig))
+		}
+		// EZMQXConfig rejects it, factory is set directly on RestFactory
+		Logger.Error("Rest client factory does not support TLS, TLS config is not applied")
+	}
+	return instance.withCredentials(instance.restInterface.GetRestClient(timeout))
+}
+
+// Same as GetWithContext, but returns underlying cause of failure along with error code.
+func (instance *RestFactory) getWithError(ctx context.Context, endpoint EZMQXRestEndpoint, url string) (*RestResponse, error) {
+	return instance.execute(ctx, endpoint, "GET", url, func() (*RestResponse, error) {
+		restClient := instance.getRestClient(endpoint)
+		if client, ok := restClient.(*RestClient); ok {
+			return client.doRequest(ctx, "GET", url, nil, true)
+		}
+		response, result := withContext(ctx, func() (*RestResponse, EZMQXErrorCode) {
+			if contextClient, ok := restClient.(RestClientContextInterface); ok {
+				return contextClient.GetWithContext(ctx, url)
+			}
+			return restClient.Get(url)
+		})
+		return response, toError(result, "GET "+url)
+	})
+}
+
+// Same as PostWithContext, but returns underlying cause of failure along with error code.
+func (instance *RestFactory) postWithError(ctx context.Context, endpoint EZMQXRestEndpoint, url string, data []byte) (*RestResponse, error) {
+	return instance.execute(ctx, endpoint, "POST", url, func() (*RestResponse, error) {
+		restClient := instance.getRestClient(endpoint)
+		if client, ok := restClient.(*RestClient); ok {
+			return client.doRequest(ctx, "POST", url, data, true)
+		}
+		response, result := withContext(ctx, func() (*RestResponse, EZMQXErrorCode) {
+			if contextClient, ok := restClient.(RestClientContextInterface); ok {
+				return contextClient.PostWithContext(ctx, url, data)
+			}
+			return restClient.Post(url, data)
+		})
+		return response, toError(result, "POST "+url)
+	})
+}
+
+// Same as DeleteWithContext, but returns underlying cause of failure along with error code.
+func (instance *RestFactory) deleteWithError(ctx context.Context, endpoint EZMQXRestEndpoint, url string) (*RestResponse, error) {
+	return instance.execute(ctx, endpoint, "DELETE", url, func() (*RestResponse, error) {
+		restClient := instance.getRestClient(endpoint)
+		if client, ok := restClient.(*RestClient); ok {
+			return client.doRequest(ctx, "DELETE", url, nil, true)
+		}
+		response, result := withContext(ctx, func() (*RestResponse, EZMQXErrorCode) {
+			if contextClient, ok := restClient.(RestClientContextInterface); ok {
+				return contextClient.DeleteWithContext(ctx, url, nil)
+			}
+			return restClient.Delete(url, nil)
+		})
+		return response, toError(result, "DELETE "+url)
+	})
+}
+
+// Calls request, if ctx is not done.
+func withContext(ctx context.Context, request func() (*RestResponse, EZMQXErrorCode)) (*RestResponse, EZMQXErrorCode) {
+	if nil != ctx.Err() {
+		return nil, EZMQX_CANCELED
+	}
+	return request()
 }
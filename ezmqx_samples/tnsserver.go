@@ -0,0 +1,79 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package main
+
+import (
+	"go/ezmqx_tns"
+
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+const TNS_DEFAULT_PORT = "48323"
+
+func printError() {
+	fmt.Printf("\nRe-run the application as shown in below examples: \n")
+	fmt.Printf("\n  (1) For running on default port [48323]: ")
+	fmt.Printf("\n      ./tnsserver\n")
+	fmt.Printf("\n  (2) For running on given port with keep alive interval in seconds: ")
+	fmt.Printf("\n      ./tnsserver -port 48323 -ka 10\n")
+	os.Exit(-1)
+}
+
+func main() {
+	port := TNS_DEFAULT_PORT
+	keepAliveInterval := ezmqx_tns.DEFAULT_KEEP_ALIVE_INTERVAL
+
+	// get port/keep alive interval from command line arguments
+	if len(os.Args)%2 != 1 {
+		printError()
+	}
+	for n := 1; n < len(os.Args); n = n + 2 {
+		if 0 == strings.Compare(os.Args[n], "-port") {
+			port = os.Args[n+1]
+		} else if 0 == strings.Compare(os.Args[n], "-ka") {
+			interval, err := strconv.Atoi(os.Args[n+1])
+			if err != nil || interval < 1 {
+				printError()
+			}
+			keepAliveInterval = interval
+		} else {
+			printError()
+		}
+	}
+
+	server := ezmqx_tns.GetTNSServer(":"+port, keepAliveInterval)
+	err := server.Start()
+	if err != nil {
+		fmt.Println("Start TNS server failed: ", err)
+		os.Exit(-1)
+	}
+	fmt.Println("TNS server started on: ", server.GetAddress())
+	fmt.Println("Keep alive interval: ", keepAliveInterval)
+
+	// Wait until ctrl+c
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	<-signals
+	server.Stop()
+	fmt.Println("TNS server stopped")
+}
@@ -0,0 +1,273 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+// Package ezmqx_tns provides TNS [Topic name server] server, which can be embedded in application
+// or run as a binary. It serves the same REST API used by ezmqx publishers, subscribers and topic discovery.
+package ezmqx_tns
+
+import (
+	"encoding/json"
+	"errors"
+	"go/ezmqx"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// URLs, shared with ezmqx TNS client
+const PREFIX = ezmqx.PREFIX
+const TOPIC = ezmqx.TOPIC
+const TNS_KEEP_ALIVE = ezmqx.TNS_KEEP_ALIVE
+const HTTP_PREFIX = ezmqx.HTTP_PREFIX
+const QUERY_TRUE = ezmqx.QUERY_TRUE
+
+// Query parameter keys
+const QUERY_NAME = "name"
+const QUERY_HIERARCHICAL = "hierarchical"
+
+// JSON Keys, shared with ezmqx TNS client
+const PAYLOAD_TOPIC = ezmqx.PAYLOAD_TOPIC
+const PAYLOAD_TOPICS = ezmqx.PAYLOAD_TOPICS
+const PAYLOAD_NAME = ezmqx.PAYLOAD_NAME
+const PAYLOAD_ENDPOINT = ezmqx.PAYLOAD_ENDPOINT
+const PAYLOAD_DATAMODEL = ezmqx.PAYLOAD_DATAMODEL
+const PAYLOAD_SECURED = ezmqx.PAYLOAD_SECURED
+const PAYLOAD_KEEPALIVE_INTERVAL = ezmqx.PAYLOAD_KEEPALIVE_INTERVAL
+const PAYLOAD_TOPIC_KA = ezmqx.PAYLOAD_TOPIC_KA
+const PAYLOAD_ERROR = "error"
+
+// Default keep alive interval in seconds, sent to publishers on topic registration.
+const DEFAULT_KEEP_ALIVE_INTERVAL = 10
+
+// Topic expires, if it is not kept alive for keep alive interval times this factor.
+const KEEP_ALIVE_TIMEOUT_FACTOR = 3
+
+const SLASH = ezmqx.SLASH
+const APPLICATION_JSON = ezmqx.APPLICATION_JSON
+
+// Structure represents TNS server.
+// It implements http.Handler, so it can be served by application's own http server as well.
+type TNSServer struct {
+	address           string
+	keepAliveInterval int
+	topics            *tnsTopicStore
+	listener          net.Listener
+	server            *http.Server
+	mutex             sync.Mutex
+}
+
+// Get TNS server instance for given address [e.g. ":48323"].
+// Publishers are asked to send keep alive on given interval in seconds, if interval is less than 1,
+// DEFAULT_KEEP_ALIVE_INTERVAL is used. Topics expire, if they are not kept alive for
+// KEEP_ALIVE_TIMEOUT_FACTOR times the interval.
+func GetTNSServer(address string, keepAliveInterval int) *TNSServer {
+	if keepAliveInterval < 1 {
+		keepAliveInterval = DEFAULT_KEEP_ALIVE_INTERVAL
+	}
+	var instance *TNSServer
+	instance = &TNSServer{}
+	instance.address = address
+	instance.keepAliveInterval = keepAliveInterval
+	timeout := time.Duration(keepAliveInterval*KEEP_ALIVE_TIMEOUT_FACTOR) * time.Second
+	instance.topics = getTopicStore(timeout)
+	return instance
+}
+
+// Set time after which topic expires, if it is not kept alive.
+// Topics never expire, if timeout is zero.
+func (instance *TNSServer) SetKeepAliveTimeout(timeout time.Duration) {
+	instance.topics.setTimeout(timeout)
+}
+
+// Start listening on address of TNS server and serve requests in background.
+func (instance *TNSServer) Start() error {
+	instance.mutex.Lock()
+	defer instance.mutex.Unlock()
+	if nil != instance.server {
+		return errors.New("TNS server already started")
+	}
+	listener, err := net.Listen("tcp", instance.address)
+	if err != nil {
+		return err
+	}
+	instance.listener = listener
+	instance.server = &http.Server{Handler: instance}
+	go instance.server.Serve(listener)
+	return nil
+}
+
+// Stop TNS server. Registered topics are kept, so TNS server can be started again.
+func (instance *TNSServer) Stop() error {
+	instance.mutex.Lock()
+	defer instance.mutex.Unlock()
+	if nil == instance.server {
+		return errors.New("TNS server not started")
+	}
+	err := instance.server.Close()
+	instance.server = nil
+	instance.listener = nil
+	return err
+}
+
+// Get address on which TNS server is listening, configured address if it is not started.
+func (instance *TNSServer) GetAddress() string {
+	instance.mutex.Lock()
+	defer instance.mutex.Unlock()
+	if nil != instance.listener {
+		return instance.listener.Addr().String()
+	}
+	return instance.address
+}
+
+// Get TNS address to be given to ezmqx in stand alone mode [e.g. "http://127.0.0.1:48323"].
+func (instance *TNSServer) GetTNSAddress() string {
+	return HTTP_PREFIX + instance.GetAddress()
+}
+
+// Get number of registered topics.
+func (instance *TNSServer) GetTopicCount() int {
+	return instance.topics.size()
+}
+
+// ServeHTTP serves TNS REST API.
+func (instance *TNSServer) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	switch request.URL.Path {
+	case PREFIX + TOPIC:
+		switch request.Method {
+		case http.MethodPost:
+			instance.registerTopic(writer, request)
+		case http.MethodGet:
+			instance.queryTopic(writer, request)
+		case http.MethodDelete:
+			instance.unregisterTopic(writer, request)
+		default:
+			writeError(writer, http.StatusMethodNotAllowed, "method not allowed "+request.Method)
+		}
+	case PREFIX + TNS_KEEP_ALIVE:
+		if request.Method != http.MethodPost {
+			writeError(writer, http.StatusMethodNotAllowed, "method not allowed "+request.Method)
+			return
+		}
+		instance.keepAlive(writer, request)
+	default:
+		writeError(writer, http.StatusNotFound, "unknown path "+request.URL.Path)
+	}
+}
+
+// Registers topic, payload is {"topic": {"name", "endpoint", "datamodel", "secured", ...}}.
+// Responds HTTP_CREATED with keep alive interval, or HTTP_CONFLICT if topic is already registered.
+func (instance *TNSServer) registerTopic(writer http.ResponseWriter, request *http.Request) {
+	payload := make(map[string]map[string]interface{})
+	err := json.NewDecoder(request.Body).Decode(&payload)
+	if err != nil {
+		writeError(writer, http.StatusBadRequest, "invalid payload: "+err.Error())
+		return
+	}
+	fields, exists := payload[PAYLOAD_TOPIC]
+	if !exists {
+		writeError(writer, http.StatusBadRequest, PAYLOAD_TOPIC+" key missing")
+		return
+	}
+	for _, key := range []string{PAYLOAD_NAME, PAYLOAD_ENDPOINT, PAYLOAD_DATAMODEL} {
+		if _, isString := fields[key].(string); !isString {
+			writeError(writer, http.StatusBadRequest, key+" key missing")
+			return
+		}
+	}
+	if _, isBool := fields[PAYLOAD_SECURED].(bool); !isBool {
+		writeError(writer, http.StatusBadRequest, PAYLOAD_SECURED+" key missing")
+		return
+	}
+	name := fields[PAYLOAD_NAME].(string)
+	err = validateTopicName(name)
+	if err != nil {
+		writeError(writer, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !instance.topics.add(name, fields) {
+		writeError(writer, http.StatusConflict, "topic already registered "+name)
+		return
+	}
+	writeJson(writer, http.StatusCreated, map[string]interface{}{PAYLOAD_KEEPALIVE_INTERVAL: instance.keepAliveInterval})
+}
+
+// Queries topic by "name" and "hierarchical" query parameters.
+// Responds HTTP_NOT_FOUND, if no topic is found.
+func (instance *TNSServer) queryTopic(writer http.ResponseWriter, request *http.Request) {
+	query := request.URL.Query()
+	name := query.Get(QUERY_NAME)
+	err := validateTopicName(name)
+	if err != nil {
+		writeError(writer, http.StatusBadRequest, err.Error())
+		return
+	}
+	topics := instance.topics.query(name, QUERY_TRUE == query.Get(QUERY_HIERARCHICAL))
+	if 0 == len(topics) {
+		writeError(writer, http.StatusNotFound, "topic not found "+name)
+		return
+	}
+	writeJson(writer, http.StatusOK, map[string]interface{}{PAYLOAD_TOPICS: topics})
+}
+
+// Unregisters topic by "name" query parameter.
+func (instance *TNSServer) unregisterTopic(writer http.ResponseWriter, request *http.Request) {
+	name := request.URL.Query().Get(QUERY_NAME)
+	if !instance.topics.remove(name) {
+		writeError(writer, http.StatusNotFound, "topic not found "+name)
+		return
+	}
+	writer.WriteHeader(http.StatusOK)
+}
+
+// Keeps topics alive, payload is {"topic_names": [...]}.
+// Responds HTTP_NOT_FOUND, if any of the topics is not registered, so that publisher registers it again.
+func (instance *TNSServer) keepAlive(writer http.ResponseWriter, request *http.Request) {
+	payload := make(map[string][]string)
+	err := json.NewDecoder(request.Body).Decode(&payload)
+	if err != nil {
+		writeError(writer, http.StatusBadRequest, "invalid payload: "+err.Error())
+		return
+	}
+	names, exists := payload[PAYLOAD_TOPIC_KA]
+	if !exists {
+		writeError(writer, http.StatusBadRequest, PAYLOAD_TOPIC_KA+" key missing")
+		return
+	}
+	if !instance.topics.keepAlive(names) {
+		writeError(writer, http.StatusNotFound, "one or more topics not registered")
+		return
+	}
+	writer.WriteHeader(http.StatusOK)
+}
+
+func writeJson(writer http.ResponseWriter, statusCode int, data interface{}) {
+	jsonValue, err := json.Marshal(data)
+	if err != nil {
+		writer.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	writer.Header().Set("Content-Type", APPLICATION_JSON)
+	writer.Header().Set("Content-Length", strconv.Itoa(len(jsonValue)))
+	writer.WriteHeader(statusCode)
+	writer.Write(jsonValue)
+}
+
+func writeError(writer http.ResponseWriter, statusCode int, message string) {
+	writeJson(writer, statusCode, map[string]string{PAYLOAD_ERROR: message})
+}
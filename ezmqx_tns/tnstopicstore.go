@@ -0,0 +1,150 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmqx_tns
+
+import (
+	"errors"
+	"go/ezmqx"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Structure represents topic registered on TNS.
+// Fields are kept as received, so that query response has the same JSON shape as register payload.
+type tnsTopic struct {
+	fields   map[string]interface{}
+	lastSeen time.Time
+}
+
+// Structure represents topics registered on TNS and their keep alive state.
+type tnsTopicStore struct {
+	topics  map[string]*tnsTopic
+	timeout time.Duration
+	mutex   sync.Mutex
+}
+
+func getTopicStore(timeout time.Duration) *tnsTopicStore {
+	var instance *tnsTopicStore
+	instance = &tnsTopicStore{}
+	instance.topics = make(map[string]*tnsTopic)
+	instance.timeout = timeout
+	return instance
+}
+
+func (instance *tnsTopicStore) setTimeout(timeout time.Duration) {
+	instance.mutex.Lock()
+	defer instance.mutex.Unlock()
+	instance.timeout = timeout
+}
+
+// Adds topic, returns false if topic with same name is already registered.
+func (instance *tnsTopicStore) add(name string, fields map[string]interface{}) bool {
+	instance.mutex.Lock()
+	defer instance.mutex.Unlock()
+	instance.removeExpired()
+	if nil != instance.topics[name] {
+		return false
+	}
+	instance.topics[name] = &tnsTopic{fields: fields, lastSeen: time.Now()}
+	return true
+}
+
+// Removes topic, returns false if topic is not registered.
+func (instance *tnsTopicStore) remove(name string) bool {
+	instance.mutex.Lock()
+	defer instance.mutex.Unlock()
+	instance.removeExpired()
+	if nil == instance.topics[name] {
+		return false
+	}
+	delete(instance.topics, name)
+	return true
+}
+
+// Returns topic with given name or, if hierarchical, topics under given name, sorted by name.
+func (instance *tnsTopicStore) query(name string, isHierarchical bool) []map[string]interface{} {
+	instance.mutex.Lock()
+	defer instance.mutex.Unlock()
+	instance.removeExpired()
+	var names []string
+	for topicName := range instance.topics {
+		if topicName == name || (isHierarchical && strings.HasPrefix(topicName, name+SLASH)) {
+			names = append(names, topicName)
+		}
+	}
+	sort.Strings(names)
+	topics := make([]map[string]interface{}, 0, len(names))
+	for _, topicName := range names {
+		topics = append(topics, instance.topics[topicName].fields)
+	}
+	return topics
+}
+
+// Refreshes keep alive time of given topics, returns false if any of them is not registered.
+func (instance *tnsTopicStore) keepAlive(names []string) bool {
+	instance.mutex.Lock()
+	defer instance.mutex.Unlock()
+	instance.removeExpired()
+	isKnown := true
+	now := time.Now()
+	for _, name := range names {
+		topic := instance.topics[name]
+		if nil == topic {
+			isKnown = false
+			continue
+		}
+		topic.lastSeen = now
+	}
+	return isKnown
+}
+
+func (instance *tnsTopicStore) size() int {
+	instance.mutex.Lock()
+	defer instance.mutex.Unlock()
+	instance.removeExpired()
+	return len(instance.topics)
+}
+
+// Removes topics which are not kept alive within timeout. Caller should hold mutex.
+func (instance *tnsTopicStore) removeExpired() {
+	if instance.timeout <= 0 {
+		return
+	}
+	expiry := time.Now().Add(-instance.timeout)
+	for name, topic := range instance.topics {
+		if topic.lastSeen.Before(expiry) {
+			delete(instance.topics, name)
+		}
+	}
+}
+
+// Validates topic name with ezmqx topic name rules and returns error which explains why it is invalid.
+// Topic name should be given as ezmqx publishers register it, i.e. already normalized.
+func validateTopicName(name string) error {
+	topicName, err := ezmqx.ParseTopicName(name)
+	if err != nil {
+		return err
+	}
+	if topicName.GetName() != name {
+		return errors.New("topic name " + strconv.Quote(name) + " is not normalized")
+	}
+	return nil
+}
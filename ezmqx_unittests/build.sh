@@ -19,6 +19,7 @@
 cd ./../../../
 PROJECT_ROOT=$(pwd)
 export GOPATH=$(pwd)
+export GO111MODULE=off
 export CGO_CFLAGS=-I$PWD/dependencies/datamodel-aml-go/dependencies/datamodel-aml-c/include/
 export CGO_LDFLAGS=-L$PWD/src/go/ezmqx_extlibs
 export CGO_LDFLAGS=$CGO_LDFLAGS" -lcaml -laml"
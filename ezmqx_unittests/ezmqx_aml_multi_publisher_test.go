@@ -0,0 +1,121 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmqx_unittests
+
+import (
+	"go/ezmqx"
+	"go/ezmqx_unittests/utils"
+	"testing"
+)
+
+func TestGetAMLMultiPublisher(t *testing.T) {
+	configInstance := ezmqx.GetConfigInstance()
+	configInstance.StartStandAloneMode(utils.TEST_LOCAL_HOST, false, "")
+	publisher, _ := ezmqx.GetAMLMultiPublisher(utils.PORT)
+	if nil == publisher {
+		t.Errorf("publisher is nil")
+	}
+	result := publisher.AddTopic(utils.TOPIC, ezmqx.AML_FILE_PATH, utils.AML_FILE_PATH)
+	if result != ezmqx.EZMQX_OK {
+		t.Errorf("Add topic failed")
+	}
+	result = publisher.AddTopic("/topic2", ezmqx.AML_FILE_PATH, utils.AML_FILE_PATH)
+	if result != ezmqx.EZMQX_OK {
+		t.Errorf("Add topic failed")
+	}
+	topics, _ := publisher.GetTopics()
+	if topics.Len() != 2 {
+		t.Errorf("Topic count mismatch")
+	}
+	publisher.Terminate()
+	configInstance.Reset()
+}
+
+func TestAMLMultiPublisherNegative(t *testing.T) {
+	configInstance := ezmqx.GetConfigInstance()
+	configInstance.StartStandAloneMode(utils.TEST_LOCAL_HOST, false, "")
+	publisher, _ := ezmqx.GetAMLMultiPublisher(utils.PORT)
+	result := publisher.AddTopic("", ezmqx.AML_FILE_PATH, utils.AML_FILE_PATH)
+	if result != ezmqx.EZMQX_INVALID_TOPIC {
+		t.Errorf("Add topic failed")
+	}
+	result = publisher.AddTopic(utils.TOPIC, ezmqx.AML_FILE_PATH, "")
+	if result != ezmqx.EZMQX_INVALID_AML_MODEL {
+		t.Errorf("Add topic failed")
+	}
+	publisher.AddTopic(utils.TOPIC, ezmqx.AML_FILE_PATH, utils.AML_FILE_PATH)
+	result = publisher.AddTopic(utils.TOPIC, ezmqx.AML_FILE_PATH, utils.AML_FILE_PATH)
+	if result != ezmqx.EZMQX_DUPLICATED_TOPIC {
+		t.Errorf("Add topic failed")
+	}
+	result = publisher.Publish("/topic2", utils.GetAMLObject())
+	if result != ezmqx.EZMQX_UNKNOWN_TOPIC {
+		t.Errorf("Publish failed")
+	}
+	result = publisher.RemoveTopic("/topic2")
+	if result != ezmqx.EZMQX_UNKNOWN_TOPIC {
+		t.Errorf("Remove topic failed")
+	}
+	publisher.Terminate()
+	configInstance.Reset()
+}
+
+func TestAMLMultiPublish(t *testing.T) {
+	configInstance := ezmqx.GetConfigInstance()
+	configInstance.StartStandAloneMode(utils.TEST_LOCAL_HOST, false, "")
+	publisher, _ := ezmqx.GetAMLMultiPublisher(utils.PORT)
+	publisher.AddTopic(utils.TOPIC, ezmqx.AML_FILE_PATH, utils.AML_FILE_PATH)
+	publisher.AddTopic("/topic2", ezmqx.AML_FILE_PATH, utils.AML_FILE_PATH)
+	result := publisher.Publish(utils.TOPIC, utils.GetAMLObject())
+	if result != ezmqx.EZMQX_OK {
+		t.Errorf("publish failed")
+	}
+	result = publisher.Publish("/topic2", utils.GetAMLObject())
+	if result != ezmqx.EZMQX_OK {
+		t.Errorf("publish failed")
+	}
+	result = publisher.RemoveTopic("/topic2")
+	if result != ezmqx.EZMQX_OK {
+		t.Errorf("Remove topic failed")
+	}
+	result = publisher.Terminate()
+	if result != ezmqx.EZMQX_OK {
+		t.Errorf("Terminate failed")
+	}
+	isTerminated, _ := publisher.IsTerminated()
+	if !isTerminated {
+		t.Errorf("Terminate failed")
+	}
+	configInstance.Reset()
+}
+
+func TestAMLMultiPublisherWithTns(t *testing.T) {
+	configInstance := ezmqx.GetConfigInstance()
+	configInstance.StartStandAloneMode(utils.ADDRESS, true, utils.TNS_ADDRESS)
+
+	utils.Factory.SetFactory(utils.FakeRestClientFactory{})
+	utils.SetRestResponse(utils.PUB_TNS_URL, []byte(utils.VALID_PUB_TNS_RESPONSE))
+
+	publisher, _ := ezmqx.GetAMLMultiPublisher(utils.PORT)
+	result := publisher.AddTopic(utils.TOPIC, ezmqx.AML_FILE_PATH, utils.AML_FILE_PATH)
+	if result != ezmqx.EZMQX_OK {
+		t.Errorf("Add topic failed")
+	}
+	publisher.Terminate()
+	configInstance.Reset()
+}
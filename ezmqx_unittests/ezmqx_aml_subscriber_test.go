@@ -19,6 +19,7 @@ package ezmqx_unittests
 
 import (
 	"container/list"
+	"context"
 	"fmt"
 	"go/aml"
 	"go/ezmqx"
@@ -325,3 +326,172 @@ func TestGetTopics(t *testing.T) {
 	subscriber.Terminate()
 	configInstance.Reset()
 }
+
+func TestAMLChannelSubscriberStandAlone(t *testing.T) {
+	configInstance := ezmqx.GetConfigInstance()
+	configInstance.StartStandAloneMode(utils.TEST_LOCAL_HOST, false, "")
+	amlFilePath := list.New()
+	amlFilePath.PushBack(utils.AML_FILE_PATH)
+	idList, _ := configInstance.AddAmlModel(*amlFilePath)
+	endPoint := ezmqx.GetEZMQXEndPoint1(utils.TEST_LOCAL_HOST, utils.PORT)
+	topic := ezmqx.GetEZMQXTopic(utils.TOPIC, idList.Front().Value.(string), false, endPoint)
+	topicList := list.New()
+	topicList.PushBack(*topic)
+	subscriber, messages, result := ezmqx.GetAMLStandAloneChannelSubscriber(*topicList, utils.QUEUE_SIZE, ezmqx.OVERFLOW_DROP)
+	if result != ezmqx.EZMQX_OK {
+		t.Errorf("Get subscriber failed")
+	}
+
+	// Routine to publish data on socket
+	go utils.Publish()
+
+	// Wait till publisher is stopped
+	<-utils.Exit_Chan
+
+	time.Sleep(1000 * time.Millisecond)
+	subscriber.Terminate()
+	count := 0
+	for message := range messages {
+		if message.GetErrorCode() == ezmqx.EZMQX_OK && nil != message.GetValue() {
+			count++
+		}
+	}
+	if count < 5 {
+		t.Errorf("Received less event")
+	}
+	configInstance.Reset()
+}
+
+func TestAMLChannelSubscriberNegative(t *testing.T) {
+	configInstance := ezmqx.GetConfigInstance()
+	configInstance.StartStandAloneMode(utils.TEST_LOCAL_HOST, false, "")
+	amlFilePath := list.New()
+	amlFilePath.PushBack(utils.AML_FILE_PATH)
+	idList, _ := configInstance.AddAmlModel(*amlFilePath)
+	endPoint := ezmqx.GetEZMQXEndPoint1(utils.ADDRESS, utils.PORT)
+	topic := ezmqx.GetEZMQXTopic(utils.TOPIC, idList.Front().Value.(string), false, endPoint)
+	topicList := list.New()
+	topicList.PushBack(*topic)
+	_, _, result := ezmqx.GetAMLStandAloneChannelSubscriber(*topicList, -1, ezmqx.OVERFLOW_DROP)
+	if result != ezmqx.EZMQX_INVALID_PARAM {
+		t.Errorf("Get subscriber failed")
+	}
+	_, _, result = ezmqx.GetAMLStandAloneChannelSubscriber(*topicList, utils.QUEUE_SIZE, 2)
+	if result != ezmqx.EZMQX_INVALID_PARAM {
+		t.Errorf("Get subscriber failed")
+	}
+	configInstance.Reset()
+}
+
+func TestAMLChannelSubscriberWithContext(t *testing.T) {
+	configInstance := ezmqx.GetConfigInstance()
+	configInstance.StartStandAloneMode(utils.ADDRESS, true, utils.TNS_ADDRESS)
+	utils.Factory.SetFactory(utils.FakeRestClientFactory{})
+	utils.SetRestResponse(utils.SUB_TOPIC_URL, []byte(utils.SUB_TOPIC_RESPONSE))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err := ezmqx.GetAMLChannelSubscriberWithContext(ctx, utils.TOPIC, false, utils.QUEUE_SIZE, ezmqx.OVERFLOW_DROP, nil)
+	if ezmqx.GetErrorCode(err) != ezmqx.EZMQX_CANCELED {
+		t.Errorf("Get subscriber is not canceled")
+	}
+	_, _, err = configInstance.GetAMLChannelSubscriberWithContext(context.Background(), utils.TOPIC, false, -1, ezmqx.OVERFLOW_DROP, nil)
+	if ezmqx.GetErrorCode(err) != ezmqx.EZMQX_INVALID_PARAM {
+		t.Errorf("Get subscriber with invalid buffer size: Error")
+	}
+	configInstance.Reset()
+}
+
+func TestAMLSubTopicRefresh(t *testing.T) {
+	configInstance := ezmqx.GetConfigInstance()
+	utils.Factory.SetFactory(utils.FakeRestClientFactory{})
+	utils.SetRestResponse(utils.CONFIG_URL, []byte(utils.VALID_CONFIG_RESPONSE))
+	utils.SetRestResponse(utils.TNS_INFO_URL, []byte(utils.VALID_TNS_INFO_RESPONSE))
+	utils.SetRestResponse(utils.RUNNING_APPS_URL, []byte(utils.VALID_RUNNING_APPS_RESPONSE))
+	utils.SetRestResponse(utils.RUNNING_APP_INFO_URL, []byte(utils.RUNNING_APP_INFO_RESPONSE))
+	configInstance.StartDockerMode(utils.TNS_CONFIG_FILE_PATH)
+	amlFilePath := list.New()
+	amlFilePath.PushBack(utils.AML_FILE_PATH)
+	configInstance.AddAmlModel(*amlFilePath)
+	utils.SetRestResponse(utils.SUB_TOPIC_H_URL, []byte(utils.SUB_TOPIC_RESPONSE))
+	subscriber, _ := ezmqx.GetAMLSubscriber(utils.TOPIC, true, amlSubCB, errorCB)
+	if nil == subscriber {
+		t.Errorf("subscriber is nil")
+		configInstance.Reset()
+		return
+	}
+	changes := make(chan ezmqx.EZMQXTopicChange, 1)
+	result := subscriber.EnableTopicRefresh(utils.REFRESH_INTERVAL, func(topic ezmqx.EZMQXTopic, change ezmqx.EZMQXTopicChange) {
+		changes <- change
+	})
+	if result != ezmqx.EZMQX_OK {
+		t.Errorf("EnableTopicRefresh failed")
+	}
+	result = subscriber.EnableTopicRefresh(utils.REFRESH_INTERVAL, nil)
+	if result != ezmqx.EZMQX_INITIALIZED {
+		t.Errorf("EnableTopicRefresh [Already enabled]: Error")
+	}
+	utils.SetRestResponse(utils.SUB_TOPIC_H_URL, []byte(utils.SUB_TOPIC_MOVED_RESPONSE))
+	select {
+	case change := <-changes:
+		if change != ezmqx.TOPIC_ENDPOINT_CHANGED {
+			t.Errorf("Unexpected topic change")
+		}
+	case <-time.After(10 * utils.REFRESH_INTERVAL):
+		t.Errorf("Topic change is not notified")
+	}
+	topics, _ := subscriber.GetTopics()
+	topic := topics.Front().Value.(ezmqx.EZMQXTopic)
+	if topic.GetEndPoint().GetPort() != utils.SECOND_PORT {
+		t.Errorf("End point is not changed")
+	}
+	utils.SetRestResponse(utils.SUB_TOPIC_H_URL, []byte(utils.SUB_TOPIC_COMPRESSED_RESPONSE))
+	select {
+	case change := <-changes:
+		if change != ezmqx.TOPIC_CHANGED {
+			t.Errorf("Unexpected topic change [Compression]")
+		}
+	case <-time.After(10 * utils.REFRESH_INTERVAL):
+		t.Errorf("Compression change is not notified")
+	}
+	topics, _ = subscriber.GetTopics()
+	topic = topics.Front().Value.(ezmqx.EZMQXTopic)
+	if topic.GetCompression() != ezmqx.COMPRESSION_GZIP {
+		t.Errorf("Compression is not changed")
+	}
+	utils.SetRestResponse(utils.SUB_TOPIC_H_URL, []byte(utils.SUB_TOPIC_REMOVED_RESPONSE))
+	select {
+	case change := <-changes:
+		if change != ezmqx.TOPIC_REMOVED {
+			t.Errorf("Unexpected topic change [Removed]")
+		}
+	case <-time.After(10 * utils.REFRESH_INTERVAL):
+		t.Errorf("Topic removal is not notified")
+	}
+	topics, _ = subscriber.GetTopics()
+	if topics.Len() != 0 {
+		t.Errorf("Removed topic is not removed")
+	}
+	subscriber.Terminate()
+	configInstance.Reset()
+}
+
+func TestAMLSubTopicRefreshStandAlone(t *testing.T) {
+	configInstance := ezmqx.GetConfigInstance()
+	configInstance.StartStandAloneMode(utils.TEST_LOCAL_HOST, false, "")
+	amlFilePath := list.New()
+	amlFilePath.PushBack(utils.AML_FILE_PATH)
+	idList, _ := configInstance.AddAmlModel(*amlFilePath)
+	endPoint := ezmqx.GetEZMQXEndPoint1(utils.ADDRESS, utils.PORT)
+	topic := ezmqx.GetEZMQXTopic(utils.TOPIC, idList.Front().Value.(string), false, endPoint)
+	subscriber, _ := ezmqx.GetAMLStandAloneSubscriber(*topic, amlSubCB, errorCB)
+	result := subscriber.EnableTopicRefresh(utils.REFRESH_INTERVAL, nil)
+	if result != ezmqx.EZMQX_TNS_NOT_AVAILABLE {
+		t.Errorf("EnableTopicRefresh [Stand alone]: Error")
+	}
+	result = subscriber.EnableTopicRefresh(0, nil)
+	if result != ezmqx.EZMQX_INVALID_PARAM {
+		t.Errorf("EnableTopicRefresh [Invalid interval]: Error")
+	}
+	subscriber.Terminate()
+	configInstance.Reset()
+}
@@ -0,0 +1,140 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmqx_unittests
+
+import (
+	"go/ezmqx"
+	"go/ezmqx_unittests/utils"
+	"testing"
+	"time"
+)
+
+func TestGetBytePublisherStandAlone(t *testing.T) {
+	configInstance := ezmqx.GetConfigInstance()
+	configInstance.StartStandAloneMode(utils.TEST_LOCAL_HOST, false, "")
+	publisher, _ := ezmqx.GetBytePublisher(utils.TOPIC, utils.BYTE_DATA_MODEL, utils.PORT)
+	if nil == publisher {
+		t.Errorf("publisher is nil")
+	}
+	topic, _ := publisher.GetTopic()
+	if topic.GetDataModel() != utils.BYTE_DATA_MODEL {
+		t.Errorf("Data model mismatch")
+	}
+	publisher.Terminate()
+	configInstance.Reset()
+}
+
+func TestGetBytePublisherWithTns(t *testing.T) {
+	configInstance := ezmqx.GetConfigInstance()
+	configInstance.StartStandAloneMode(utils.ADDRESS, true, utils.TNS_ADDRESS)
+
+	utils.Factory.SetFactory(utils.FakeRestClientFactory{})
+	utils.SetRestResponse(utils.PUB_TNS_URL, []byte(utils.VALID_PUB_TNS_RESPONSE))
+
+	publisher, _ := ezmqx.GetBytePublisher(utils.TOPIC, utils.BYTE_DATA_MODEL, utils.PORT)
+	if nil == publisher {
+		t.Errorf("publisher is nil")
+	}
+	publisher.Terminate()
+	configInstance.Reset()
+}
+
+func TestGetBytePublisherNegative(t *testing.T) {
+	configInstance := ezmqx.GetConfigInstance()
+	configInstance.StartStandAloneMode(utils.TEST_LOCAL_HOST, false, "")
+	_, result := ezmqx.GetBytePublisher("", utils.BYTE_DATA_MODEL, utils.PORT)
+	if result != ezmqx.EZMQX_INVALID_TOPIC {
+		t.Errorf("Get publisher failed")
+	}
+	_, result = ezmqx.GetBytePublisher(utils.TOPIC, "", utils.PORT)
+	if result != ezmqx.EZMQX_INVALID_PARAM {
+		t.Errorf("Get publisher failed")
+	}
+	configInstance.Reset()
+	_, result = ezmqx.GetBytePublisher(utils.TOPIC, utils.BYTE_DATA_MODEL, utils.PORT)
+	if result != ezmqx.EZMQX_NOT_INITIALIZED {
+		t.Errorf("Get publisher failed")
+	}
+}
+
+func TestGetSecuredBytePublisher(t *testing.T) {
+	configInstance := ezmqx.GetConfigInstance()
+	configInstance.StartStandAloneMode(utils.TEST_LOCAL_HOST, false, "")
+	publisher, errorCode := ezmqx.GetSecuredBytePublisher(utils.TOPIC, utils.SERVER_SECRET_KEY, utils.BYTE_DATA_MODEL, utils.PORT)
+	if errorCode != ezmqx.EZMQX_OK {
+		t.Errorf("GetSecuredBytePublisher failed")
+	}
+	isSecured, _ := publisher.IsSecured()
+	if !isSecured {
+		t.Errorf("publisher is secured failed")
+	}
+	publisher.Terminate()
+	configInstance.Reset()
+}
+
+func TestStandAloneBytePublish(t *testing.T) {
+	configInstance := ezmqx.GetConfigInstance()
+	configInstance.StartStandAloneMode(utils.TEST_LOCAL_HOST, false, "")
+	publisher, _ := ezmqx.GetBytePublisher(utils.TOPIC, utils.BYTE_DATA_MODEL, utils.PORT)
+	result := publisher.Publish([]byte(utils.BYTE_DATA))
+	if result != ezmqx.EZMQX_OK {
+		t.Errorf("publish failed")
+	}
+	publisher.Terminate()
+	configInstance.Reset()
+	result = publisher.Publish([]byte(utils.BYTE_DATA))
+	if result != ezmqx.EZMQX_TERMINATED {
+		t.Errorf("publish failed")
+	}
+}
+
+func TestStandAloneBytePublishAsync(t *testing.T) {
+	configInstance := ezmqx.GetConfigInstance()
+	configInstance.StartStandAloneMode(utils.TEST_LOCAL_HOST, false, "")
+	publisher, _ := ezmqx.GetBytePublisher(utils.TOPIC, utils.BYTE_DATA_MODEL, utils.PORT)
+	results := make(chan ezmqx.EZMQXErrorCode, utils.NUMBER_OF_EVENTS)
+	callback := func(data []byte, errorCode ezmqx.EZMQXErrorCode) {
+		results <- errorCode
+	}
+	for i := 0; i < utils.NUMBER_OF_EVENTS; i++ {
+		result := publisher.PublishAsync([]byte(utils.BYTE_DATA), callback)
+		if result != ezmqx.EZMQX_OK {
+			t.Errorf("publish async failed")
+		}
+	}
+	for i := 0; i < utils.NUMBER_OF_EVENTS; i++ {
+		select {
+		case result := <-results:
+			if result != ezmqx.EZMQX_OK {
+				t.Errorf("async publish result is not OK")
+			}
+		case <-time.After(utils.LINGER):
+			t.Errorf("async publish callback is not called")
+		}
+	}
+	dropped, _ := publisher.GetDroppedCount()
+	if dropped != 0 {
+		t.Errorf("Publisher dropped messages")
+	}
+	publisher.Terminate()
+	result := publisher.PublishAsync([]byte(utils.BYTE_DATA), nil)
+	if result != ezmqx.EZMQX_TERMINATED {
+		t.Errorf("publish async after terminate failed")
+	}
+	configInstance.Reset()
+}
@@ -0,0 +1,312 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmqx_unittests
+
+import (
+	"container/list"
+	"context"
+	"go/ezmqx"
+	"go/ezmqx_unittests/utils"
+	"strings"
+	"testing"
+	"time"
+)
+
+var byteEventCount = 0
+var byteErrorCount = 0
+
+func byteSubCB(topic string, data []byte) {
+	if string(data) == utils.BYTE_DATA {
+		byteEventCount++
+	}
+}
+func byteErrorCB(topic string, errorCode ezmqx.EZMQXErrorCode) {
+	byteErrorCount++
+}
+
+func TestGetByteStandAloneSubscriber(t *testing.T) {
+	configInstance := ezmqx.GetConfigInstance()
+	configInstance.StartStandAloneMode(utils.TEST_LOCAL_HOST, false, "")
+	endPoint := ezmqx.GetEZMQXEndPoint1(utils.ADDRESS, utils.PORT)
+	topic := ezmqx.GetEZMQXTopic(utils.TOPIC, utils.BYTE_DATA_MODEL, false, endPoint)
+	subscriber, _ := ezmqx.GetByteStandAloneSubscriber(*topic, byteSubCB, byteErrorCB)
+	if nil == subscriber {
+		t.Errorf("subscriber is nil")
+	}
+	subscriber.Terminate()
+	configInstance.Reset()
+}
+
+func TestGetByteStandAloneSubscriberNegative(t *testing.T) {
+	configInstance := ezmqx.GetConfigInstance()
+	configInstance.StartStandAloneMode(utils.TEST_LOCAL_HOST, false, "")
+	endPoint := ezmqx.GetEZMQXEndPoint1(utils.ADDRESS, utils.PORT)
+	topic := ezmqx.GetEZMQXTopic("", utils.BYTE_DATA_MODEL, false, endPoint)
+	_, result := ezmqx.GetByteStandAloneSubscriber(*topic, byteSubCB, byteErrorCB)
+	if result != ezmqx.EZMQX_INVALID_TOPIC {
+		t.Errorf("Get subscriber failed")
+	}
+	topic = ezmqx.GetEZMQXTopic(utils.TOPIC, "", false, endPoint)
+	topicList := list.New()
+	topicList.PushBack(*topic)
+	_, result = ezmqx.GetByteStandAloneSubscriber1(*topicList, byteSubCB, byteErrorCB)
+	if result != ezmqx.EZMQX_INVALID_PARAM {
+		t.Errorf("Get subscriber failed")
+	}
+	configInstance.Reset()
+}
+
+func TestGetSecuredByteSubscriber(t *testing.T) {
+	configInstance := ezmqx.GetConfigInstance()
+	configInstance.StartStandAloneMode(utils.TEST_LOCAL_HOST, false, "")
+	endPoint := ezmqx.GetEZMQXEndPoint1(utils.ADDRESS, utils.PORT)
+	topic := ezmqx.GetEZMQXTopic(utils.TOPIC, utils.BYTE_DATA_MODEL, true, endPoint)
+	subscriber, _ := ezmqx.GetSecuredByteSubscriber(*topic, utils.SERVER_PUBLIC_KEY, utils.CLIENT_PUBLIC_KEY, utils.CLIENT_SECRET_KEY, byteSubCB, byteErrorCB)
+	if nil == subscriber {
+		t.Errorf("subscriber is nil")
+	}
+	isSecured, _ := subscriber.IsSecured()
+	if !isSecured {
+		t.Errorf("subscriber is secured failed")
+	}
+	subscriber.Terminate()
+	configInstance.Reset()
+}
+
+func TestByteSubscriberStandAlone(t *testing.T) {
+	configInstance := ezmqx.GetConfigInstance()
+	configInstance.StartStandAloneMode(utils.TEST_LOCAL_HOST, false, "")
+	endPoint := ezmqx.GetEZMQXEndPoint1(utils.TEST_LOCAL_HOST, utils.PORT)
+	topic := ezmqx.GetEZMQXTopic(utils.TOPIC, utils.BYTE_DATA_MODEL, false, endPoint)
+	subscriber, _ := ezmqx.GetByteStandAloneSubscriber(*topic, byteSubCB, byteErrorCB)
+	if nil == subscriber {
+		t.Errorf("subscriber is nil")
+	}
+
+	// Routine to publish data on socket
+	go utils.PublishBytes()
+
+	// Wait till publisher is stopped
+	<-utils.Exit_Chan
+	time.Sleep(1000 * time.Millisecond)
+	if byteEventCount < 5 {
+		t.Errorf("Received less event")
+	}
+	subscriber.Terminate()
+	configInstance.Reset()
+}
+
+func TestByteSubscriberEnvelope(t *testing.T) {
+	configInstance := ezmqx.GetConfigInstance()
+	configInstance.StartStandAloneMode(utils.TEST_LOCAL_HOST, false, "")
+	publisher, _ := ezmqx.GetBytePublisher(utils.TOPIC, utils.BYTE_DATA_MODEL, utils.PORT)
+	result := publisher.EnableEnvelope("publisher-1")
+	if result != ezmqx.EZMQX_OK {
+		t.Errorf("Enable envelope failed")
+	}
+	endPoint := ezmqx.GetEZMQXEndPoint1(utils.TEST_LOCAL_HOST, utils.PORT)
+	topic := ezmqx.GetEZMQXTopic(utils.TOPIC, utils.BYTE_DATA_MODEL, false, endPoint)
+	subscriber, _ := ezmqx.GetByteStandAloneSubscriber(*topic, byteSubCB, byteErrorCB)
+	time.Sleep(1000 * time.Millisecond)
+	byteEventCount = 0
+	byteErrorCount = 0
+	for i := 0; i < utils.NUMBER_OF_EVENTS; i++ {
+		publisher.Publish([]byte(utils.BYTE_DATA))
+		time.Sleep(100 * time.Millisecond)
+	}
+	time.Sleep(1000 * time.Millisecond)
+	// envelope should be removed before callback
+	if byteEventCount < utils.NUMBER_OF_EVENTS {
+		t.Errorf("Received less event")
+	}
+	if byteErrorCount != 0 {
+		t.Errorf("Unexpected gap or duplicate reported")
+	}
+	subscriber.Terminate()
+	publisher.Terminate()
+	configInstance.Reset()
+}
+
+func TestByteSubscriberLastValueCache(t *testing.T) {
+	configInstance := ezmqx.GetConfigInstance()
+	configInstance.StartStandAloneMode(utils.TEST_LOCAL_HOST, false, "")
+	configInstance.SetLastValueCache(utils.LAST_VALUE_DEPTH)
+	publisher, _ := ezmqx.GetBytePublisher(utils.TOPIC, utils.BYTE_DATA_MODEL, utils.PORT)
+	for i := 0; i < utils.NUMBER_OF_EVENTS; i++ {
+		publisher.Publish([]byte(utils.BYTE_DATA))
+	}
+	pubTopic, _ := publisher.GetTopic()
+	if nil == pubTopic.GetSnapshotEndPoint() {
+		t.Errorf("Snapshot end point is nil")
+	}
+	byteEventCount = 0
+	endPoint := ezmqx.GetEZMQXEndPoint1(utils.TEST_LOCAL_HOST, utils.PORT)
+	topic := ezmqx.GetEZMQXTopic1(utils.TOPIC, utils.BYTE_DATA_MODEL, false, endPoint, pubTopic.GetSnapshotEndPoint())
+	subscriber, _ := ezmqx.GetByteStandAloneSubscriber(*topic, byteSubCB, byteErrorCB)
+	// cached messages are delivered on subscribe
+	if byteEventCount != utils.LAST_VALUE_DEPTH {
+		t.Errorf("Snapshot is not delivered")
+	}
+	subscriber.Terminate()
+	publisher.Terminate()
+	configInstance.Reset()
+}
+
+func TestByteSubscriberCompression(t *testing.T) {
+	compressions := []ezmqx.EZMQXCompression{ezmqx.COMPRESSION_GZIP, ezmqx.COMPRESSION_ZSTD}
+	for _, compression := range compressions {
+		configInstance := ezmqx.GetConfigInstance()
+		configInstance.StartStandAloneMode(utils.TEST_LOCAL_HOST, false, "")
+		configInstance.SetCompression(compression)
+		publisher, _ := ezmqx.GetBytePublisher(utils.TOPIC, utils.BYTE_DATA_MODEL, utils.PORT)
+		pubTopic, _ := publisher.GetTopic()
+		if pubTopic.GetCompression() != compression {
+			t.Errorf("Compression is not set on topic")
+		}
+		endPoint := ezmqx.GetEZMQXEndPoint1(utils.TEST_LOCAL_HOST, utils.PORT)
+		topic := ezmqx.GetEZMQXTopic2(utils.TOPIC, utils.BYTE_DATA_MODEL, false, endPoint, nil, compression)
+		subscriber, _ := ezmqx.GetByteStandAloneSubscriber(*topic, byteSubCB, byteErrorCB)
+		time.Sleep(1000 * time.Millisecond)
+		byteEventCount = 0
+		byteErrorCount = 0
+		for i := 0; i < utils.NUMBER_OF_EVENTS; i++ {
+			publisher.Publish([]byte(utils.BYTE_DATA))
+			time.Sleep(100 * time.Millisecond)
+		}
+		time.Sleep(1000 * time.Millisecond)
+		// payload should be decompressed before callback
+		if byteEventCount < utils.NUMBER_OF_EVENTS {
+			t.Errorf("Received less event")
+		}
+		if byteErrorCount != 0 {
+			t.Errorf("Decompression failed")
+		}
+		subscriber.Terminate()
+		publisher.Terminate()
+		configInstance.Reset()
+	}
+}
+
+func TestByteSubscriberOversizedPayload(t *testing.T) {
+	compressions := []ezmqx.EZMQXCompression{ezmqx.COMPRESSION_GZIP, ezmqx.COMPRESSION_ZSTD}
+	for _, compression := range compressions {
+		configInstance := ezmqx.GetConfigInstance()
+		configInstance.StartStandAloneMode(utils.TEST_LOCAL_HOST, false, "")
+		configInstance.SetCompression(compression)
+		publisher, _ := ezmqx.GetBytePublisher(utils.TOPIC, utils.BYTE_DATA_MODEL, utils.PORT)
+		endPoint := ezmqx.GetEZMQXEndPoint1(utils.TEST_LOCAL_HOST, utils.PORT)
+		topic := ezmqx.GetEZMQXTopic2(utils.TOPIC, utils.BYTE_DATA_MODEL, false, endPoint, nil, compression)
+		subscriber, _ := ezmqx.GetByteStandAloneSubscriber(*topic, byteSubCB, byteErrorCB)
+		time.Sleep(1000 * time.Millisecond)
+		byteEventCount = 0
+		byteErrorCount = 0
+		// compresses to a small frame, which exceeds maximum size when decompressed
+		publisher.Publish(make([]byte, ezmqx.MAX_DECOMPRESSED_PAYLOAD_SIZE+1))
+		time.Sleep(1000 * time.Millisecond)
+		if byteErrorCount != 1 {
+			t.Errorf("Oversized payload is not rejected")
+		}
+		subscriber.Terminate()
+		publisher.Terminate()
+		configInstance.Reset()
+	}
+}
+
+func TestByteSubscriberFlowControl(t *testing.T) {
+	configInstance := ezmqx.GetConfigInstance()
+	configInstance.StartStandAloneMode(utils.TEST_LOCAL_HOST, false, "")
+	flowControl := ezmqx.GetEZMQXFlowControl(utils.QUEUE_SIZE, utils.QUEUE_SIZE, utils.LINGER, ezmqx.OVERFLOW_BLOCK)
+	configInstance.SetFlowControl(flowControl)
+	publisher, _ := ezmqx.GetBytePublisher(utils.TOPIC, utils.BYTE_DATA_MODEL, utils.PORT)
+	endPoint := ezmqx.GetEZMQXEndPoint1(utils.TEST_LOCAL_HOST, utils.PORT)
+	topic := ezmqx.GetEZMQXTopic(utils.TOPIC, utils.BYTE_DATA_MODEL, false, endPoint)
+	subscriber, _ := ezmqx.GetByteStandAloneSubscriber(*topic, byteSubCB, byteErrorCB)
+	time.Sleep(1000 * time.Millisecond)
+	byteEventCount = 0
+	for i := 0; i < utils.NUMBER_OF_EVENTS; i++ {
+		result := publisher.Publish([]byte(utils.BYTE_DATA))
+		if result != ezmqx.EZMQX_OK {
+			t.Errorf("Publish failed")
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	time.Sleep(1000 * time.Millisecond)
+	if byteEventCount < utils.NUMBER_OF_EVENTS {
+		t.Errorf("Received less event")
+	}
+	// nothing is dropped with block policy
+	dropped, _ := publisher.GetDroppedCount()
+	if dropped != 0 {
+		t.Errorf("Publisher dropped messages")
+	}
+	dropped, _ = subscriber.GetDroppedCount()
+	if dropped != 0 {
+		t.Errorf("Subscriber dropped messages")
+	}
+	subscriber.Terminate()
+	publisher.Terminate()
+	configInstance.Reset()
+}
+
+func TestByteFlowControlNegative(t *testing.T) {
+	configInstance := ezmqx.GetConfigInstance()
+	configInstance.StartStandAloneMode(utils.TEST_LOCAL_HOST, false, "")
+	flowControl := ezmqx.GetEZMQXFlowControl(-1, utils.QUEUE_SIZE, utils.LINGER, ezmqx.OVERFLOW_BLOCK)
+	_, err := ezmqx.GetBytePublisherWithContext(context.Background(), utils.TOPIC, utils.BYTE_DATA_MODEL, utils.PORT, flowControl)
+	if ezmqx.GetErrorCode(err) != ezmqx.EZMQX_INVALID_PARAM {
+		t.Errorf("Publisher created with invalid flow control")
+	}
+	_, err = ezmqx.GetByteSubscriberWithContext(context.Background(), utils.TOPIC, false, byteSubCB, byteErrorCB, flowControl)
+	if ezmqx.GetErrorCode(err) != ezmqx.EZMQX_INVALID_PARAM {
+		t.Errorf("Subscriber created with invalid flow control")
+	}
+	configInstance.Reset()
+}
+
+func TestEnableEnvelopeNegative(t *testing.T) {
+	configInstance := ezmqx.GetConfigInstance()
+	configInstance.StartStandAloneMode(utils.TEST_LOCAL_HOST, false, "")
+	publisher, _ := ezmqx.GetBytePublisher(utils.TOPIC, utils.BYTE_DATA_MODEL, utils.PORT)
+	result := publisher.EnableEnvelope(strings.Repeat("a", 256))
+	if result != ezmqx.EZMQX_INVALID_PARAM {
+		t.Errorf("Enable envelope failed")
+	}
+	result = publisher.EnableEnvelope("")
+	if result != ezmqx.EZMQX_OK {
+		t.Errorf("Enable envelope failed")
+	}
+	publisher.Terminate()
+	configInstance.Reset()
+}
+
+func TestByteSubDockerMode(t *testing.T) {
+	configInstance := ezmqx.GetConfigInstance()
+	utils.Factory.SetFactory(utils.FakeRestClientFactory{})
+	utils.SetRestResponse(utils.CONFIG_URL, []byte(utils.VALID_CONFIG_RESPONSE))
+	utils.SetRestResponse(utils.TNS_INFO_URL, []byte(utils.VALID_TNS_INFO_RESPONSE))
+	utils.SetRestResponse(utils.RUNNING_APPS_URL, []byte(utils.VALID_RUNNING_APPS_RESPONSE))
+	utils.SetRestResponse(utils.RUNNING_APP_INFO_URL, []byte(utils.RUNNING_APP_INFO_RESPONSE))
+	configInstance.StartDockerMode(utils.TNS_CONFIG_FILE_PATH)
+	utils.SetRestResponse(utils.SUB_TOPIC_H_URL, []byte(utils.SUB_TOPIC_RESPONSE))
+	subscriber, _ := ezmqx.GetByteSubscriber(utils.TOPIC, true, byteSubCB, byteErrorCB)
+	if nil == subscriber {
+		t.Errorf("subscriber is nil")
+	}
+	subscriber.Terminate()
+	configInstance.Reset()
+}
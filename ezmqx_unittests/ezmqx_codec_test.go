@@ -0,0 +1,156 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmqx_unittests
+
+import (
+	"container/list"
+	"context"
+	"go/aml"
+	"go/ezmqx"
+	"go/ezmqx_unittests/utils"
+	"testing"
+	"time"
+)
+
+func TestAMLCodec(t *testing.T) {
+	configInstance := ezmqx.GetConfigInstance()
+	configInstance.StartStandAloneMode(utils.TEST_LOCAL_HOST, false, "")
+	amlFilePath := list.New()
+	amlFilePath.PushBack(utils.AML_FILE_PATH)
+	idList, _ := configInstance.AddAmlModel(*amlFilePath)
+	codec, result := ezmqx.GetAMLCodec(idList.Front().Value.(string))
+	if result != ezmqx.EZMQX_OK {
+		t.Errorf("Get codec failed")
+	}
+	if codec.GetDataModel() != idList.Front().Value.(string) {
+		t.Errorf("Data model mismatch")
+	}
+	data, result := codec.Encode(utils.GetAMLObject())
+	if result != ezmqx.EZMQX_OK {
+		t.Errorf("Encode failed")
+	}
+	object, result := codec.Decode(data)
+	if result != ezmqx.EZMQX_OK || nil == object {
+		t.Errorf("Decode failed")
+	}
+	_, result = codec.Decode([]byte(utils.BYTE_DATA))
+	if result != ezmqx.EZMQX_BROKEN_PAYLOAD {
+		t.Errorf("Decode broken payload failed")
+	}
+	_, result = ezmqx.GetAMLCodec(utils.DATA_MODEL)
+	if result == ezmqx.EZMQX_OK {
+		t.Errorf("Get codec of unknown model succeeded")
+	}
+	configInstance.Reset()
+}
+
+func TestGetCodecResolver(t *testing.T) {
+	configInstance := ezmqx.GetConfigInstance()
+	configInstance.StartStandAloneMode(utils.TEST_LOCAL_HOST, false, "")
+	amlFilePath := list.New()
+	amlFilePath.PushBack(utils.AML_FILE_PATH)
+	idList, _ := configInstance.AddAmlModel(*amlFilePath)
+	codec, _ := ezmqx.GetXMLCodec(idList.Front().Value.(string))
+	resolver := ezmqx.GetCodecResolver(codec)
+	_, result := resolver(idList.Front().Value.(string))
+	if result != ezmqx.EZMQX_OK {
+		t.Errorf("Resolve codec failed")
+	}
+	_, result = resolver(utils.DATA_MODEL)
+	if result != ezmqx.EZMQX_INVALID_PARAM {
+		t.Errorf("Resolve codec of other data model succeeded")
+	}
+	configInstance.Reset()
+}
+
+func TestByteCodec(t *testing.T) {
+	codec := ezmqx.GetByteCodec(utils.DATA_MODEL)
+	if codec.GetDataModel() != utils.DATA_MODEL {
+		t.Errorf("Data model mismatch")
+	}
+	data, result := codec.Encode([]byte(utils.BYTE_DATA))
+	if result != ezmqx.EZMQX_OK || string(data) != utils.BYTE_DATA {
+		t.Errorf("Encode failed")
+	}
+	data, result = codec.Decode(data)
+	if result != ezmqx.EZMQX_OK || string(data) != utils.BYTE_DATA {
+		t.Errorf("Decode failed")
+	}
+}
+
+func TestJSONCodec(t *testing.T) {
+	codec := ezmqx.GetJSONCodec()
+	if codec.GetDataModel() != ezmqx.JSON_DATA_MODEL {
+		t.Errorf("Data model mismatch")
+	}
+	data, result := codec.Encode(map[string]interface{}{"x": 1.5})
+	if result != ezmqx.EZMQX_OK {
+		t.Errorf("Encode failed")
+	}
+	value, result := codec.Decode(data)
+	if result != ezmqx.EZMQX_OK || value.(map[string]interface{})["x"] != 1.5 {
+		t.Errorf("Decode failed")
+	}
+	_, result = codec.Encode(make(chan int))
+	if result != ezmqx.EZMQX_INVALID_PARAM {
+		t.Errorf("Encode of invalid value succeeded")
+	}
+	_, result = codec.Decode([]byte(utils.BYTE_DATA))
+	if result != ezmqx.EZMQX_BROKEN_PAYLOAD {
+		t.Errorf("Decode broken payload failed")
+	}
+}
+
+func TestTypedPublisherSubscriber(t *testing.T) {
+	configInstance := ezmqx.GetConfigInstance()
+	configInstance.StartStandAloneMode(utils.TEST_LOCAL_HOST, false, "")
+	amlFilePath := list.New()
+	amlFilePath.PushBack(utils.AML_FILE_PATH)
+	idList, _ := configInstance.AddAmlModel(*amlFilePath)
+	codec, _ := ezmqx.GetAMLCodec(idList.Front().Value.(string))
+	publisher, err := ezmqx.GetTypedPublisher(context.Background(), utils.TOPIC, codec, utils.PORT, nil)
+	if err != nil {
+		t.Errorf("Get publisher failed: %v", err)
+		return
+	}
+	topic, _ := publisher.GetTopic()
+	topicList := list.New()
+	topicList.PushBack(*topic)
+	eventCount := 0
+	subscriber, result := ezmqx.GetTypedStandAloneSubscriber(*topicList, configInstance.GetAMLCodec,
+		func(topic string, object *aml.AMLObject) {
+			eventCount++
+		},
+		func(topic string, errorCode ezmqx.EZMQXErrorCode) {
+		})
+	if result != ezmqx.EZMQX_OK {
+		t.Errorf("Get subscriber failed")
+	}
+	time.Sleep(1000 * time.Millisecond)
+	for i := 0; i < utils.NUMBER_OF_EVENTS; i++ {
+		publisher.Publish(utils.GetAMLObject())
+		time.Sleep(100 * time.Millisecond)
+	}
+	time.Sleep(1000 * time.Millisecond)
+	if eventCount < utils.NUMBER_OF_EVENTS {
+		t.Errorf("Received less event")
+	}
+	subscriber.Terminate()
+	publisher.Terminate()
+	configInstance.Reset()
+}
@@ -19,6 +19,8 @@ package ezmqx_unittests
 
 import (
 	"container/list"
+	"context"
+	"errors"
 	"go/ezmqx"
 	"go/ezmqx_unittests/utils"
 	"testing"
@@ -76,6 +78,42 @@ func TestStartDockerMode(t *testing.T) {
 	instance.Reset()
 }
 
+func TestStartDockerModeWithContext(t *testing.T) {
+	utils.Factory.SetFactory(utils.FakeRestClientFactory{})
+	utils.SetRestResponse(utils.CONFIG_URL, []byte(utils.VALID_CONFIG_RESPONSE))
+	utils.SetRestResponse(utils.TNS_INFO_URL, []byte(utils.VALID_TNS_INFO_RESPONSE))
+	utils.SetRestResponse(utils.RUNNING_APPS_URL, []byte(utils.VALID_RUNNING_APPS_RESPONSE))
+	utils.SetRestResponse(utils.RUNNING_APP_INFO_URL, []byte(utils.RUNNING_APP_INFO_RESPONSE))
+	var instance *ezmqx.EZMQXConfig = ezmqx.GetConfigInstance()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := instance.StartDockerModeWithContext(ctx, utils.TNS_CONFIG_FILE_PATH)
+	if ezmqx.EZMQX_CANCELED != ezmqx.GetErrorCode(err) {
+		t.Errorf("Start docker mode [Canceled]: Error")
+	}
+	err = instance.StartDockerModeWithContext(context.Background(), utils.TNS_CONFIG_FILE_PATH)
+	if err != nil {
+		t.Errorf("Start docker mode: Error")
+	}
+	instance.Reset()
+}
+
+func TestStartDockerModeCanceled(t *testing.T) {
+	var instance *ezmqx.EZMQXConfig = ezmqx.GetConfigInstance()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := instance.StartDockerModeWithContext(ctx, utils.TNS_CONFIG_FILE_PATH)
+	if ezmqx.EZMQX_CANCELED != ezmqx.GetErrorCode(err) || !errors.Is(err, context.Canceled) {
+		t.Errorf("Start docker mode [Canceled]: Error")
+	}
+	// State is not changed by canceled start
+	result := instance.StartStandAloneMode(utils.TEST_LOCAL_HOST, false, "")
+	if ezmqx.EZMQX_OK != result {
+		t.Errorf("Start standalone mode after canceled start: Error")
+	}
+	instance.Reset()
+}
+
 func TestStartDockerModeNegative(t *testing.T) {
 	utils.Factory.SetFactory(utils.FakeRestClientFactory{})
 	utils.SetRestResponse(utils.CONFIG_URL, []byte(utils.VALID_CONFIG_RESPONSE))
@@ -112,6 +150,127 @@ func TestAddAmlModelNegative(t *testing.T) {
 	}
 }
 
+func TestSetLastValueCache(t *testing.T) {
+	var instance *ezmqx.EZMQXConfig = ezmqx.GetConfigInstance()
+	instance.StartStandAloneMode(utils.TEST_LOCAL_HOST, false, "")
+	result := instance.SetLastValueCache(utils.LAST_VALUE_DEPTH)
+	if ezmqx.EZMQX_OK != result {
+		t.Errorf("SetLastValueCache: Error")
+	}
+	result = instance.SetLastValueCache(-1)
+	if ezmqx.EZMQX_INVALID_PARAM != result {
+		t.Errorf("SetLastValueCache [Negative depth]: Error")
+	}
+	instance.Reset()
+}
+
+func TestSetLastValueCacheNegative(t *testing.T) {
+	var instance *ezmqx.EZMQXConfig = ezmqx.GetConfigInstance()
+	result := instance.SetLastValueCache(utils.LAST_VALUE_DEPTH)
+	if ezmqx.EZMQX_NOT_INITIALIZED != result {
+		t.Errorf("SetLastValueCache: Error")
+	}
+}
+
+func TestSetCompression(t *testing.T) {
+	var instance *ezmqx.EZMQXConfig = ezmqx.GetConfigInstance()
+	instance.StartStandAloneMode(utils.TEST_LOCAL_HOST, false, "")
+	result := instance.SetCompression(ezmqx.COMPRESSION_GZIP)
+	if ezmqx.EZMQX_OK != result {
+		t.Errorf("SetCompression: Error")
+	}
+	result = instance.SetCompression(ezmqx.EZMQXCompression(100))
+	if ezmqx.EZMQX_INVALID_PARAM != result {
+		t.Errorf("SetCompression [Invalid codec]: Error")
+	}
+	instance.Reset()
+}
+
+func TestSetFlowControl(t *testing.T) {
+	var instance *ezmqx.EZMQXConfig = ezmqx.GetConfigInstance()
+	instance.StartStandAloneMode(utils.TEST_LOCAL_HOST, false, "")
+	flowControl := ezmqx.GetEZMQXFlowControl(utils.QUEUE_SIZE, utils.QUEUE_SIZE, utils.LINGER, ezmqx.OVERFLOW_DROP)
+	result := instance.SetFlowControl(flowControl)
+	if ezmqx.EZMQX_OK != result {
+		t.Errorf("SetFlowControl: Error")
+	}
+	flowControl = ezmqx.GetEZMQXFlowControl(-1, utils.QUEUE_SIZE, utils.LINGER, ezmqx.OVERFLOW_BLOCK)
+	result = instance.SetFlowControl(flowControl)
+	if ezmqx.EZMQX_INVALID_PARAM != result {
+		t.Errorf("SetFlowControl [Negative queue size]: Error")
+	}
+	result = instance.SetFlowControl(nil)
+	if ezmqx.EZMQX_OK != result {
+		t.Errorf("SetFlowControl [nil]: Error")
+	}
+	instance.Reset()
+}
+
+func TestSetRegistrationCallback(t *testing.T) {
+	var instance *ezmqx.EZMQXConfig = ezmqx.GetConfigInstance()
+	callback := func(topic string, state ezmqx.EZMQXRegistrationState) {}
+	result := instance.SetRegistrationCallback(callback)
+	if ezmqx.EZMQX_NOT_INITIALIZED != result {
+		t.Errorf("SetRegistrationCallback [Not initialized]: Error")
+	}
+	instance.StartStandAloneMode(utils.ADDRESS, true, utils.TNS_ADDRESS)
+	result = instance.SetRegistrationCallback(callback)
+	if ezmqx.EZMQX_OK != result {
+		t.Errorf("SetRegistrationCallback: Error")
+	}
+	result = instance.SetRegistrationCallback(nil)
+	if ezmqx.EZMQX_OK != result {
+		t.Errorf("SetRegistrationCallback [nil]: Error")
+	}
+	instance.Reset()
+}
+
+func TestGetEZMQXConfig(t *testing.T) {
+	first := ezmqx.GetEZMQXConfig()
+	second := ezmqx.GetEZMQXConfig()
+	if first == second || first == ezmqx.GetConfigInstance() {
+		t.Errorf("GetEZMQXConfig: instance is not new")
+	}
+	first.SetRestClientFactory(utils.FakeRestClientFactory{})
+	result := first.StartStandAloneMode(utils.ADDRESS, true, utils.TNS_ADDRESS)
+	if ezmqx.EZMQX_OK != result {
+		t.Errorf("StartStandAloneMode [first]: Error")
+	}
+	if ezmqx.EZMQX_INITIALIZED != first.SetRestClientFactory(utils.FakeRestClientFactory{}) {
+		t.Errorf("SetRestClientFactory [started]: Error")
+	}
+	result = second.StartStandAloneMode(utils.ADDRESS, false, "")
+	if ezmqx.EZMQX_OK != result {
+		t.Errorf("StartStandAloneMode [second]: Error")
+	}
+	_, result = ezmqx.GetEZMQXTopicDiscovery()
+	if ezmqx.EZMQX_NOT_INITIALIZED != result {
+		t.Errorf("Default instance is initialized")
+	}
+
+	utils.SetRestResponse(utils.TOPIC_DISCOVERY_URL, []byte(utils.VALID_TOPIC_DISCOVERY_RESPONSE))
+	topicDiscovery, _ := first.GetEZMQXTopicDiscovery()
+	_, result = topicDiscovery.Query(utils.TOPIC)
+	if ezmqx.EZMQX_OK != result {
+		t.Errorf("Query [first]: Error")
+	}
+	topicDiscovery, _ = second.GetEZMQXTopicDiscovery()
+	_, result = topicDiscovery.Query(utils.TOPIC)
+	if ezmqx.EZMQX_TNS_NOT_AVAILABLE != result {
+		t.Errorf("Query [second]: TNS is available")
+	}
+
+	publisher, err := second.GetBytePublisherWithContext(context.Background(), utils.TOPIC, utils.BYTE_DATA_MODEL, utils.SECOND_PORT, nil)
+	if err != nil {
+		t.Errorf("GetBytePublisherWithContext [second]: %v", err)
+	} else {
+		publisher.Terminate()
+	}
+	if ezmqx.EZMQX_OK != first.Reset() || ezmqx.EZMQX_OK != second.Reset() {
+		t.Errorf("Reset: Error")
+	}
+}
+
 func TestReset(t *testing.T) {
 	var instance *ezmqx.EZMQXConfig = ezmqx.GetConfigInstance()
 	result := instance.StartStandAloneMode(utils.TEST_LOCAL_HOST, false, "")
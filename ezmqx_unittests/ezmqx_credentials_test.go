@@ -0,0 +1,122 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmqx_unittests
+
+import (
+	"context"
+	"go/ezmqx"
+	"go/ezmqx_unittests/utils"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+// Starts TNS server behind a gateway which accepts only given bearer token.
+func startAuthTNSServer(token string) *httptest.Server {
+	return utils.StartTNSServer(func(tnsServer http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			if request.Header.Get("Authorization") != "Bearer "+token {
+				writer.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			tnsServer.ServeHTTP(writer, request)
+		})
+	}, nil)
+}
+
+func TestCallbackCredentialsRetry(t *testing.T) {
+	server := startAuthTNSServer(utils.AUTH_TOKEN)
+	defer server.Close()
+
+	refreshCount := 0
+	credentials := ezmqx.GetCallbackCredentials(func(ctx context.Context, refresh bool) (string, error) {
+		if refresh {
+			refreshCount++
+			return utils.AUTH_TOKEN, nil
+		}
+		return utils.EXPIRED_AUTH_TOKEN, nil
+	})
+	configInstance := ezmqx.GetEZMQXConfig()
+	configInstance.SetRestClientFactory(ezmqx.RestClientFactory{})
+	configInstance.SetCredentialsProvider(credentials)
+	configInstance.StartStandAloneMode(utils.ADDRESS, true, server.URL)
+	defer configInstance.Reset()
+
+	topicDiscovery, _ := configInstance.GetEZMQXTopicDiscovery()
+	_, result := topicDiscovery.Query(utils.TNS_SERVER_TOPIC_NAME)
+	if result != ezmqx.EZMQX_OK {
+		t.Errorf("Query with refreshed token failed: %d", result)
+	}
+	if refreshCount != 1 {
+		t.Errorf("Token refresh count mismatch: %d", refreshCount)
+	}
+}
+
+func TestCredentialsUnauthorized(t *testing.T) {
+	server := startAuthTNSServer(utils.AUTH_TOKEN)
+	defer server.Close()
+
+	configInstance := ezmqx.GetEZMQXConfig()
+	configInstance.SetRestClientFactory(ezmqx.RestClientFactory{})
+	configInstance.SetCredentialsProvider(ezmqx.GetStaticCredentials(utils.EXPIRED_AUTH_TOKEN))
+	configInstance.StartStandAloneMode(utils.ADDRESS, true, server.URL)
+	defer configInstance.Reset()
+
+	topicDiscovery, _ := configInstance.GetEZMQXTopicDiscovery()
+	_, result := topicDiscovery.Query(utils.TNS_SERVER_TOPIC_NAME)
+	if result != ezmqx.EZMQX_REST_ERROR {
+		t.Errorf("Query with expired token should fail: %d", result)
+	}
+	result = configInstance.SetCredentialsProvider(ezmqx.GetStaticCredentials(utils.AUTH_TOKEN))
+	if result != ezmqx.EZMQX_INITIALIZED {
+		t.Errorf("Set credentials provider should fail after start: %d", result)
+	}
+}
+
+func TestFileCredentials(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	credentials := ezmqx.GetFileCredentials(path)
+	_, err := credentials.GetAuthorization(context.Background(), false)
+	if err == nil {
+		t.Errorf("Get authorization without token file should fail")
+	}
+	ioutil.WriteFile(path, []byte(utils.EXPIRED_AUTH_TOKEN+"\n"), 0600)
+	authorization, err := credentials.GetAuthorization(context.Background(), false)
+	if err != nil || authorization != "Bearer "+utils.EXPIRED_AUTH_TOKEN {
+		t.Errorf("Authorization mismatch: %s %v", authorization, err)
+	}
+	// Token file is read again on change
+	ioutil.WriteFile(path, []byte(utils.AUTH_TOKEN), 0600)
+	authorization, err = credentials.GetAuthorization(context.Background(), false)
+	if err != nil || authorization != "Bearer "+utils.AUTH_TOKEN {
+		t.Errorf("Authorization mismatch after token change: %s %v", authorization, err)
+	}
+}
+
+func TestStaticCredentials(t *testing.T) {
+	authorization, err := ezmqx.GetStaticCredentials(utils.AUTH_TOKEN).GetAuthorization(context.Background(), true)
+	if err != nil || authorization != "Bearer "+utils.AUTH_TOKEN {
+		t.Errorf("Authorization mismatch: %s %v", authorization, err)
+	}
+	_, err = ezmqx.GetStaticCredentials("").GetAuthorization(context.Background(), false)
+	if err == nil {
+		t.Errorf("Get authorization with empty token should fail")
+	}
+}
@@ -0,0 +1,69 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmqx_unittests
+
+import (
+	"errors"
+	"fmt"
+	"go/ezmqx"
+	"strings"
+	"testing"
+)
+
+func TestEZMQXError(t *testing.T) {
+	cause := errors.New("nodes key missing")
+	err := ezmqx.GetEZMQXError(ezmqx.EZMQX_REST_ERROR, "parseTnsInfoResponse", cause)
+	if err.GetErrorCode() != ezmqx.EZMQX_REST_ERROR {
+		t.Errorf("Error code mismatch")
+	}
+	if err.GetOperation() != "parseTnsInfoResponse" {
+		t.Errorf("Operation mismatch")
+	}
+	if err.Error() != "parseTnsInfoResponse: nodes key missing [EZMQX_REST_ERROR]" {
+		t.Errorf("Error message mismatch: %s", err.Error())
+	}
+	if !errors.Is(err, ezmqx.EZMQXErrorCode(ezmqx.EZMQX_REST_ERROR)) {
+		t.Errorf("Error is not EZMQX_REST_ERROR")
+	}
+	if errors.Is(err, ezmqx.EZMQXErrorCode(ezmqx.EZMQX_INVALID_TOPIC)) {
+		t.Errorf("Error is EZMQX_INVALID_TOPIC")
+	}
+	if errors.Unwrap(err) != cause || !errors.Is(err, cause) {
+		t.Errorf("Cause is not unwrapped")
+	}
+}
+
+func TestGetErrorCode(t *testing.T) {
+	if ezmqx.GetErrorCode(nil) != ezmqx.EZMQX_OK {
+		t.Errorf("Error code of nil is not EZMQX_OK")
+	}
+	err := ezmqx.GetEZMQXError(ezmqx.EZMQX_CANCELED, "Query", nil)
+	wrapped := fmt.Errorf("query failed: %w", err)
+	if ezmqx.GetErrorCode(wrapped) != ezmqx.EZMQX_CANCELED {
+		t.Errorf("Error code of wrapped error mismatch")
+	}
+	if !errors.Is(wrapped, ezmqx.EZMQXErrorCode(ezmqx.EZMQX_CANCELED)) {
+		t.Errorf("Wrapped error is not EZMQX_CANCELED")
+	}
+	if ezmqx.GetErrorCode(errors.New("other")) != ezmqx.EZMQX_UNKNOWN_STATE {
+		t.Errorf("Error code of other error is not EZMQX_UNKNOWN_STATE")
+	}
+	if !strings.Contains(ezmqx.EZMQXErrorCode(ezmqx.EZMQX_TERMINATED).Error(), "EZMQX_TERMINATED") {
+		t.Errorf("Error code name mismatch")
+	}
+}
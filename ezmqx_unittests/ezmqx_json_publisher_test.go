@@ -0,0 +1,60 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmqx_unittests
+
+import (
+	"encoding/json"
+	"go/ezmqx"
+	"go/ezmqx_unittests/utils"
+	"testing"
+)
+
+func TestGetJSONPublisherStandAlone(t *testing.T) {
+	configInstance := ezmqx.GetConfigInstance()
+	configInstance.StartStandAloneMode(utils.TEST_LOCAL_HOST, false, "")
+	publisher, _ := ezmqx.GetJSONPublisher(utils.TOPIC, utils.PORT)
+	if nil == publisher {
+		t.Errorf("publisher is nil")
+	}
+	topic, _ := publisher.GetTopic()
+	if topic.GetDataModel() != ezmqx.JSON_DATA_MODEL {
+		t.Errorf("Data model mismatch")
+	}
+	publisher.Terminate()
+	configInstance.Reset()
+}
+
+func TestJSONPublish(t *testing.T) {
+	configInstance := ezmqx.GetConfigInstance()
+	configInstance.StartStandAloneMode(utils.TEST_LOCAL_HOST, false, "")
+	publisher, _ := ezmqx.GetJSONPublisher(utils.TOPIC, utils.PORT)
+	result := publisher.Publish(map[string]interface{}{"x": 20, "y": 110})
+	if result != ezmqx.EZMQX_OK {
+		t.Errorf("publish failed")
+	}
+	result = publisher.Publish(json.RawMessage(`{"x": 20}`))
+	if result != ezmqx.EZMQX_OK {
+		t.Errorf("publish raw message failed")
+	}
+	result = publisher.Publish(make(chan int))
+	if result != ezmqx.EZMQX_INVALID_PARAM {
+		t.Errorf("publish invalid value failed")
+	}
+	publisher.Terminate()
+	configInstance.Reset()
+}
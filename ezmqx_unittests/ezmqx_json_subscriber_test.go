@@ -0,0 +1,78 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmqx_unittests
+
+import (
+	"go/ezmqx"
+	"go/ezmqx_unittests/utils"
+	"testing"
+)
+
+func jsonSubCB(topic string, value interface{}) {
+}
+func jsonErrorCB(topic string, errorCode ezmqx.EZMQXErrorCode) {
+}
+
+func TestGetJSONStandAloneSubscriber(t *testing.T) {
+	configInstance := ezmqx.GetConfigInstance()
+	configInstance.StartStandAloneMode(utils.TEST_LOCAL_HOST, false, "")
+	endPoint := ezmqx.GetEZMQXEndPoint1(utils.ADDRESS, utils.PORT)
+	topic := ezmqx.GetEZMQXTopic(utils.TOPIC, ezmqx.JSON_DATA_MODEL, false, endPoint)
+	subscriber, _ := ezmqx.GetJSONStandAloneSubscriber(*topic, jsonSubCB, jsonErrorCB)
+	if nil == subscriber {
+		t.Errorf("subscriber is nil")
+	}
+	subscriber.Terminate()
+	configInstance.Reset()
+}
+
+func TestGetJSONStandAloneSubscriberNegative(t *testing.T) {
+	configInstance := ezmqx.GetConfigInstance()
+	configInstance.StartStandAloneMode(utils.TEST_LOCAL_HOST, false, "")
+	endPoint := ezmqx.GetEZMQXEndPoint1(utils.ADDRESS, utils.PORT)
+	//Data model is not json
+	topic := ezmqx.GetEZMQXTopic(utils.TOPIC, utils.BYTE_DATA_MODEL, false, endPoint)
+	_, result := ezmqx.GetJSONStandAloneSubscriber(*topic, jsonSubCB, jsonErrorCB)
+	if result != ezmqx.EZMQX_INVALID_PARAM {
+		t.Errorf("Get subscriber failed")
+	}
+	//Invalid topic
+	topic = ezmqx.GetEZMQXTopic("", ezmqx.JSON_DATA_MODEL, false, endPoint)
+	_, result = ezmqx.GetJSONStandAloneSubscriber(*topic, jsonSubCB, jsonErrorCB)
+	if result != ezmqx.EZMQX_INVALID_TOPIC {
+		t.Errorf("Get subscriber failed")
+	}
+	configInstance.Reset()
+}
+
+func TestGetSecuredJSONSubscriber(t *testing.T) {
+	configInstance := ezmqx.GetConfigInstance()
+	configInstance.StartStandAloneMode(utils.TEST_LOCAL_HOST, false, "")
+	endPoint := ezmqx.GetEZMQXEndPoint1(utils.ADDRESS, utils.PORT)
+	topic := ezmqx.GetEZMQXTopic(utils.TOPIC, ezmqx.JSON_DATA_MODEL, true, endPoint)
+	subscriber, _ := ezmqx.GetSecuredJSONSubscriber(*topic, utils.SERVER_PUBLIC_KEY, utils.CLIENT_PUBLIC_KEY, utils.CLIENT_SECRET_KEY, jsonSubCB, jsonErrorCB)
+	if nil == subscriber {
+		t.Errorf("subscriber is nil")
+	}
+	isSecured, _ := subscriber.IsSecured()
+	if !isSecured {
+		t.Errorf("subscriber is secured failed")
+	}
+	subscriber.Terminate()
+	configInstance.Reset()
+}
@@ -0,0 +1,140 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+//go:generate protoc --include_imports --descriptor_set_out=sample_data_model.desc sample_data_model.proto
+
+package ezmqx_unittests
+
+import (
+	"container/list"
+	"go/ezmqx"
+	"go/ezmqx_unittests/utils"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"testing"
+)
+
+func protoSubCB(topic string, message proto.Message) {
+}
+func protoErrorCB(topic string, errorCode ezmqx.EZMQXErrorCode) {
+}
+
+func addProtoModel(configInstance *ezmqx.EZMQXConfig) (*list.List, ezmqx.EZMQXErrorCode) {
+	descFilePath := list.New()
+	descFilePath.PushBack(utils.PROTO_FILE_PATH)
+	return configInstance.AddProtoModel(*descFilePath)
+}
+
+func TestAddProtoModel(t *testing.T) {
+	configInstance := ezmqx.GetConfigInstance()
+	configInstance.StartStandAloneMode(utils.TEST_LOCAL_HOST, false, "")
+	nameList, result := addProtoModel(configInstance)
+	if result != ezmqx.EZMQX_OK {
+		t.Errorf("Add proto model failed")
+	}
+	if nameList.Front().Value.(string) != utils.PROTO_MESSAGE_NAME {
+		t.Errorf("Message name mismatch")
+	}
+	configInstance.Reset()
+}
+
+func TestAddProtoModelNegative(t *testing.T) {
+	configInstance := ezmqx.GetConfigInstance()
+	_, result := addProtoModel(configInstance)
+	if result != ezmqx.EZMQX_NOT_INITIALIZED {
+		t.Errorf("Add proto model failed")
+	}
+	configInstance.StartStandAloneMode(utils.TEST_LOCAL_HOST, false, "")
+	descFilePath := list.New()
+	descFilePath.PushBack(utils.AML_FILE_PATH)
+	_, result = configInstance.AddProtoModel(*descFilePath)
+	if result != ezmqx.EZMQX_INVALID_PROTO_MODEL {
+		t.Errorf("Add proto model failed")
+	}
+	configInstance.Reset()
+}
+
+func TestGetProtoPublisher(t *testing.T) {
+	configInstance := ezmqx.GetConfigInstance()
+	configInstance.StartStandAloneMode(utils.TEST_LOCAL_HOST, false, "")
+	addProtoModel(configInstance)
+	publisher, _ := ezmqx.GetProtoPublisher(utils.TOPIC, utils.PROTO_MESSAGE_NAME, utils.PORT)
+	if nil == publisher {
+		t.Errorf("publisher is nil")
+	}
+	topic, _ := publisher.GetTopic()
+	if topic.GetDataModel() != utils.PROTO_MESSAGE_NAME {
+		t.Errorf("Data model mismatch")
+	}
+	// Message type mismatch
+	result := publisher.Publish(&descriptorpb.FileDescriptorSet{})
+	if result != ezmqx.EZMQX_INVALID_PARAM {
+		t.Errorf("Publish failed")
+	}
+	publisher.Terminate()
+	_, result = ezmqx.GetProtoPublisher(utils.TOPIC, "ezmqx.sample.Unknown", utils.PORT)
+	if result != ezmqx.EZMQX_UNKNOWN_PROTO_MODEL {
+		t.Errorf("Get publisher failed")
+	}
+	configInstance.Reset()
+}
+
+func TestGetProtoStandAloneSubscriber(t *testing.T) {
+	configInstance := ezmqx.GetConfigInstance()
+	configInstance.StartStandAloneMode(utils.TEST_LOCAL_HOST, false, "")
+	addProtoModel(configInstance)
+	endPoint := ezmqx.GetEZMQXEndPoint1(utils.ADDRESS, utils.PORT)
+	topic := ezmqx.GetEZMQXTopic(utils.TOPIC, utils.PROTO_MESSAGE_NAME, false, endPoint)
+	subscriber, _ := ezmqx.GetProtoStandAloneSubscriber(*topic, protoSubCB, protoErrorCB)
+	if nil == subscriber {
+		t.Errorf("subscriber is nil")
+	}
+	subscriber.Terminate()
+	topic = ezmqx.GetEZMQXTopic(utils.TOPIC, "ezmqx.sample.Unknown", false, endPoint)
+	_, result := ezmqx.GetProtoStandAloneSubscriber(*topic, protoSubCB, protoErrorCB)
+	if result != ezmqx.EZMQX_UNKNOWN_PROTO_MODEL {
+		t.Errorf("Get subscriber failed")
+	}
+	configInstance.Reset()
+}
+
+func TestProtoCodec(t *testing.T) {
+	configInstance := ezmqx.GetConfigInstance()
+	configInstance.StartStandAloneMode(utils.TEST_LOCAL_HOST, false, "")
+	addProtoModel(configInstance)
+	codec, result := ezmqx.GetProtoCodec(utils.PROTO_MESSAGE_NAME)
+	if result != ezmqx.EZMQX_OK || codec.GetDataModel() != utils.PROTO_MESSAGE_NAME {
+		t.Errorf("Get proto codec failed")
+	}
+	_, result = codec.Encode(&descriptorpb.FileDescriptorProto{})
+	if result != ezmqx.EZMQX_INVALID_PARAM {
+		t.Errorf("Encode of other message type succeeded")
+	}
+	message, result := codec.Decode(nil)
+	if result != ezmqx.EZMQX_OK || string(message.ProtoReflect().Descriptor().FullName()) != utils.PROTO_MESSAGE_NAME {
+		t.Errorf("Decode failed")
+	}
+	data, result := codec.Encode(message)
+	if result != ezmqx.EZMQX_OK || 0 != len(data) {
+		t.Errorf("Encode failed")
+	}
+	_, result = ezmqx.GetProtoCodec(utils.DATA_MODEL)
+	if result != ezmqx.EZMQX_UNKNOWN_PROTO_MODEL {
+		t.Errorf("Get codec of unknown message succeeded")
+	}
+	configInstance.Reset()
+}
@@ -18,9 +18,11 @@
 package ezmqx_unittests
 
 import (
+	"go/aml"
 	"go/ezmqx"
 	"go/ezmqx_unittests/utils"
 	"testing"
+	"time"
 
 	"container/list"
 )
@@ -204,6 +206,83 @@ func TestStandAlonePublishNegative(t *testing.T) {
 	}
 }
 
+func TestStandAlonePublishXML(t *testing.T) {
+	configInstance := ezmqx.GetConfigInstance()
+	configInstance.StartStandAloneMode(utils.TEST_LOCAL_HOST, false, "")
+	publisher, _ := ezmqx.GetAMLPublisher(utils.TOPIC, ezmqx.AML_FILE_PATH, utils.AML_FILE_PATH, utils.PORT)
+	representation, _ := aml.CreateRepresentation(utils.AML_FILE_PATH)
+	amlString, _ := representation.DataToAml(utils.GetAMLObject())
+	result := publisher.PublishXML(amlString)
+	if result != ezmqx.EZMQX_OK {
+		t.Errorf("publish xml failed")
+	}
+	result = publisher.PublishXML("<invalid")
+	if result != ezmqx.EZMQX_INVALID_PARAM {
+		t.Errorf("publish invalid xml failed")
+	}
+	publisher.Terminate()
+	configInstance.Reset()
+	result = publisher.PublishXML(amlString)
+	if result != ezmqx.EZMQX_TERMINATED {
+		t.Errorf("publish xml failed")
+	}
+}
+
+func TestStandAlonePublishAsync(t *testing.T) {
+	configInstance := ezmqx.GetConfigInstance()
+	configInstance.StartStandAloneMode(utils.TEST_LOCAL_HOST, false, "")
+	publisher, _ := ezmqx.GetAMLPublisher(utils.TOPIC, ezmqx.AML_FILE_PATH, utils.AML_FILE_PATH, utils.PORT)
+	results := make(chan ezmqx.EZMQXErrorCode, utils.NUMBER_OF_EVENTS)
+	callback := func(object *aml.AMLObject, errorCode ezmqx.EZMQXErrorCode) {
+		results <- errorCode
+	}
+	for i := 0; i < utils.NUMBER_OF_EVENTS; i++ {
+		result := publisher.PublishAsync(utils.GetAMLObject(), callback)
+		if result != ezmqx.EZMQX_OK {
+			t.Errorf("publish async failed")
+		}
+	}
+	for i := 0; i < utils.NUMBER_OF_EVENTS; i++ {
+		select {
+		case result := <-results:
+			if result != ezmqx.EZMQX_OK {
+				t.Errorf("async publish result is not OK")
+			}
+		case <-time.After(utils.LINGER):
+			t.Errorf("async publish callback is not called")
+		}
+	}
+	publisher.Terminate()
+	result := publisher.PublishAsync(utils.GetAMLObject(), nil)
+	if result != ezmqx.EZMQX_TERMINATED {
+		t.Errorf("publish async after terminate failed")
+	}
+	configInstance.Reset()
+}
+
+func TestPublishAsyncTerminateFromCallback(t *testing.T) {
+	configInstance := ezmqx.GetConfigInstance()
+	configInstance.StartStandAloneMode(utils.TEST_LOCAL_HOST, false, "")
+	publisher, _ := ezmqx.GetAMLPublisher(utils.TOPIC, ezmqx.AML_FILE_PATH, utils.AML_FILE_PATH, utils.PORT)
+	results := make(chan ezmqx.EZMQXErrorCode, 1)
+	callback := func(object *aml.AMLObject, errorCode ezmqx.EZMQXErrorCode) {
+		results <- publisher.Terminate()
+	}
+	result := publisher.PublishAsync(utils.GetAMLObject(), callback)
+	if result != ezmqx.EZMQX_OK {
+		t.Errorf("publish async failed")
+	}
+	select {
+	case result = <-results:
+		if result != ezmqx.EZMQX_OK {
+			t.Errorf("terminate from callback failed")
+		}
+	case <-time.After(utils.LINGER):
+		t.Errorf("terminate from callback is blocked")
+	}
+	configInstance.Reset()
+}
+
 func TestDockerModePublish(t *testing.T) {
 	configInstance := ezmqx.GetConfigInstance()
 	utils.Factory.SetFactory(utils.FakeRestClientFactory{})
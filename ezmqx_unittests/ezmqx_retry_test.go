@@ -0,0 +1,164 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmqx_unittests
+
+import (
+	"context"
+	"go/ezmqx"
+	"go/ezmqx_unittests/utils"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Starts TNS server which responds with 503 to first given number of requests.
+func startUnavailableTNSServer(unavailableCount int32, requestCount *int32) *httptest.Server {
+	return utils.StartTNSServer(func(tnsServer http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			if atomic.AddInt32(requestCount, 1) <= unavailableCount {
+				writer.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			tnsServer.ServeHTTP(writer, request)
+		})
+	}, nil)
+}
+
+func TestRetryPolicy(t *testing.T) {
+	var requestCount int32
+	server := startUnavailableTNSServer(utils.RETRY_COUNT, &requestCount)
+	defer server.Close()
+
+	configInstance := ezmqx.GetEZMQXConfig()
+	configInstance.SetRestClientFactory(ezmqx.RestClientFactory{})
+	result := configInstance.SetRetryPolicy(ezmqx.GetEZMQXRetryPolicy(utils.RETRY_COUNT, utils.RETRY_INITIAL_BACKOFF, utils.RETRY_MAX_BACKOFF))
+	if result != ezmqx.EZMQX_OK {
+		t.Fatalf("Set retry policy failed: %d", result)
+	}
+	defer configInstance.SetRetryPolicy(nil)
+	configInstance.StartStandAloneMode(utils.ADDRESS, true, server.URL)
+	defer configInstance.Reset()
+
+	topicDiscovery, _ := configInstance.GetEZMQXTopicDiscovery()
+	_, result = topicDiscovery.Query(utils.TNS_SERVER_TOPIC_NAME)
+	if result != ezmqx.EZMQX_OK {
+		t.Errorf("Query with retry failed: %d", result)
+	}
+	if atomic.LoadInt32(&requestCount) != utils.RETRY_COUNT+1 {
+		t.Errorf("Request count mismatch: %d", requestCount)
+	}
+	result = configInstance.SetRetryPolicy(nil)
+	if result != ezmqx.EZMQX_INITIALIZED {
+		t.Errorf("Set retry policy should fail after start: %d", result)
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	var requestCount int32
+	server := startUnavailableTNSServer(utils.CIRCUIT_FAILURE_THRESHOLD, &requestCount)
+	defer server.Close()
+
+	configInstance := ezmqx.GetEZMQXConfig()
+	configInstance.SetRestClientFactory(ezmqx.RestClientFactory{})
+	result := configInstance.SetCircuitBreaker(ezmqx.GetEZMQXCircuitBreaker(utils.CIRCUIT_FAILURE_THRESHOLD, utils.CIRCUIT_OPEN_DURATION))
+	if result != ezmqx.EZMQX_OK {
+		t.Fatalf("Set circuit breaker failed: %d", result)
+	}
+	defer configInstance.SetCircuitBreaker(nil)
+	configInstance.StartStandAloneMode(utils.ADDRESS, true, server.URL)
+	defer configInstance.Reset()
+
+	topicDiscovery, _ := configInstance.GetEZMQXTopicDiscovery()
+	for i := 0; i < utils.CIRCUIT_FAILURE_THRESHOLD; i++ {
+		_, result = topicDiscovery.Query(utils.TNS_SERVER_TOPIC_NAME)
+		if result != ezmqx.EZMQX_REST_ERROR {
+			t.Errorf("Query of unavailable TNS should fail: %d", result)
+		}
+	}
+	// Circuit is open, request is not sent
+	_, result = topicDiscovery.Query(utils.TNS_SERVER_TOPIC_NAME)
+	if result != ezmqx.EZMQX_TNS_NOT_AVAILABLE {
+		t.Errorf("Query with open circuit should fail fast: %d", result)
+	}
+	if atomic.LoadInt32(&requestCount) != utils.CIRCUIT_FAILURE_THRESHOLD {
+		t.Errorf("Request count mismatch: %d", requestCount)
+	}
+
+	// Circuit is closed after successful check of host
+	time.Sleep(utils.CIRCUIT_OPEN_DURATION)
+	_, result = topicDiscovery.Query(utils.TNS_SERVER_TOPIC_NAME)
+	if result != ezmqx.EZMQX_OK {
+		t.Errorf("Query after open duration failed: %d", result)
+	}
+	_, result = topicDiscovery.Query(utils.TNS_SERVER_TOPIC_NAME)
+	if result != ezmqx.EZMQX_OK {
+		t.Errorf("Query with closed circuit failed: %d", result)
+	}
+	result = configInstance.SetCircuitBreaker(nil)
+	if result != ezmqx.EZMQX_INITIALIZED {
+		t.Errorf("Set circuit breaker should fail after start: %d", result)
+	}
+}
+
+func TestCircuitBreakerCanceledRetry(t *testing.T) {
+	var requestCount int32
+	server := startUnavailableTNSServer(utils.CIRCUIT_FAILURE_THRESHOLD, &requestCount)
+	defer server.Close()
+
+	configInstance := ezmqx.GetEZMQXConfig()
+	configInstance.SetRestClientFactory(ezmqx.RestClientFactory{})
+	configInstance.SetRetryPolicy(ezmqx.GetEZMQXRetryPolicy(utils.RETRY_COUNT, utils.RETRY_CANCEL_BACKOFF, utils.RETRY_CANCEL_BACKOFF))
+	configInstance.SetCircuitBreaker(ezmqx.GetEZMQXCircuitBreaker(utils.CIRCUIT_FAILURE_THRESHOLD, utils.CIRCUIT_OPEN_DURATION))
+	client := configInstance.GetTNSClient(server.URL)
+
+	// Requests are canceled during backoff, failures before cancel do not open circuit
+	for i := 0; i < utils.CIRCUIT_FAILURE_THRESHOLD; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), utils.RETRY_MAX_BACKOFF)
+		_, err := client.Query(ctx, utils.TNS_SERVER_TOPIC_NAME)
+		cancel()
+		if ezmqx.GetErrorCode(err) != ezmqx.EZMQX_CANCELED {
+			t.Errorf("Query should be canceled: %v", err)
+		}
+	}
+	_, err := client.Query(context.Background(), utils.TNS_SERVER_TOPIC_NAME)
+	if err != nil {
+		t.Errorf("Query after canceled retries failed: %v", err)
+	}
+}
+
+func TestRetryPolicyNegative(t *testing.T) {
+	configInstance := ezmqx.GetEZMQXConfig()
+	result := configInstance.SetRetryPolicy(ezmqx.GetEZMQXRetryPolicy(-1, utils.RETRY_INITIAL_BACKOFF, utils.RETRY_MAX_BACKOFF))
+	if result != ezmqx.EZMQX_INVALID_PARAM {
+		t.Errorf("Negative retry count should fail: %d", result)
+	}
+	result = configInstance.SetRetryPolicy(ezmqx.GetEZMQXRetryPolicy(utils.RETRY_COUNT, 0, utils.RETRY_MAX_BACKOFF))
+	if result != ezmqx.EZMQX_INVALID_PARAM {
+		t.Errorf("Zero backoff should fail: %d", result)
+	}
+	result = configInstance.SetRetryPolicy(ezmqx.GetEZMQXRetryPolicy(utils.RETRY_COUNT, utils.RETRY_MAX_BACKOFF, utils.RETRY_INITIAL_BACKOFF))
+	if result != ezmqx.EZMQX_INVALID_PARAM {
+		t.Errorf("Maximum backoff less than initial backoff should fail: %d", result)
+	}
+	result = configInstance.SetCircuitBreaker(ezmqx.GetEZMQXCircuitBreaker(0, utils.CIRCUIT_OPEN_DURATION))
+	if result != ezmqx.EZMQX_INVALID_PARAM {
+		t.Errorf("Zero failure threshold should fail: %d", result)
+	}
+}
@@ -0,0 +1,145 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmqx_unittests
+
+import (
+	"go/ezmqx"
+	"go/ezmqx_unittests/utils"
+	"testing"
+)
+
+func TestSetTLSConfig(t *testing.T) {
+	certificates, err := utils.CreateTestCertificates(t.TempDir())
+	if err != nil {
+		t.Fatalf("Create certificates failed: %v", err)
+	}
+	server := utils.StartTNSServer(nil, certificates.ServerTLSConfig)
+	defer server.Close()
+
+	configInstance := ezmqx.GetEZMQXConfig()
+	configInstance.SetRestClientFactory(ezmqx.RestClientFactory{})
+	tlsConfig := ezmqx.GetEZMQXTLSConfig(certificates.CAFile, certificates.ClientCertFile, certificates.ClientKeyFile, utils.TLS_SERVER_NAME)
+	result := configInstance.SetTLSConfig(ezmqx.REST_ENDPOINT_TNS, tlsConfig)
+	if result != ezmqx.EZMQX_OK {
+		t.Fatalf("Set TLS config failed: %d", result)
+	}
+	// http TNS address is changed to https
+	configInstance.StartStandAloneMode(utils.ADDRESS, true, "http://"+server.Listener.Addr().String())
+	defer configInstance.Reset()
+
+	topicDiscovery, _ := configInstance.GetEZMQXTopicDiscovery()
+	topic, result := topicDiscovery.Query(utils.TNS_SERVER_TOPIC_NAME)
+	if result != ezmqx.EZMQX_OK || topic.GetName() != utils.TNS_SERVER_TOPIC_NAME {
+		t.Errorf("Query over TLS failed: %d", result)
+	}
+	result = configInstance.SetTLSConfig(ezmqx.REST_ENDPOINT_TNS, nil)
+	if result != ezmqx.EZMQX_INITIALIZED {
+		t.Errorf("Set TLS config should fail after start: %d", result)
+	}
+}
+
+func TestSetTLSConfigAfterReset(t *testing.T) {
+	certificates, err := utils.CreateTestCertificates(t.TempDir())
+	if err != nil {
+		t.Fatalf("Create certificates failed: %v", err)
+	}
+	server := utils.StartTNSServer(nil, certificates.ServerTLSConfig)
+	defer server.Close()
+
+	configInstance := ezmqx.GetEZMQXConfig()
+	configInstance.SetRestClientFactory(ezmqx.RestClientFactory{})
+	tlsConfig := ezmqx.GetEZMQXTLSConfig(certificates.CAFile, certificates.ClientCertFile, certificates.ClientKeyFile, utils.TLS_SERVER_NAME)
+	configInstance.SetTLSConfig(ezmqx.REST_ENDPOINT_TNS, tlsConfig)
+	// TLS configuration is kept and connections are made again after reset
+	for i := 0; i < 2; i++ {
+		configInstance.StartStandAloneMode(utils.ADDRESS, true, "https://"+server.Listener.Addr().String())
+		topicDiscovery, _ := configInstance.GetEZMQXTopicDiscovery()
+		_, result := topicDiscovery.Query(utils.TNS_SERVER_TOPIC_NAME)
+		if result != ezmqx.EZMQX_OK {
+			t.Errorf("Query over TLS failed: %d", result)
+		}
+		configInstance.Reset()
+	}
+}
+
+func TestSetTLSConfigWithoutClientCertificate(t *testing.T) {
+	certificates, err := utils.CreateTestCertificates(t.TempDir())
+	if err != nil {
+		t.Fatalf("Create certificates failed: %v", err)
+	}
+	server := utils.StartTNSServer(nil, certificates.ServerTLSConfig)
+	defer server.Close()
+
+	configInstance := ezmqx.GetEZMQXConfig()
+	configInstance.SetRestClientFactory(ezmqx.RestClientFactory{})
+	configInstance.SetTLSConfig(ezmqx.REST_ENDPOINT_TNS, ezmqx.GetEZMQXTLSConfig(certificates.CAFile, "", "", utils.TLS_SERVER_NAME))
+	configInstance.StartStandAloneMode(utils.ADDRESS, true, "https://"+server.Listener.Addr().String())
+	defer configInstance.Reset()
+
+	topicDiscovery, _ := configInstance.GetEZMQXTopicDiscovery()
+	_, result := topicDiscovery.Query(utils.TNS_SERVER_TOPIC_NAME)
+	if result != ezmqx.EZMQX_REST_ERROR {
+		t.Errorf("Query without client certificate should fail: %d", result)
+	}
+}
+
+func TestSetTLSConfigNegative(t *testing.T) {
+	configInstance := ezmqx.GetEZMQXConfig()
+	tlsConfig := ezmqx.GetEZMQXTLSConfig("/invalid/ca.pem", "", "", "")
+	result := configInstance.SetTLSConfig(ezmqx.REST_ENDPOINT_NODE, tlsConfig)
+	if result != ezmqx.EZMQX_INVALID_PARAM {
+		t.Errorf("Set TLS config with invalid CA file should fail: %d", result)
+	}
+	tlsConfig = ezmqx.GetEZMQXTLSConfig("", utils.TNS_SERVER_ADDRESS, "", "")
+	result = configInstance.SetTLSConfig(ezmqx.REST_ENDPOINT_ANCHOR, tlsConfig)
+	if result != ezmqx.EZMQX_INVALID_PARAM {
+		t.Errorf("Set TLS config with invalid client certificate should fail: %d", result)
+	}
+	result = configInstance.SetTLSConfig(ezmqx.EZMQXRestEndpoint(10), nil)
+	if result != ezmqx.EZMQX_INVALID_PARAM {
+		t.Errorf("Set TLS config with invalid endpoint should fail: %d", result)
+	}
+}
+
+func TestSetTLSConfigWithCustomFactory(t *testing.T) {
+	tlsConfig := ezmqx.GetEZMQXTLSConfig("", "", "", utils.TLS_SERVER_NAME)
+	configInstance := ezmqx.GetEZMQXConfig()
+	configInstance.SetRestClientFactory(utils.FakeRestClientFactory{})
+	result := configInstance.SetTLSConfig(ezmqx.REST_ENDPOINT_TNS, tlsConfig)
+	if result != ezmqx.EZMQX_INVALID_PARAM {
+		t.Errorf("Set TLS config with non TLS factory should fail: %d", result)
+	}
+	result = configInstance.SetTLSConfig(ezmqx.REST_ENDPOINT_TNS, nil)
+	if result != ezmqx.EZMQX_OK {
+		t.Errorf("Disable TLS with non TLS factory failed: %d", result)
+	}
+
+	configInstance = ezmqx.GetEZMQXConfig()
+	result = configInstance.SetTLSConfig(ezmqx.REST_ENDPOINT_TNS, tlsConfig)
+	if result != ezmqx.EZMQX_OK {
+		t.Fatalf("Set TLS config failed: %d", result)
+	}
+	result = configInstance.SetRestClientFactory(utils.FakeRestClientFactory{})
+	if result != ezmqx.EZMQX_INVALID_PARAM {
+		t.Errorf("Set non TLS factory with TLS config should fail: %d", result)
+	}
+	result = configInstance.SetRestClientFactory(ezmqx.RestClientFactory{})
+	if result != ezmqx.EZMQX_OK {
+		t.Errorf("Set TLS factory failed: %d", result)
+	}
+}
@@ -0,0 +1,129 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmqx_unittests
+
+import (
+	"context"
+	"go/ezmqx"
+	"go/ezmqx_tns"
+	"go/ezmqx_unittests/utils"
+	"testing"
+)
+
+func TestTNSClient(t *testing.T) {
+	server := ezmqx_tns.GetTNSServer(utils.TNS_SERVER_ADDRESS, 1)
+	err := server.Start()
+	if err != nil {
+		t.Fatalf("Start TNS server failed: %v", err)
+	}
+	defer server.Stop()
+	utils.Factory.SetFactory(ezmqx.RestClientFactory{})
+	defer utils.Factory.SetFactory(utils.FakeRestClientFactory{})
+	client := ezmqx.GetTNSClient(server.GetTNSAddress())
+	ctx := context.Background()
+
+	topic := ezmqx.EZMQXTNSTopic{Name: utils.TOPIC, EndPoint: utils.IP_PORT, DataModel: utils.DATA_MODEL, Compression: ezmqx.COMPRESSION_GZIP_NAME}
+	response, err := client.Register(ctx, ezmqx.EZMQXTNSRegisterRequest{Topic: topic})
+	if err != nil || response.KeepAliveInterval != 1 {
+		t.Errorf("Register failed: %v", err)
+	}
+	_, err = client.Register(ctx, ezmqx.EZMQXTNSRegisterRequest{Topic: topic})
+	if ezmqx.GetErrorCode(err) != ezmqx.EZMQX_DUPLICATED_TOPIC {
+		t.Errorf("Duplicate register should fail: %v", err)
+	}
+
+	topics, err := client.Query(ctx, utils.TOPIC)
+	if err != nil || len(topics) != 1 || topics[0] != topic {
+		t.Errorf("Query failed: %v %v", err, topics)
+	}
+	ezmqxTopic, err := topics[0].ToEZMQXTopic()
+	if err != nil || ezmqxTopic.GetCompression() != ezmqx.COMPRESSION_GZIP || ezmqxTopic.GetEndPoint().ToString() != utils.IP_PORT {
+		t.Errorf("To EZMQX topic failed: %v", err)
+	}
+	topics, err = client.HierarchicalQuery(ctx, utils.TOPIC)
+	if err != nil || len(topics) != 1 {
+		t.Errorf("Hierarchical query failed: %v", err)
+	}
+
+	err = client.KeepAlive(ctx, ezmqx.EZMQXTNSKeepAliveRequest{TopicNames: []string{utils.TOPIC}})
+	if err != nil {
+		t.Errorf("Keep alive failed: %v", err)
+	}
+	err = client.KeepAlive(ctx, ezmqx.EZMQXTNSKeepAliveRequest{TopicNames: []string{utils.TOPIC + "/unknown"}})
+	if ezmqx.GetErrorCode(err) != ezmqx.EZMQX_UNKNOWN_TOPIC {
+		t.Errorf("Keep alive of unknown topic should fail: %v", err)
+	}
+
+	err = client.Unregister(ctx, utils.TOPIC)
+	if err != nil {
+		t.Errorf("Unregister failed: %v", err)
+	}
+	_, err = client.Query(ctx, utils.TOPIC)
+	if ezmqx.GetErrorCode(err) != ezmqx.EZMQX_UNKNOWN_TOPIC {
+		t.Errorf("Query of unregistered topic should fail: %v", err)
+	}
+}
+
+func TestTNSClientInvalidResponse(t *testing.T) {
+	utils.Factory.SetFactory(utils.FakeRestClientFactory{})
+	utils.SetRestResponse(utils.TOPIC_DISCOVERY_URL, []byte(utils.INVALID_TOPIC_DISCOVERY_RESPONSE))
+	client := ezmqx.GetTNSClient(utils.TNS_ADDRESS)
+	_, err := client.Query(context.Background(), utils.TOPIC)
+	if ezmqx.GetErrorCode(err) != ezmqx.EZMQX_REST_ERROR {
+		t.Errorf("Query with invalid response should fail: %v", err)
+	}
+	utils.SetRestResponse(utils.TOPIC_DISCOVERY_URL, []byte(`{ "topics": [ {"name": "/topic", "endpoint": "localhost:5562", "secured": false } ] }`))
+	_, err = client.Query(context.Background(), utils.TOPIC)
+	if ezmqx.GetErrorCode(err) != ezmqx.EZMQX_REST_ERROR {
+		t.Errorf("Query without data model should fail: %v", err)
+	}
+}
+
+func TestTNSClientWithConfig(t *testing.T) {
+	server := ezmqx_tns.GetTNSServer(utils.TNS_SERVER_ADDRESS, 1)
+	err := server.Start()
+	if err != nil {
+		t.Fatalf("Start TNS server failed: %v", err)
+	}
+	defer server.Stop()
+	configInstance := ezmqx.GetEZMQXConfig()
+	client := configInstance.GetTNSClient(server.GetTNSAddress())
+	if client.GetTNSAddress() != server.GetTNSAddress() {
+		t.Errorf("TNS address mismatch")
+	}
+	ctx := context.Background()
+
+	topic := ezmqx.EZMQXTNSTopic{Name: utils.TOPIC, EndPoint: utils.IP_PORT, DataModel: utils.DATA_MODEL}
+	_, err = client.Register(ctx, ezmqx.EZMQXTNSRegisterRequest{Topic: topic})
+	if err != nil {
+		t.Errorf("Register failed: %v", err)
+	}
+	// '#' should be escaped, not cut the query to given topic
+	topics, err := client.Query(ctx, utils.TOPIC+"#fragment")
+	if err == nil || len(topics) != 0 {
+		t.Errorf("Query of topic with '#' should fail: %v", topics)
+	}
+	err = client.Unregister(ctx, utils.TOPIC+"#fragment")
+	if err == nil {
+		t.Errorf("Unregister of topic with '#' should fail")
+	}
+	err = client.Unregister(ctx, utils.TOPIC)
+	if err != nil {
+		t.Errorf("Unregister failed: %v", err)
+	}
+}
@@ -0,0 +1,198 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package ezmqx_unittests
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/ezmqx"
+	"go/ezmqx_tns"
+	"go/ezmqx_unittests/utils"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func postTNS(t *testing.T, url string, payload string) *http.Response {
+	response, err := http.Post(url, "application/json", bytes.NewBufferString(payload))
+	if err != nil {
+		t.Fatalf("Post request failed: %v", err)
+	}
+	response.Body.Close()
+	return response
+}
+
+func getTNSTopics(t *testing.T, url string) (int, []interface{}) {
+	response, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("Get request failed: %v", err)
+	}
+	defer response.Body.Close()
+	result := make(map[string][]interface{})
+	json.NewDecoder(response.Body).Decode(&result)
+	return response.StatusCode, result["topics"]
+}
+
+func TestTNSServer(t *testing.T) {
+	server := ezmqx_tns.GetTNSServer(utils.TNS_SERVER_ADDRESS, 1)
+	err := server.Start()
+	if err != nil {
+		t.Fatalf("Start TNS server failed: %v", err)
+	}
+	defer server.Stop()
+	topicURL := server.GetTNSAddress() + utils.TNS_SERVER_TOPIC
+	keepAliveURL := server.GetTNSAddress() + utils.TNS_SERVER_KEEP_ALIVE
+
+	response := postTNS(t, topicURL, utils.TNS_SERVER_REGISTER_PAYLOAD)
+	if response.StatusCode != http.StatusCreated {
+		t.Errorf("Register topic failed: %d", response.StatusCode)
+	}
+	response = postTNS(t, topicURL, utils.TNS_SERVER_REGISTER_PAYLOAD)
+	if response.StatusCode != http.StatusConflict {
+		t.Errorf("Duplicate topic registered: %d", response.StatusCode)
+	}
+	response = postTNS(t, topicURL, `{"topic":{"name":"/topic"}}`)
+	if response.StatusCode != http.StatusBadRequest {
+		t.Errorf("Invalid topic registered: %d", response.StatusCode)
+	}
+	response = postTNS(t, topicURL, utils.TNS_SERVER_INVALID_REGISTER_PAYLOAD)
+	if response.StatusCode != http.StatusBadRequest {
+		t.Errorf("Topic with invalid character registered: %d", response.StatusCode)
+	}
+	statusCode, _ := getTNSTopics(t, topicURL+"?name=/sensor/..")
+	if statusCode != http.StatusBadRequest {
+		t.Errorf("Query with reserved segment should fail: %d", statusCode)
+	}
+
+	statusCode, topics := getTNSTopics(t, topicURL+"?name=/sensor&hierarchical=yes")
+	if statusCode != http.StatusOK || len(topics) != 1 {
+		t.Errorf("Hierarchical query failed: %d %v", statusCode, topics)
+	}
+	statusCode, _ = getTNSTopics(t, topicURL+"?name=/sensor&hierarchical=no")
+	if statusCode != http.StatusNotFound {
+		t.Errorf("Query should fail: %d", statusCode)
+	}
+
+	response = postTNS(t, keepAliveURL, `{"topic_names":["/sensor/temperature"]}`)
+	if response.StatusCode != http.StatusOK {
+		t.Errorf("Keep alive failed: %d", response.StatusCode)
+	}
+	response = postTNS(t, keepAliveURL, `{"topic_names":["/sensor/humidity"]}`)
+	if response.StatusCode != http.StatusNotFound {
+		t.Errorf("Keep alive of unknown topic should fail: %d", response.StatusCode)
+	}
+
+	request, _ := http.NewRequest(http.MethodDelete, topicURL+"?name=/sensor/temperature", nil)
+	response, err = http.DefaultClient.Do(request)
+	if err != nil || response.StatusCode != http.StatusOK {
+		t.Errorf("Unregister topic failed")
+	}
+	if server.GetTopicCount() != 0 {
+		t.Errorf("Topic count mismatch")
+	}
+}
+
+func TestTNSServerKeepAliveExpiry(t *testing.T) {
+	server := ezmqx_tns.GetTNSServer(utils.TNS_SERVER_ADDRESS, 1)
+	server.SetKeepAliveTimeout(utils.TNS_SERVER_KEEP_ALIVE_TIMEOUT)
+	err := server.Start()
+	if err != nil {
+		t.Fatalf("Start TNS server failed: %v", err)
+	}
+	defer server.Stop()
+	postTNS(t, server.GetTNSAddress()+utils.TNS_SERVER_TOPIC, utils.TNS_SERVER_REGISTER_PAYLOAD)
+	if server.GetTopicCount() != 1 {
+		t.Errorf("Topic count mismatch")
+	}
+	time.Sleep(2 * utils.TNS_SERVER_KEEP_ALIVE_TIMEOUT)
+	if server.GetTopicCount() != 0 {
+		t.Errorf("Topic not expired")
+	}
+}
+
+func TestTNSServerWithPublisher(t *testing.T) {
+	server := ezmqx_tns.GetTNSServer(utils.TNS_SERVER_ADDRESS, 1)
+	err := server.Start()
+	if err != nil {
+		t.Fatalf("Start TNS server failed: %v", err)
+	}
+	defer server.Stop()
+	utils.Factory.SetFactory(ezmqx.RestClientFactory{})
+	defer utils.Factory.SetFactory(utils.FakeRestClientFactory{})
+	configInstance := ezmqx.GetConfigInstance()
+	configInstance.StartStandAloneMode(utils.ADDRESS, true, server.GetTNSAddress())
+	defer configInstance.Reset()
+
+	publisher, result := ezmqx.GetBytePublisher(utils.TOPIC, utils.BYTE_DATA_MODEL, utils.PORT)
+	if result != ezmqx.EZMQX_OK {
+		t.Fatalf("Get publisher failed: %d", result)
+	}
+	topicDiscovery, _ := ezmqx.GetEZMQXTopicDiscovery()
+	topic, result := topicDiscovery.Query(utils.TOPIC)
+	if result != ezmqx.EZMQX_OK || topic.GetDataModel() != utils.BYTE_DATA_MODEL {
+		t.Errorf("Query failed: %d", result)
+	}
+	publisher.Terminate()
+	if server.GetTopicCount() != 0 {
+		t.Errorf("Topic not unregistered")
+	}
+}
+
+func TestTNSServerReRegistration(t *testing.T) {
+	server := ezmqx_tns.GetTNSServer(utils.TNS_SERVER_ADDRESS, 1)
+	err := server.Start()
+	if err != nil {
+		t.Fatalf("Start TNS server failed: %v", err)
+	}
+	defer server.Stop()
+	utils.Factory.SetFactory(ezmqx.RestClientFactory{})
+	defer utils.Factory.SetFactory(utils.FakeRestClientFactory{})
+	configInstance := ezmqx.GetConfigInstance()
+	configInstance.StartStandAloneMode(utils.ADDRESS, true, server.GetTNSAddress())
+	defer configInstance.Reset()
+	states := make(chan ezmqx.EZMQXRegistrationState, utils.NUMBER_OF_EVENTS)
+	configInstance.SetRegistrationCallback(func(topic string, state ezmqx.EZMQXRegistrationState) {
+		states <- state
+	})
+
+	publisher, result := ezmqx.GetBytePublisher(utils.TOPIC, utils.BYTE_DATA_MODEL, utils.PORT)
+	if result != ezmqx.EZMQX_OK {
+		t.Fatalf("Get publisher failed: %d", result)
+	}
+	defer publisher.Terminate()
+	// TNS forgets the topic [e.g. restarted], so keep alive fails with 404
+	request, _ := http.NewRequest(http.MethodDelete, server.GetTNSAddress()+utils.TNS_SERVER_TOPIC+"?name="+utils.TOPIC, nil)
+	response, err := http.DefaultClient.Do(request)
+	if err != nil || response.StatusCode != http.StatusOK {
+		t.Fatalf("Delete topic from TNS failed")
+	}
+	response.Body.Close()
+	for _, expected := range []ezmqx.EZMQXRegistrationState{ezmqx.TOPIC_UNREGISTERED, ezmqx.TOPIC_REGISTERED} {
+		select {
+		case state := <-states:
+			if state != expected {
+				t.Errorf("Registration state mismatch: %d", state)
+			}
+		case <-time.After(utils.TNS_RE_REGISTRATION_TIMEOUT):
+			t.Fatalf("Registration state is not notified: %d", expected)
+		}
+	}
+	if server.GetTopicCount() != 1 {
+		t.Errorf("Topic not re-registered")
+	}
+}
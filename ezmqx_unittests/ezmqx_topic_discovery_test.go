@@ -18,8 +18,11 @@
 package ezmqx_unittests
 
 import (
+	"context"
+	"errors"
 	"go/ezmqx"
 	"go/ezmqx_unittests/utils"
+	"strings"
 	"testing"
 )
 
@@ -56,6 +59,32 @@ func TestQuery(t *testing.T) {
 	configInstance.Reset()
 }
 
+func TestQueryWithContext(t *testing.T) {
+	configInstance := ezmqx.GetConfigInstance()
+	configInstance.StartStandAloneMode(utils.ADDRESS, true, utils.TNS_ADDRESS)
+	topicDiscovery, _ := ezmqx.GetEZMQXTopicDiscovery()
+
+	//Set fake rest client
+	utils.Factory.SetFactory(utils.FakeRestClientFactory{})
+	utils.SetRestResponse(utils.TOPIC_DISCOVERY_URL, []byte(utils.VALID_TOPIC_DISCOVERY_RESPONSE))
+
+	_, err := topicDiscovery.QueryWithContext(context.Background(), utils.TOPIC)
+	if err != nil {
+		t.Errorf("Error EZMQX topic query failed")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = topicDiscovery.QueryWithContext(ctx, utils.TOPIC)
+	if ezmqx.GetErrorCode(err) != ezmqx.EZMQX_CANCELED {
+		t.Errorf("Error EZMQX topic query is not canceled")
+	}
+	_, err = topicDiscovery.HierarchicalQueryWithContext(ctx, utils.TOPIC)
+	if ezmqx.GetErrorCode(err) != ezmqx.EZMQX_CANCELED {
+		t.Errorf("Error EZMQX hierarchical query is not canceled")
+	}
+	configInstance.Reset()
+}
+
 func TestQueryNegative(t *testing.T) {
 	configInstance := ezmqx.GetConfigInstance()
 	configInstance.StartStandAloneMode(utils.ADDRESS, true, utils.TNS_ADDRESS)
@@ -269,3 +298,102 @@ func TestQueryTopicValidation(t *testing.T) {
 
 	configInstance.Reset()
 }
+
+func TestQueryError(t *testing.T) {
+	configInstance := ezmqx.GetConfigInstance()
+	configInstance.StartStandAloneMode(utils.ADDRESS, true, utils.TNS_ADDRESS)
+	topicDiscovery, _ := ezmqx.GetEZMQXTopicDiscovery()
+
+	//Set fake rest client
+	utils.Factory.SetFactory(utils.FakeRestClientFactory{})
+	utils.SetRestResponse(utils.TOPIC_DISCOVERY_URL, []byte(utils.VALID_TOPIC_DISCOVERY_RESPONSE))
+
+	_, err := topicDiscovery.QueryWithContext(context.Background(), utils.TOPIC)
+	if err != nil {
+		t.Errorf("Error EZMQX topic query failed: %v", err)
+	}
+	utils.SetRestResponse(utils.TOPIC_DISCOVERY_URL, []byte(utils.INVALID_TOPIC_DISCOVERY_RESPONSE))
+	_, err = topicDiscovery.QueryWithContext(context.Background(), utils.TOPIC)
+	if !errors.Is(err, ezmqx.EZMQXErrorCode(ezmqx.EZMQX_REST_ERROR)) {
+		t.Errorf("Error is not EZMQX_REST_ERROR")
+	}
+	if nil == err || !strings.Contains(err.Error(), "parseTNSResponse") {
+		t.Errorf("Error does not contain failed operation")
+	}
+	_, err = topicDiscovery.HierarchicalQueryWithContext(context.Background(), "invalid topic")
+	if !errors.Is(err, ezmqx.EZMQXErrorCode(ezmqx.EZMQX_INVALID_TOPIC)) {
+		t.Errorf("Error is not EZMQX_INVALID_TOPIC")
+	}
+	configInstance.Reset()
+}
+
+func TestWatch(t *testing.T) {
+	configInstance := ezmqx.GetConfigInstance()
+	configInstance.StartStandAloneMode(utils.ADDRESS, true, utils.TNS_ADDRESS)
+	topicDiscovery, _ := ezmqx.GetEZMQXTopicDiscovery()
+
+	//Set fake rest client
+	utils.Factory.SetFactory(utils.FakeRestClientFactory{})
+	utils.SetRestResponse(utils.TOPIC_DISCOVERY_H_URL, []byte(utils.VALID_TOPIC_DISCOVERY_RESPONSE))
+
+	_, result := topicDiscovery.Watch(utils.TOPIC, 0)
+	if result != ezmqx.EZMQX_INVALID_PARAM {
+		t.Errorf("Error EZMQX topic watch [Invalid interval]")
+	}
+	watch, result := topicDiscovery.Watch(utils.TOPIC, utils.REFRESH_INTERVAL)
+	if result != ezmqx.EZMQX_OK {
+		t.Errorf("Error EZMQX topic watch failed")
+		configInstance.Reset()
+		return
+	}
+	event := <-watch.GetEvents()
+	topic := event.GetTopic()
+	if event.GetChange() != ezmqx.TOPIC_ADDED || topic.GetName() != "topicName" {
+		t.Errorf("Error EZMQX topic watch [Added]")
+	}
+	utils.SetRestResponse(utils.TOPIC_DISCOVERY_H_URL, []byte(utils.EMPTY_TOPIC_DISCOVERY_RESPONSE))
+	event = <-watch.GetEvents()
+	if event.GetChange() != ezmqx.TOPIC_REMOVED {
+		t.Errorf("Error EZMQX topic watch [Removed]")
+	}
+	watch.Stop()
+	_, open := <-watch.GetEvents()
+	if open {
+		t.Errorf("Error EZMQX topic watch channel is not closed")
+	}
+	configInstance.Reset()
+}
+
+func TestHierarchicalQueryPattern(t *testing.T) {
+	configInstance := ezmqx.GetConfigInstance()
+	configInstance.StartStandAloneMode(utils.ADDRESS, true, utils.TNS_ADDRESS)
+	topicDiscovery, _ := ezmqx.GetEZMQXTopicDiscovery()
+
+	//Set fake rest client
+	utils.Factory.SetFactory(utils.FakeRestClientFactory{})
+	utils.SetRestResponse(utils.TOPIC_DISCOVERY_H_URL, []byte(utils.PATTERN_TOPIC_DISCOVERY_RESPONSE))
+
+	patterns := map[string]int{utils.TOPIC + "/*": 2, utils.TOPIC + "/#": 3, utils.TOPIC + "/*/b": 1}
+	for pattern, count := range patterns {
+		topics, result := topicDiscovery.HierarchicalQuery(pattern)
+		if result != ezmqx.EZMQX_OK || topics.Len() != count {
+			t.Errorf("Error EZMQX topic query [%s] failed", pattern)
+		}
+	}
+	_, result := topicDiscovery.HierarchicalQuery(utils.TOPIC + "/*/c")
+	if result != ezmqx.EZMQX_NO_TOPIC_MATCHED {
+		t.Errorf("Error EZMQX topic query [No match]")
+	}
+	invalidPatterns := []string{"/*", "/#", utils.TOPIC + "/#/a", utils.TOPIC + "/a*"}
+	for _, pattern := range invalidPatterns {
+		_, result = topicDiscovery.HierarchicalQuery(pattern)
+		if result != ezmqx.EZMQX_INVALID_TOPIC {
+			t.Errorf("Error EZMQX topic query [%s] is not invalid", pattern)
+		}
+	}
+	_, result = topicDiscovery.Query(utils.TOPIC + "/*")
+	if result != ezmqx.EZMQX_INVALID_TOPIC {
+		t.Errorf("Error EZMQX topic query [Pattern without hierarchical]")
+	}
+	configInstance.Reset()
+}
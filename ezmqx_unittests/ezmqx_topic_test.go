@@ -20,6 +20,7 @@ package ezmqx_unittests
 import (
 	"go/ezmqx"
 	"go/ezmqx_unittests/utils"
+	"strings"
 	"testing"
 )
 
@@ -79,3 +80,47 @@ func TestGetEndPoint(t *testing.T) {
 		t.Errorf("Error Address mismatch")
 	}
 }
+
+func TestParseTopicName(t *testing.T) {
+	topicName, err := ezmqx.ParseTopicName("  /Robot/Arm/Sensor_1//  ")
+	if err != nil {
+		t.Errorf("Error parse topic name failed: %v", err)
+		return
+	}
+	if topicName.GetName() != "/Robot/Arm/Sensor_1" {
+		t.Errorf("Error topic name is not normalized: %s", topicName.GetName())
+	}
+	segments := topicName.GetSegments()
+	if len(segments) != 3 || segments[0] != "Robot" || segments[1] != "Arm" || segments[2] != "Sensor_1" {
+		t.Errorf("Error segments mismatch: %v", segments)
+	}
+	parent := topicName.GetParent()
+	if nil == parent || parent.GetName() != "/Robot/Arm" || !parent.IsAncestorOf(topicName) {
+		t.Errorf("Error parent topic name mismatch")
+	}
+	if topicName.IsAncestorOf(parent) {
+		t.Errorf("Error topic name is not ancestor of parent")
+	}
+}
+
+func TestParseTopicNameNegative(t *testing.T) {
+	invalidNames := map[string]string{
+		"":                             "empty",
+		"Robot/Arm":                    "does not start with '/'",
+		"/Robot//Arm":                  "empty segment at level 2",
+		"/Robot/../Arm":                "reserved",
+		"/Robot/Ärm":                   "non ASCII",
+		"/Robot/Arm+1":                 "invalid character '+' at index 3",
+		"/" + strings.Repeat("a", 300): "exceeds",
+	}
+	for name, reason := range invalidNames {
+		_, err := ezmqx.ParseTopicName(name)
+		if ezmqx.GetErrorCode(err) != ezmqx.EZMQX_INVALID_TOPIC {
+			t.Errorf("Error invalid topic name accepted: %s", name)
+			continue
+		}
+		if !strings.Contains(err.Error(), reason) {
+			t.Errorf("Error reason mismatch for %s: %v", name, err)
+		}
+	}
+}
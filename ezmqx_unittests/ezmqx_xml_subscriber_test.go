@@ -323,3 +323,28 @@ func TestXGetTopics(t *testing.T) {
 	subscriber.Terminate()
 	configInstance.Reset()
 }
+
+func TestXMLChannelSubscriber(t *testing.T) {
+	configInstance := ezmqx.GetConfigInstance()
+	configInstance.StartStandAloneMode(utils.TEST_LOCAL_HOST, false, "")
+	amlFilePath := list.New()
+	amlFilePath.PushBack(utils.AML_FILE_PATH)
+	idList, _ := configInstance.AddAmlModel(*amlFilePath)
+	endPoint := ezmqx.GetEZMQXEndPoint1(utils.ADDRESS, utils.PORT)
+	topic := ezmqx.GetEZMQXTopic(utils.TOPIC, idList.Front().Value.(string), false, endPoint)
+	topicList := list.New()
+	topicList.PushBack(*topic)
+	subscriber, messages, result := ezmqx.GetXMLStandAloneChannelSubscriber(*topicList, utils.QUEUE_SIZE, ezmqx.OVERFLOW_BLOCK)
+	if result != ezmqx.EZMQX_OK {
+		t.Errorf("Get subscriber failed")
+	}
+	result = subscriber.Terminate()
+	if result != ezmqx.EZMQX_OK {
+		t.Errorf("Termination failed")
+	}
+	_, open := <-messages
+	if open {
+		t.Errorf("Channel is not closed")
+	}
+	configInstance.Reset()
+}
@@ -31,12 +31,21 @@ const TEST_LOCAL_HOST = "localhost"
 const TNS_ADDRESS = "http://192.168.0.1:80/tns-server"
 
 const PORT = 5562
+const SECOND_PORT = 5563
 const IP_PORT = "127.0.0.1:5562"
 const TOPIC = "/topic"
 const DATA_MODEL = "Robot_1.1"
+const BYTE_DATA_MODEL = "raw_telemetry_1.0"
+const LAST_VALUE_DEPTH = 3
+const QUEUE_SIZE = 100
+const LINGER = 1000 * time.Millisecond
+const REFRESH_INTERVAL = 100 * time.Millisecond
 const AML_FILE_PATH = "sample_data_model.aml"
+const PROTO_FILE_PATH = "sample_data_model.desc"
+const PROTO_MESSAGE_NAME = "ezmqx.sample.Robot"
 const TNS_CONFIG_FILE_PATH = "tnsConf.json"
 const NUMBER_OF_EVENTS = 5
+const BYTE_DATA = "raw telemetry payload"
 
 // TODO insert pharos-web-client-ip, pharos-node-ip
 const CONFIG_URL = "http://pharos-node:48098/api/v1/management/device/configuration"
@@ -57,6 +66,8 @@ var RUNNING_APP_INFO_RESPONSE = `{ "description": "services:  system-provisionin
 const TOPIC_DISCOVERY_H_URL = "http://192.168.0.1:80/tns-server/api/v1/tns/topic?name=/topic&hierarchical=yes"
 const TOPIC_DISCOVERY_URL = "http://192.168.0.1:80/tns-server/api/v1/tns/topic?name=/topic&hierarchical=no"
 const VALID_TOPIC_DISCOVERY_RESPONSE = `{ "topics": [  {"name":  "topicName", "datamodel": "GTC_Robot_0.0.1", "endpoint": "localhost:5562", "secured": false } ] }`
+const PATTERN_TOPIC_DISCOVERY_RESPONSE = `{ "topics": [  {"name":  "/topic/a", "datamodel": "GTC_Robot_0.0.1", "endpoint": "localhost:5562", "secured": false }, {"name":  "/topic/a/b", "datamodel": "GTC_Robot_0.0.1", "endpoint": "localhost:5562", "secured": false }, {"name":  "/topic/c", "datamodel": "GTC_Robot_0.0.1", "endpoint": "localhost:5562", "secured": false } ] }`
+const EMPTY_TOPIC_DISCOVERY_RESPONSE = `{ "topics": [] }`
 const INVALID_TOPIC_DISCOVERY_RESPONSE = `{ "topic": [  {"name":  "topicName", "datamodel": "GTC_Robot_0.0.1", "endpoint": "localhost:5562", "secured": false } ] }`
 
 const PUB_TNS_URL = "http://192.168.0.1:80/tns-server/api/v1/tns/topic"
@@ -64,8 +75,33 @@ const VALID_PUB_TNS_RESPONSE = `{ "ka_interval": 200 }`
 
 const SUB_TOPIC_H_URL = "http://192.168.0.1:80/tns-server/api/v1/tns/topic?name=/topic&hierarchical=yes"
 const SUB_TOPIC_RESPONSE = `{ "topics": [  {"name":  "/topic", "datamodel": "GTC_Robot_0.0.1", "endpoint": "localhost:5562", "secured": false } ] }`
+const SUB_TOPIC_MOVED_RESPONSE = `{ "topics": [  {"name":  "/topic", "datamodel": "GTC_Robot_0.0.1", "endpoint": "localhost:5563", "secured": false } ] }`
+const SUB_TOPIC_COMPRESSED_RESPONSE = `{ "topics": [  {"name":  "/topic", "datamodel": "GTC_Robot_0.0.1", "endpoint": "localhost:5563", "secured": false, "compression": "gzip" } ] }`
+const SUB_TOPIC_REMOVED_RESPONSE = `{ "topics": [] }`
 const SUB_TOPIC_URL = "http://192.168.0.1:80/tns-server/api/v1/tns/topic?name=/topic&hierarchical=no"
 
+// TNS server
+const TNS_SERVER_ADDRESS = "127.0.0.1:0"
+const TNS_SERVER_TOPIC = "/api/v1/tns/topic"
+const TNS_SERVER_KEEP_ALIVE = "/api/v1/tns/keepalive"
+const TNS_SERVER_TOPIC_NAME = "/sensor/temperature"
+const TNS_SERVER_REGISTER_PAYLOAD = `{"topic": {"name": "/sensor/temperature", "datamodel": "GTC_Robot_0.0.1", "endpoint": "localhost:5562", "secured": false}}`
+const TNS_SERVER_INVALID_REGISTER_PAYLOAD = `{"topic": {"name": "/sensor/temp$", "datamodel": "GTC_Robot_0.0.1", "endpoint": "localhost:5562", "secured": false}}`
+const TNS_SERVER_KEEP_ALIVE_TIMEOUT = 100 * time.Millisecond
+const TNS_RE_REGISTRATION_TIMEOUT = 5 * time.Second
+
+// Credentials
+const AUTH_TOKEN = "valid-token"
+const EXPIRED_AUTH_TOKEN = "expired-token-0"
+
+// Retry and circuit breaker
+const RETRY_COUNT = 3
+const RETRY_INITIAL_BACKOFF = 10 * time.Millisecond
+const RETRY_MAX_BACKOFF = 40 * time.Millisecond
+const CIRCUIT_FAILURE_THRESHOLD = 2
+const CIRCUIT_OPEN_DURATION = 100 * time.Millisecond
+const RETRY_CANCEL_BACKOFF = 1 * time.Second
+
 // this key only used on unittests
 const SERVER_SECRET_KEY = "[:X%Q3UfY+kv2A^.wv:(qy2E=bk0L][cm=mS3Hcx";
 const SERVER_PUBLIC_KEY = "tXJx&1^QE2g7WCXbF.$$TVP.wCtxwNhR8?iLi&S<";
@@ -100,6 +136,28 @@ func Publish() {
 	Exit_Chan <- true
 }
 
+func PublishBytes() {
+	publisher, errorCode := ezmqx.GetBytePublisher(TOPIC, BYTE_DATA_MODEL, PORT)
+	if errorCode != ezmqx.EZMQX_OK {
+		fmt.Println("Get byte publiser failed")
+		os.Exit(-1)
+	}
+	for i := 0; i < NUMBER_OF_EVENTS; i++ {
+		time.Sleep(1500 * time.Millisecond)
+		result := publisher.Publish([]byte(BYTE_DATA))
+		if result != ezmqx.EZMQX_OK {
+			fmt.Println("Error while publishing")
+		}
+		fmt.Println("Published event result:", result)
+	}
+	result := publisher.Terminate()
+	if result != ezmqx.EZMQX_OK {
+		fmt.Printf("Error while terminating publisher")
+		os.Exit(-1)
+	}
+	Exit_Chan <- true
+}
+
 func GetAMLObject() *aml.AMLObject {
 	// create "Model" data
 	model, _ := aml.CreateAMLData()
@@ -19,6 +19,7 @@ package utils
 
 import (
 	"go/ezmqx"
+	"sync"
 	"time"
 )
 
@@ -27,11 +28,18 @@ type FakeRestClient struct {
 
 var restResponse = make(map[string][]byte)
 
+// Responses are read by background routines [e.g. topic refresh], so they are guarded by mutex.
+var responseMutex = &sync.Mutex{}
+
 func GetRestResponse(url string) []byte {
+	responseMutex.Lock()
+	defer responseMutex.Unlock()
 	return restResponse[url]
 }
 
 func SetRestResponse(url string, payload []byte) {
+	responseMutex.Lock()
+	defer responseMutex.Unlock()
 	restResponse[url] = payload
 }
 
@@ -42,17 +50,17 @@ func GetFakeClient(timeout time.Duration) *FakeRestClient {
 }
 
 func (instance *FakeRestClient) Get(url string) (*ezmqx.RestResponse, ezmqx.EZMQXErrorCode) {
-	return ezmqx.GetRestResponse(200, restResponse[url]), ezmqx.EZMQX_OK
+	return ezmqx.GetRestResponse(200, GetRestResponse(url)), ezmqx.EZMQX_OK
 }
 
 func (instance *FakeRestClient) Put(url string, data []byte) (*ezmqx.RestResponse, ezmqx.EZMQXErrorCode) {
-	return ezmqx.GetRestResponse(200, restResponse[url]), ezmqx.EZMQX_OK
+	return ezmqx.GetRestResponse(200, GetRestResponse(url)), ezmqx.EZMQX_OK
 }
 
 func (instance *FakeRestClient) Post(url string, data []byte) (*ezmqx.RestResponse, ezmqx.EZMQXErrorCode) {
-	return ezmqx.GetRestResponse(201, restResponse[url]), ezmqx.EZMQX_OK
+	return ezmqx.GetRestResponse(201, GetRestResponse(url)), ezmqx.EZMQX_OK
 }
 
 func (instance *FakeRestClient) Delete(url string, data []byte) (*ezmqx.RestResponse, ezmqx.EZMQXErrorCode) {
-	return ezmqx.GetRestResponse(200, restResponse[url]), ezmqx.EZMQX_OK
+	return ezmqx.GetRestResponse(200, GetRestResponse(url)), ezmqx.EZMQX_OK
 }
@@ -0,0 +1,135 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package utils
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"path/filepath"
+	"time"
+)
+
+const TLS_SERVER_NAME = "tns-server"
+
+// Certificates for TLS tests, CA signs both server and client certificates.
+type TestCertificates struct {
+	CAFile          string
+	ClientCertFile  string
+	ClientKeyFile   string
+	ServerTLSConfig *tls.Config
+}
+
+// Creates CA, server and client certificates in given directory.
+// Server requires client certificate signed by the CA.
+func CreateTestCertificates(dir string) (*TestCertificates, error) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	caTemplate := getCertificateTemplate(1, "test-ca")
+	caTemplate.IsCA = true
+	caTemplate.KeyUsage = x509.KeyUsageCertSign
+	caTemplate.BasicConstraintsValid = true
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		return nil, err
+	}
+	caCert, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		return nil, err
+	}
+
+	serverTemplate := getCertificateTemplate(2, TLS_SERVER_NAME)
+	serverTemplate.DNSNames = []string{TLS_SERVER_NAME}
+	serverTemplate.IPAddresses = []net.IP{net.ParseIP(ADDRESS)}
+	serverTemplate.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+	serverCert, err := createSignedCertificate(serverTemplate, caCert, caKey)
+	if err != nil {
+		return nil, err
+	}
+
+	clientTemplate := getCertificateTemplate(3, "test-client")
+	clientTemplate.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	clientCert, err := createSignedCertificate(clientTemplate, caCert, caKey)
+	if err != nil {
+		return nil, err
+	}
+
+	certificates := &TestCertificates{}
+	certificates.CAFile = filepath.Join(dir, "ca.pem")
+	certificates.ClientCertFile = filepath.Join(dir, "client.pem")
+	certificates.ClientKeyFile = filepath.Join(dir, "client-key.pem")
+	err = writePEM(certificates.CAFile, "CERTIFICATE", caDER)
+	if err != nil {
+		return nil, err
+	}
+	err = writePEM(certificates.ClientCertFile, "CERTIFICATE", clientCert.Certificate[0])
+	if err != nil {
+		return nil, err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(clientCert.PrivateKey.(*ecdsa.PrivateKey))
+	if err != nil {
+		return nil, err
+	}
+	err = writePEM(certificates.ClientKeyFile, "EC PRIVATE KEY", keyDER)
+	if err != nil {
+		return nil, err
+	}
+	caPool := x509.NewCertPool()
+	caPool.AddCert(caCert)
+	certificates.ServerTLSConfig = &tls.Config{
+		Certificates: []tls.Certificate{*serverCert},
+		ClientCAs:    caPool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	}
+	return certificates, nil
+}
+
+func getCertificateTemplate(serial int64, commonName string) *x509.Certificate {
+	return &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+}
+
+func createSignedCertificate(template *x509.Certificate, caCert *x509.Certificate, caKey *ecdsa.PrivateKey) (*tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+}
+
+func writePEM(path string, blockType string, der []byte) error {
+	return ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600)
+}
@@ -0,0 +1,47 @@
+/*******************************************************************************
+ * Copyright 2018 Samsung Electronics All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ *******************************************************************************/
+
+package utils
+
+import (
+	"bytes"
+	"crypto/tls"
+	"go/ezmqx_tns"
+	"net/http"
+	"net/http/httptest"
+)
+
+// Starts embedded TNS server with TNS_SERVER_TOPIC_NAME registered.
+// wrap [can be nil] returns handler which serves requests in front of TNS server, e.g. a gateway.
+// Server is started over TLS if tlsConfig is not nil.
+func StartTNSServer(wrap func(tnsServer http.Handler) http.Handler, tlsConfig *tls.Config) *httptest.Server {
+	tnsServer := ezmqx_tns.GetTNSServer(TNS_SERVER_ADDRESS, 1)
+	request := httptest.NewRequest(http.MethodPost, TNS_SERVER_TOPIC, bytes.NewBufferString(TNS_SERVER_REGISTER_PAYLOAD))
+	tnsServer.ServeHTTP(httptest.NewRecorder(), request)
+	var handler http.Handler = tnsServer
+	if nil != wrap {
+		handler = wrap(tnsServer)
+	}
+	server := httptest.NewUnstartedServer(handler)
+	if nil != tlsConfig {
+		server.TLS = tlsConfig
+		server.StartTLS()
+	} else {
+		server.Start()
+	}
+	return server
+}